@@ -3,10 +3,12 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"strings"
 	"syscall"
@@ -30,6 +32,9 @@ type Runner struct {
 	Stdout io.Writer
 	Stderr io.Writer
 	Logger *slog.Logger
+
+	// listDevices overrides the Pulse device query; nil uses audio.ListDevices.
+	listDevices func(context.Context, audio.ClientIdentity) ([]audio.Device, error)
 }
 
 // Execute is the package entrypoint used by cmd/sotto/main.go.
@@ -44,23 +49,26 @@ func (r Runner) Execute(ctx context.Context, args []string) int {
 	if err != nil {
 		fmt.Fprintf(r.Stderr, "error: %v\n\n", err)
 		fmt.Fprint(r.Stderr, cli.HelpText("sotto"))
-		return 2
+		return ExitUsage
 	}
 
 	if parsed.ShowHelp {
 		fmt.Fprint(r.Stdout, cli.HelpText("sotto"))
-		return 0
+		return ExitOK
 	}
 
-	if parsed.Command == cli.CommandVersion {
+	if parsed.Command == cli.CommandVersion && !parsed.Check {
 		fmt.Fprintln(r.Stdout, version.String())
-		return 0
+		return ExitOK
+	}
+	if parsed.Command == cli.CommandPaths {
+		return r.commandPaths(parsed.ConfigPath, parsed.JSON)
 	}
 
 	logRuntime, err := logging.New()
 	if err != nil {
 		fmt.Fprintf(r.Stderr, "error: setup logging: %v\n", err)
-		return 1
+		return ExitRuntime
 	}
 	defer func() { _ = logRuntime.Close() }()
 
@@ -73,7 +81,7 @@ func (r Runner) Execute(ctx context.Context, args []string) int {
 	if err != nil {
 		fmt.Fprintf(r.Stderr, "error: %v\n", err)
 		logger.Error("load config failed", "error", err.Error())
-		return 1
+		return ExitConfig
 	}
 	for _, w := range cfgLoaded.Warnings {
 		msg := w.Message
@@ -83,51 +91,183 @@ func (r Runner) Execute(ctx context.Context, args []string) int {
 		fmt.Fprintf(r.Stderr, "warning: %s\n", msg)
 		logger.Warn("config warning", "line", w.Line, "message", w.Message)
 	}
+	if parsed.Strict && len(cfgLoaded.Warnings) > 0 {
+		fmt.Fprintf(r.Stderr, "error: %d config warning(s) with --strict\n", len(cfgLoaded.Warnings))
+		return ExitUsage
+	}
+
+	if err := applyEndpointOverrides(&cfgLoaded.Config, parsed); err != nil {
+		fmt.Fprintf(r.Stderr, "error: %v\n", err)
+		logger.Error("endpoint override invalid", "error", err.Error())
+		return ExitRuntime
+	}
+	// The overrides apply to this process's config for a session it owns and
+	// are forwarded with the request when another owner is running.
+	overrides := sessionOverrides(parsed)
+	if overrides != nil {
+		pipeline.ApplySessionOverrides(&cfgLoaded.Config, *overrides)
+		if _, err := config.Validate(cfgLoaded.Config); err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			logger.Error("session override invalid", "error", err.Error())
+			return ExitRuntime
+		}
+	}
 
 	if speechPlan, _, err := config.BuildSpeechPhrases(cfgLoaded.Config); err == nil {
 		logger.Debug("speech context plan", "phrase_count", len(speechPlan), "phrases", speechPlan)
 	}
 
+	loggedCfg := config.Redacted(cfgLoaded.Config)
 	logger.Info("command start",
 		"command", parsed.Command,
 		"config", cfgLoaded.Path,
 		"log", logRuntime.Path,
+		"riva_grpc", loggedCfg.RivaGRPC,
+		"riva_http", loggedCfg.RivaHTTP,
 	)
 
 	switch parsed.Command {
 	case cli.CommandDoctor:
+		var fixes []doctor.Check
+		if parsed.Fix {
+			fixes = doctor.Fix(ctx, doctorFixPaths(cfgLoaded))
+			if !cfgLoaded.Exists {
+				// The checks must see the starter config the fix may have written.
+				reloaded, err := config.Load(parsed.ConfigPath)
+				if err != nil {
+					fmt.Fprintf(r.Stderr, "error: %v\n", err)
+					return ExitConfig
+				}
+				if err := applyEndpointOverrides(&reloaded.Config, parsed); err != nil {
+					fmt.Fprintf(r.Stderr, "error: %v\n", err)
+					logger.Error("endpoint override invalid", "error", err.Error())
+					return ExitRuntime
+				}
+				cfgLoaded = reloaded
+			}
+		}
 		report := doctor.Run(cfgLoaded)
-		fmt.Fprintln(r.Stdout, report.String())
+		report.Checks = append(fixes, report.Checks...)
+		fmt.Fprintln(r.Stdout, report.StringColored(colorEnabled(r.Stdout)))
 		if report.OK() {
-			return 0
+			return ExitOK
 		}
-		return 1
+		return ExitRuntime
+	case cli.CommandVersion:
+		return r.commandVersionCheck(ctx, cfgLoaded.Config.Update)
 	case cli.CommandDevices:
-		return r.commandDevices(ctx)
+		return r.commandDevices(ctx, cfgLoaded.Config, parsed.AllDevices, parsed.JSON)
+	case cli.CommandBench:
+		return r.commandBench(ctx, cfgLoaded.Config, logger, parsed)
+	case cli.CommandTestCue:
+		notify := indicator.NewHyprNotify(cfgLoaded.Config.Indicator, audio.IdentityFromConfig(cfgLoaded.Config.Audio), logger)
+		if err := notify.PreviewCue(ctx, parsed.CueKind); err != nil {
+			fmt.Fprintf(r.Stderr, "error: play %s cue: %v\n", parsed.CueKind, err)
+			return ExitRuntime
+		}
+		return ExitOK
+	case cli.CommandMonitor:
+		return r.commandMonitor(ctx)
+	case cli.CommandVocab:
+		return r.commandVocabCheck(cfgLoaded.Config, parsed.VocabFile, parsed.JSON)
 	case cli.CommandStatus:
 		return r.commandStatus(ctx)
+	case cli.CommandLast:
+		return r.commandLast(ctx, parsed.JSON)
 	case cli.CommandStop:
-		return r.forwardOrFail(ctx, "stop")
+		return r.forwardOrFail(ctx, ipc.Request{Command: "stop", NoPaste: parsed.NoPaste})
 	case cli.CommandCancel:
-		return r.forwardOrFail(ctx, "cancel")
+		return r.forwardOrFail(ctx, ipc.Request{Command: "cancel"})
+	case cli.CommandFlush:
+		return r.forwardOrFail(ctx, ipc.Request{Command: "flush"})
 	case cli.CommandToggle:
-		return r.commandToggle(ctx, cfgLoaded.Config, logger)
+		return r.commandToggle(ctx, cfgLoaded, logger, ipc.Request{Command: "toggle", NoPaste: parsed.NoPaste, Overrides: overrides}, parsed.JSON)
+	case cli.CommandPTTStart:
+		return r.commandPTTStart(ctx, cfgLoaded, logger, ipc.Request{Command: "ptt-start", NoPaste: parsed.NoPaste, Overrides: overrides})
+	case cli.CommandDaemon:
+		return r.commandDaemon(ctx, cfgLoaded, logger, parsed.Warm)
+	case cli.CommandWarmupStatus:
+		return r.forwardOrFail(ctx, ipc.Request{Command: "warmup-status"})
+	case cli.CommandPTTStop:
+		return r.forwardOrFail(ctx, ipc.Request{Command: "stop", NoPaste: parsed.NoPaste})
 	default:
 		fmt.Fprintf(r.Stderr, "error: unsupported command %q\n", parsed.Command)
-		return 2
+		return ExitUsage
 	}
 }
 
+// commandVersionCheck prints the build version and whether update.url reports a
+// newer release. It never installs anything.
+func (r Runner) commandVersionCheck(ctx context.Context, cfg config.UpdateConfig) int {
+	fmt.Fprintln(r.Stdout, version.String())
+	if cfg.Offline {
+		fmt.Fprintln(r.Stdout, "update check skipped (update.offline=true)")
+		return ExitOK
+	}
+
+	client := &http.Client{Timeout: 3 * time.Second}
+	latest, err := version.Latest(ctx, client, cfg.URL)
+	if err != nil {
+		fmt.Fprintf(r.Stderr, "error: check for updates: %v\n", err)
+		return ExitRuntime
+	}
+
+	cmp, ok := version.Compare(version.Version, latest)
+	switch {
+	case !ok:
+		fmt.Fprintf(r.Stdout, "latest release is %s (cannot compare with %s)\n", latest, version.Version)
+	case cmp < 0:
+		fmt.Fprintf(r.Stdout, "update available: %s (current %s)\n", latest, version.Version)
+	default:
+		fmt.Fprintf(r.Stdout, "up to date (latest release %s)\n", latest)
+	}
+	return ExitOK
+}
+
+// deviceJSON is one element of `sotto devices --json`.
+type deviceJSON struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Available   bool   `json:"available"`
+	Muted       bool   `json:"muted"`
+	Default     bool   `json:"default"`
+	Monitor     bool   `json:"monitor"`
+}
+
 // commandDevices prints discovered input devices and key availability metadata.
-func (r Runner) commandDevices(ctx context.Context) int {
-	devices, err := audio.ListDevices(ctx)
+// Devices excluded by audio.allow/audio.deny/audio.allow_monitor are hidden
+// unless all is set.
+func (r Runner) commandDevices(ctx context.Context, cfg config.Config, all bool, asJSON bool) int {
+	list := r.listDevices
+	if list == nil {
+		list = audio.ListDevices
+	}
+	devices, err := list(ctx, audio.IdentityFromConfig(cfg.Audio))
 	if err != nil {
 		fmt.Fprintf(r.Stderr, "error: %v\n", err)
-		return 1
+		return ExitRuntime
+	}
+	if !all {
+		devices = audio.FilterDevices(devices, audio.DeviceFilter{Allow: cfg.Audio.Allow, Deny: cfg.Audio.Deny, AllowMonitor: cfg.Audio.AllowMonitor})
+	}
+	if asJSON {
+		out := make([]deviceJSON, 0, len(devices))
+		for _, device := range devices {
+			out = append(out, deviceJSON(device))
+		}
+		if err := json.NewEncoder(r.Stdout).Encode(out); err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			return ExitRuntime
+		}
+		if len(devices) == 0 {
+			return ExitRuntime
+		}
+		return ExitOK
 	}
 	if len(devices) == 0 {
 		fmt.Fprintln(r.Stdout, "no audio devices found")
-		return 1
+		return ExitRuntime
 	}
 
 	for _, device := range devices {
@@ -143,114 +283,149 @@ func (r Runner) commandDevices(ctx context.Context) int {
 		if device.Muted {
 			muted = "yes"
 		}
+		monitor := ""
+		if device.Monitor {
+			monitor = " | monitor=yes"
+		}
 		fmt.Fprintf(
 			r.Stdout,
-			"%s id=%s | description=%q | state=%s | available=%s | muted=%s\n",
+			"%s id=%s | description=%q | state=%s | available=%s | muted=%s%s\n",
 			defaultMark,
 			device.ID,
 			device.Description,
 			device.State,
 			availability,
 			muted,
+			monitor,
 		)
 	}
 
-	return 0
+	return ExitOK
 }
 
+// statusStopped is printed when no owner process is listening, as opposed to
+// "idle" from a responsive owner with no active recording.
+const statusStopped = "stopped"
+
 // commandStatus queries the active owner (if any) and prints session state.
 func (r Runner) commandStatus(ctx context.Context) int {
 	socketPath, err := ipc.RuntimeSocketPath()
 	if err != nil {
-		fmt.Fprintln(r.Stdout, "idle")
-		return 0
+		fmt.Fprintln(r.Stdout, statusStopped)
+		return ExitOK
 	}
 
 	resp, handled, err := tryForward(ctx, socketPath, "status")
-	if handled {
-		if err != nil {
-			fmt.Fprintf(r.Stderr, "error: %v\n", err)
-			return 1
-		}
-		if resp.State == "" {
-			resp.State = "idle"
-		}
-		fmt.Fprintln(r.Stdout, resp.State)
-		return 0
+	if !handled {
+		// Nothing is listening on the socket, so no owner is running.
+		fmt.Fprintln(r.Stdout, statusStopped)
+		return ExitOK
 	}
-
-	fmt.Fprintln(r.Stdout, "idle")
-	return 0
+	if err != nil {
+		fmt.Fprintf(r.Stderr, "error: %v\n", err)
+		return ExitRuntime
+	}
+	if resp.State == "" {
+		resp.State = "idle"
+	}
+	fmt.Fprintln(r.Stdout, resp.State)
+	return ExitOK
 }
 
-// forwardOrFail forwards a command to the active owner and fails when no owner exists.
-func (r Runner) forwardOrFail(ctx context.Context, command string) int {
+// forwardOrFail forwards a command to the active owner and fails with
+// ExitNoOwner when no owner exists.
+func (r Runner) forwardOrFail(ctx context.Context, req ipc.Request) int {
 	socketPath, err := ipc.RuntimeSocketPath()
 	if err != nil {
 		fmt.Fprintf(r.Stderr, "error: %v\n", err)
-		return 1
+		return ExitRuntime
 	}
 
-	resp, handled, err := tryForward(ctx, socketPath, command)
+	resp, handled, err := forwardRequest(ctx, socketPath, req)
 	if !handled {
 		fmt.Fprintf(r.Stderr, "error: no active sotto session\n")
-		return 1
+		return ExitNoOwner
 	}
 	if err != nil {
 		fmt.Fprintf(r.Stderr, "error: %v\n", err)
-		return 1
+		return ExitRuntime
 	}
 	if resp.Message != "" {
 		fmt.Fprintln(r.Stdout, resp.Message)
 	}
-	return 0
+	return ExitOK
 }
 
 // commandToggle starts a new owner session or forwards toggle to an existing owner.
-func (r Runner) commandToggle(ctx context.Context, cfg config.Config, logger *slog.Logger) int {
+// req carries --no-paste and the session overrides; asJSON prints the owner's
+// session result as a toggleReport.
+func (r Runner) commandToggle(ctx context.Context, cfgLoaded config.Loaded, logger *slog.Logger, req ipc.Request, asJSON bool) int {
+	return r.startOrForward(ctx, cfgLoaded, logger, req, 0, asJSON)
+}
+
+// commandPTTStart becomes the owner for a push-to-talk recording. An existing
+// owner treats the forwarded ptt-start as a no-op, so key auto-repeat is safe.
+// The recording ends on its own after session.ptt_timeout_ms if ptt-stop is
+// lost; session.ptt_timeout_action decides whether it is cancelled or committed.
+func (r Runner) commandPTTStart(ctx context.Context, cfgLoaded config.Loaded, logger *slog.Logger, req ipc.Request) int {
+	limit := time.Duration(cfgLoaded.Config.Session.PTTTimeoutMS) * time.Millisecond
+	return r.startOrForward(ctx, cfgLoaded, logger, req, limit, false)
+}
+
+// startOrForward forwards req to an existing owner, or becomes the owner and
+// runs one session when none is listening. maxRecording of zero is unbounded.
+func (r Runner) startOrForward(ctx context.Context, cfgLoaded config.Loaded, logger *slog.Logger, req ipc.Request, maxRecording time.Duration, asJSON bool) int {
+	cfg := cfgLoaded.Config
+	noPaste := req.NoPaste
 	socketPath, err := ipc.RuntimeSocketPath()
 	if err != nil {
 		fmt.Fprintf(r.Stderr, "error: %v\n", err)
-		return 1
+		return ExitRuntime
 	}
 
-	resp, handled, err := tryForward(ctx, socketPath, "toggle")
+	resp, handled, err := forwardRequest(ctx, socketPath, req)
 	if handled {
 		if err != nil {
 			fmt.Fprintf(r.Stderr, "error: %v\n", err)
-			return 1
+			return ExitRuntime
 		}
+		logger.Debug("forwarded to owner", "command", req.Command, "owner_pid", resp.OwnerPID)
 		if resp.Message != "" {
 			fmt.Fprintln(r.Stdout, resp.Message)
 		}
-		return 0
+		return ExitOK
 	}
 
 	listener, err := ipc.Acquire(ctx, socketPath, 180*time.Millisecond, 8, nil)
 	if err != nil {
 		if errors.Is(err, ipc.ErrAlreadyRunning) {
-			resp, _, forwardErr := tryForward(ctx, socketPath, "toggle")
+			logger.Info("owner appeared during acquire; forwarding", "command", req.Command, "owner_pid", ownerPID(err))
+			resp, _, forwardErr := forwardRequest(ctx, socketPath, req)
 			if forwardErr != nil {
 				fmt.Fprintf(r.Stderr, "error: %v\n", forwardErr)
-				return 1
+				return ExitRuntime
 			}
 			if resp.Message != "" {
 				fmt.Fprintln(r.Stdout, resp.Message)
 			}
-			return 0
+			return ExitOK
 		}
 		fmt.Fprintf(r.Stderr, "error: %v\n", err)
-		return 1
+		return ExitRuntime
 	}
 	defer func() {
 		_ = listener.Close()
 		_ = os.Remove(socketPath)
 	}()
 
+	if noPaste {
+		cfg.Paste.Enable = false
+	}
 	transcriber := pipeline.NewTranscriber(cfg, logger)
-	committer := output.NewCommitter(cfg, logger)
-	indicatorCtl := indicator.NewHyprNotify(cfg.Indicator, logger)
-	controller := session.NewController(logger, transcriber, committer, indicatorCtl)
+	transcriber.Prewarm(ctx)
+	controller, committer := newOwnerController(cfg, logger, transcriber, r.Stderr)
+	defer committer.Wait()
+	controller.SetMaxRecording(maxRecording)
 
 	serverCtx, serverCancel := context.WithCancel(ctx)
 	defer serverCancel()
@@ -261,46 +436,230 @@ func (r Runner) commandToggle(ctx context.Context, cfg config.Config, logger *sl
 	}()
 
 	result := controller.Run(ctx)
+	result.ConfigPath = cfgLoaded.Path
 	serverCancel()
 	if serverErr := <-serverErrCh; serverErr != nil {
 		fmt.Fprintf(r.Stderr, "error: ipc server failed: %v\n", serverErr)
-		return 1
+		return ExitRuntime
 	}
 
-	logSessionResult(logger, result)
+	logSessionResult(logger, result, cfg.Debug.MetricsFile)
+	return r.printSessionResult(result, asJSON)
+}
+
+// toggleReport is the `sotto toggle --json` payload.
+type toggleReport struct {
+	Transcript string `json:"transcript"`
+	Device     string `json:"device"`
+	Bytes      int64  `json:"bytes"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Cancelled  bool   `json:"cancelled"`
+	// RequestID and ModelVersion are omitted when Riva sends no trailers.
+	RequestID    string `json:"request_id,omitempty"`
+	ModelVersion string `json:"model_version,omitempty"`
+}
+
+// printSessionResult reports an owner session's outcome: the trimmed
+// transcript, "cancelled", or a toggleReport when asJSON is set. Errors always
+// go to stderr.
+func (r Runner) printSessionResult(result session.Result, asJSON bool) int {
+	if result.Err != nil && !result.Cancelled {
+		fmt.Fprintf(r.Stderr, "error: %v\n", result.Err)
+		return ExitRuntime
+	}
+
+	transcript := strings.TrimSpace(result.Transcript)
+	if asJSON {
+		report := toggleReport{
+			Device:       result.AudioDevice,
+			Bytes:        result.BytesCaptured,
+			LatencyMS:    result.GRPCLatency.Milliseconds(),
+			Cancelled:    result.Cancelled,
+			RequestID:    result.RequestID,
+			ModelVersion: result.ModelVersion,
+		}
+		if !result.Cancelled {
+			report.Transcript = transcript
+		}
+		if err := json.NewEncoder(r.Stdout).Encode(report); err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			return ExitRuntime
+		}
+		return ExitOK
+	}
 
 	if result.Cancelled {
 		fmt.Fprintln(r.Stdout, "cancelled")
-		return 0
+		return ExitOK
 	}
-	if result.Err != nil {
-		fmt.Fprintf(r.Stderr, "error: %v\n", result.Err)
-		return 1
+	if transcript != "" {
+		fmt.Fprintln(r.Stdout, transcript)
 	}
-	if strings.TrimSpace(result.Transcript) != "" {
-		fmt.Fprintln(r.Stdout, strings.TrimSpace(result.Transcript))
+	return ExitOK
+}
+
+// commandDaemon becomes a long-lived owner: toggle and ptt-start forwarded
+// while idle begin a new session instead of finding no owner. It exits after
+// owner.idle_timeout_ms without a session, or when ctx ends (SIGINT/SIGTERM).
+// warm keeps a pre-warmed Riva connection between sessions, re-dialed after
+// each one since a session's stream consumes its connection.
+func (r Runner) commandDaemon(ctx context.Context, cfgLoaded config.Loaded, logger *slog.Logger, warm bool) int {
+	cfg := cfgLoaded.Config
+	socketPath, err := ipc.RuntimeSocketPath()
+	if err != nil {
+		fmt.Fprintf(r.Stderr, "error: %v\n", err)
+		return ExitRuntime
 	}
 
-	return 0
+	listener, err := ipc.Acquire(ctx, socketPath, 180*time.Millisecond, 8, nil)
+	if err != nil {
+		if errors.Is(err, ipc.ErrAlreadyRunning) {
+			if pid := ownerPID(err); pid > 0 {
+				fmt.Fprintf(r.Stderr, "error: a sotto owner is already running (pid %d)\n", pid)
+				return ExitRuntime
+			}
+			fmt.Fprintln(r.Stderr, "error: a sotto owner is already running")
+			return ExitRuntime
+		}
+		fmt.Fprintf(r.Stderr, "error: %v\n", err)
+		return ExitRuntime
+	}
+	defer func() {
+		_ = listener.Close()
+		_ = os.Remove(socketPath)
+	}()
+
+	transcriber := pipeline.NewTranscriber(cfg, logger)
+	controller, committer := newOwnerController(cfg, logger, transcriber, r.Stderr)
+	defer committer.Wait()
+	controller.SetPTTTimeout(time.Duration(cfg.Session.PTTTimeoutMS) * time.Millisecond)
+
+	serverCtx, serverCancel := context.WithCancel(ctx)
+	defer serverCancel()
+	if warm {
+		transcriber.Prewarm(serverCtx)
+		defer func() { _ = transcriber.Cancel(context.Background()) }()
+	}
+
+	serverErrCh := make(chan error, 1)
+	go func() {
+		serverErrCh <- ipc.Serve(serverCtx, listener, controller)
+	}()
+
+	idleTimeout := time.Duration(cfg.Owner.IdleTimeoutMS) * time.Millisecond
+	controller.RunDaemon(serverCtx, idleTimeout, func(result session.Result) {
+		result.ConfigPath = cfgLoaded.Path
+		logSessionResult(logger, result, cfg.Debug.MetricsFile)
+		if warm {
+			transcriber.Prewarm(serverCtx)
+		}
+	})
+	serverCancel()
+	if serverErr := <-serverErrCh; serverErr != nil {
+		fmt.Fprintf(r.Stderr, "error: ipc server failed: %v\n", serverErr)
+		return ExitRuntime
+	}
+	return ExitOK
+}
+
+// newOwnerController wires the committer and indicator around transcriber.
+// Commit warnings are also printed to stderr. The committer is returned so
+// the owner can wait for pending clipboard restores before it exits.
+func newOwnerController(cfg config.Config, logger *slog.Logger, transcriber *pipeline.Transcriber, stderr io.Writer) (*session.Controller, *output.Committer) {
+	committer := output.NewCommitter(cfg, logger)
+	committer.SetWarningOutput(stderr)
+	if cfg.Output.History {
+		if path, err := historyLogPath(); err == nil {
+			committer.SetHistoryPath(path)
+		} else if logger != nil {
+			logger.Warn("transcript history disabled", "error", err.Error())
+		}
+	}
+	indicatorCtl := indicator.NewHyprNotify(cfg.Indicator, audio.IdentityFromConfig(cfg.Audio), logger)
+	if cfg.Indicator.ShowInterim {
+		transcriber.SetInterimSink(func(text string) {
+			indicatorCtl.ShowInterim(context.Background(), text)
+		})
+	}
+	controller := session.NewController(logger, transcriber, committer, indicatorCtl)
+	controller.SetIdempotentStop(cfg.Session.IdempotentStop)
+	controller.SetNoAudioAsError(cfg.Session.NoAudioAction == "error")
+	controller.SetTimeoutCommits(cfg.Session.PTTTimeoutAction == "commit")
+	controller.SetTranscribingDelay(time.Duration(cfg.Indicator.TranscribingDelayMS) * time.Millisecond)
+	controller.SetMinCommitChars(cfg.Transcript.MinCommitChars)
+	if cfg.Session.ConfirmCancel {
+		controller.SetConfirmCancel(session.ConfirmCancelWindow)
+	}
+	return controller, committer
+}
+
+// applyEndpointOverrides replaces configured Riva endpoints with CLI flag
+// values and validates the result; without either flag it leaves cfg alone.
+func applyEndpointOverrides(cfg *config.Config, parsed cli.Parsed) error {
+	if parsed.RivaGRPC == "" && parsed.RivaHTTP == "" {
+		return nil
+	}
+	if parsed.RivaGRPC != "" {
+		cfg.RivaGRPC = parsed.RivaGRPC
+	}
+	if parsed.RivaHTTP != "" {
+		cfg.RivaHTTP = parsed.RivaHTTP
+	}
+	_, err := config.Validate(*cfg)
+	return err
 }
 
-// logSessionResult writes normalized session metrics into the runtime logger.
-func logSessionResult(logger *slog.Logger, result session.Result) {
+// sessionOverrides collects the --punctuation, --code, --model, --vocab, and
+// --phrase flags, or returns nil when none is set.
+func sessionOverrides(parsed cli.Parsed) *ipc.SessionOverrides {
+	if parsed.Punctuation == "" && !parsed.Code && parsed.Model == "" && parsed.Vocab == nil && parsed.Phrases == nil {
+		return nil
+	}
+	overrides := &ipc.SessionOverrides{
+		Punctuation: parsed.Punctuation,
+		Code:        parsed.Code,
+		Model:       parsed.Model,
+		Vocab:       parsed.Vocab,
+	}
+	for _, phrase := range parsed.Phrases {
+		overrides.Phrases = append(overrides.Phrases, ipc.SessionPhrase{Phrase: phrase.Term, Boost: phrase.Boost})
+	}
+	return overrides
+}
+
+// logSessionResult writes normalized session metrics into the runtime logger
+// and, when metricsFile is set, into a Prometheus textfile.
+func logSessionResult(logger *slog.Logger, result session.Result, metricsFile string) {
+	if metricsFile != "" {
+		if err := writeSessionMetrics(metricsFile, result); err != nil && logger != nil {
+			logger.Warn("write metrics file failed", "path", metricsFile, "error", err.Error())
+		}
+	}
 	if logger == nil {
 		return
 	}
 	fields := []any{
+		"session_id", result.ID,
 		"state", result.State,
 		"cancelled", result.Cancelled,
 		"started_at", result.StartedAt.Format(time.RFC3339Nano),
 		"finished_at", result.FinishedAt.Format(time.RFC3339Nano),
 		"duration_ms", result.FinishedAt.Sub(result.StartedAt).Milliseconds(),
 		"audio_device", result.AudioDevice,
+		"model", result.Model,
+		"language", result.LanguageCode,
+		"config_path", result.ConfigPath,
 		"bytes_captured", result.BytesCaptured,
 		"transcript_length", len(result.Transcript),
 		"grpc_latency_ms", result.GRPCLatency.Milliseconds(),
 		"focused_monitor", result.FocusedMonitor,
 	}
+	if result.RequestID != "" {
+		fields = append(fields, "riva_request_id", result.RequestID)
+	}
+	if result.ModelVersion != "" {
+		fields = append(fields, "riva_model_version", result.ModelVersion)
+	}
 
 	if result.Err != nil {
 		logger.Error("session failed", append(fields, "error", result.Err.Error())...)
@@ -313,7 +672,13 @@ func logSessionResult(logger *slog.Logger, result session.Result) {
 //
 // handled=false means there was no active owner to handle the request.
 func tryForward(ctx context.Context, socketPath string, command string) (ipc.Response, bool, error) {
-	resp, err := ipc.Send(ctx, socketPath, ipc.Request{Command: command}, 220*time.Millisecond)
+	return forwardRequest(ctx, socketPath, ipc.Request{Command: command})
+}
+
+// forwardRequest is tryForward for requests that carry options beyond the command.
+func forwardRequest(ctx context.Context, socketPath string, req ipc.Request) (ipc.Response, bool, error) {
+	command := req.Command
+	resp, err := ipc.Send(ctx, socketPath, req, 220*time.Millisecond)
 	if err == nil {
 		if resp.OK {
 			return resp, true, nil
@@ -331,6 +696,15 @@ func tryForward(ctx context.Context, socketPath string, command string) (ipc.Res
 	return ipc.Response{}, true, fmt.Errorf("forward command %q: %w", command, err)
 }
 
+// ownerPID extracts the owner PID reported by Acquire, or zero when unknown.
+func ownerPID(err error) int {
+	var running *ipc.AlreadyRunningError
+	if errors.As(err, &running) {
+		return running.OwnerPID
+	}
+	return 0
+}
+
 // isSocketMissing reports whether forwarding failed because the owner socket is absent.
 func isSocketMissing(err error) bool {
 	if err == nil {
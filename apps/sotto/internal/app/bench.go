@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/rbright/sotto/internal/audio"
+	"github.com/rbright/sotto/internal/cli"
+	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/pipeline"
+)
+
+// defaultBenchDuration is how long `sotto bench` captures without --duration.
+const defaultBenchDuration = 5 * time.Second
+
+// benchReport is the `sotto bench --json` payload. Millisecond fields are
+// offsets from the start of the run; first_partial_ms is null when Riva
+// returned no hypothesis.
+type benchReport struct {
+	Source         string `json:"source"`
+	AudioMS        int64  `json:"audio_ms"`
+	DialMS         int64  `json:"dial_ms"`
+	FirstPartialMS *int64 `json:"first_partial_ms"`
+	FinalMS        int64  `json:"final_ms"`
+	TotalMS        int64  `json:"total_ms"`
+	Transcript     string `json:"transcript"`
+}
+
+// commandBench runs one uncommitted transcription and prints its latency
+// breakdown, for tuning a Riva deployment.
+func (r Runner) commandBench(ctx context.Context, cfg config.Config, logger *slog.Logger, parsed cli.Parsed) int {
+	opts := pipeline.BenchOptions{Duration: parsed.BenchDuration}
+	if parsed.BenchFile != "" {
+		file, err := os.Open(parsed.BenchFile)
+		if err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			return ExitRuntime
+		}
+		pcm, err := audio.ReadPCM16WAV(file)
+		_ = file.Close()
+		if err != nil {
+			fmt.Fprintf(r.Stderr, "error: %s: %v\n", parsed.BenchFile, err)
+			return ExitRuntime
+		}
+		opts.PCM = pcm
+		opts.FileName = parsed.BenchFile
+	} else if opts.Duration == 0 {
+		opts.Duration = defaultBenchDuration
+	}
+
+	result, err := pipeline.NewTranscriber(cfg, logger).Bench(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(r.Stderr, "error: bench: %v\n", err)
+		logger.Error("bench failed", "error", err.Error())
+		return ExitRuntime
+	}
+	logger.Info("bench complete",
+		"source", result.Source,
+		"dial_ms", result.Dial.Milliseconds(),
+		"first_partial_ms", result.FirstPartial.Milliseconds(),
+		"final_ms", result.Final.Milliseconds(),
+		"total_ms", result.Total.Milliseconds(),
+	)
+
+	if parsed.JSON {
+		if err := json.NewEncoder(r.Stdout).Encode(newBenchReport(result)); err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			return ExitRuntime
+		}
+		return ExitOK
+	}
+
+	firstPartial := "-"
+	if result.FirstPartial > 0 {
+		firstPartial = formatBenchDuration(result.FirstPartial)
+	}
+	for _, row := range []struct{ name, value string }{
+		{"source", result.Source},
+		{"audio", formatBenchDuration(result.Audio)},
+		{"dial", formatBenchDuration(result.Dial)},
+		{"first partial", firstPartial},
+		{"final", formatBenchDuration(result.Final)},
+		{"total", formatBenchDuration(result.Total)},
+		{"transcript", result.Transcript},
+	} {
+		fmt.Fprintf(r.Stdout, "%-13s %s\n", row.name, row.value)
+	}
+	return ExitOK
+}
+
+// newBenchReport converts a bench result to its JSON payload.
+func newBenchReport(result pipeline.BenchResult) benchReport {
+	report := benchReport{
+		Source:     result.Source,
+		AudioMS:    result.Audio.Milliseconds(),
+		DialMS:     result.Dial.Milliseconds(),
+		FinalMS:    result.Final.Milliseconds(),
+		TotalMS:    result.Total.Milliseconds(),
+		Transcript: result.Transcript,
+	}
+	if result.FirstPartial > 0 {
+		ms := result.FirstPartial.Milliseconds()
+		report.FirstPartialMS = &ms
+	}
+	return report
+}
+
+// formatBenchDuration prints d rounded to the millisecond.
+func formatBenchDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorEnabledRequiresTerminal(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	require.False(t, colorEnabled(&bytes.Buffer{}))
+
+	devNull, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer devNull.Close()
+	require.False(t, colorEnabled(devNull))
+}
+
+func TestColorEnabledOnTerminalUnlessNoColor(t *testing.T) {
+	// The pty master is a terminal, so it stands in for an interactive stdout.
+	pty, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	defer pty.Close()
+
+	t.Setenv("NO_COLOR", "")
+	require.True(t, colorEnabled(pty))
+
+	t.Setenv("NO_COLOR", "1")
+	require.False(t, colorEnabled(pty))
+}
@@ -0,0 +1,21 @@
+package app
+
+// Exit codes returned by Execute. Scripts may branch on them, so existing
+// values must not change; add new codes at the end instead.
+const (
+	// ExitOK means the command succeeded.
+	ExitOK = 0
+	// ExitRuntime covers failures while running a command: Riva or audio
+	// errors, a failed session, doctor checks that did not pass, a second
+	// daemon while an owner is running, or an invalid --vocab, --model, or
+	// --riva-* override.
+	ExitRuntime = 1
+	// ExitUsage reports invalid arguments or config warnings under --strict.
+	ExitUsage = 2
+	// ExitConfig reports a config file that could not be read, parsed, or
+	// validated.
+	ExitConfig = 3
+	// ExitNoOwner reports that stop, cancel, flush, ptt-stop, or
+	// warmup-status found no running owner to forward to.
+	ExitNoOwner = 4
+)
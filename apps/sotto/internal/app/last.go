@@ -0,0 +1,107 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/ipc"
+	"github.com/rbright/sotto/internal/output"
+)
+
+// errNoTranscript reports that neither the owner nor the history log has a transcript.
+var errNoTranscript = errors.New("no transcript available")
+
+// lastTranscript is the `sotto last --json` payload.
+type lastTranscript struct {
+	Transcript string `json:"transcript"`
+	Source     string `json:"source"`
+}
+
+// commandLast prints the most recently committed transcript, asking the active
+// owner first and falling back to the history log when no owner has one.
+func (r Runner) commandLast(ctx context.Context, asJSON bool) int {
+	last, err := resolveLastTranscript(ctx)
+	if err != nil {
+		fmt.Fprintf(r.Stderr, "error: %v\n", err)
+		return ExitRuntime
+	}
+
+	if asJSON {
+		if err := json.NewEncoder(r.Stdout).Encode(last); err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			return ExitRuntime
+		}
+		return ExitOK
+	}
+	fmt.Fprintln(r.Stdout, strings.TrimSpace(last.Transcript))
+	return ExitOK
+}
+
+// resolveLastTranscript queries the owner over IPC, then the history log.
+func resolveLastTranscript(ctx context.Context) (lastTranscript, error) {
+	if socketPath, err := ipc.RuntimeSocketPath(); err == nil {
+		resp, handled, err := tryForward(ctx, socketPath, "last")
+		if handled && err == nil && resp.Message != "" {
+			return lastTranscript{Transcript: resp.Message, Source: "owner"}, nil
+		}
+	}
+
+	path, err := historyLogPath()
+	if err != nil {
+		return lastTranscript{}, err
+	}
+	transcript, err := readLastHistoryEntry(path)
+	if err != nil {
+		return lastTranscript{}, err
+	}
+	return lastTranscript{Transcript: transcript, Source: "history"}, nil
+}
+
+// historyLogPath returns the transcript history log location under the state dir.
+func historyLogPath() (string, error) {
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, "history.jsonl"), nil
+}
+
+// readLastHistoryEntry returns the newest non-empty transcript in the history log.
+// Malformed lines are skipped so a torn final write does not hide earlier entries.
+func readLastHistoryEntry(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", errNoTranscript
+		}
+		return "", fmt.Errorf("open history log: %w", err)
+	}
+	defer file.Close()
+
+	var last string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry output.HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if strings.TrimSpace(entry.Transcript) != "" {
+			last = entry.Transcript
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read history log: %w", err)
+	}
+	if last == "" {
+		return "", errNoTranscript
+	}
+	return last, nil
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rbright/sotto/internal/session"
+)
+
+// latencyBuckets are the gRPC finalization latency histogram upper bounds, in seconds.
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// writeSessionMetrics folds one session result into the Prometheus textfile at
+// path. Each owner is a separate process, so counters are carried forward by
+// re-reading the previous file before rewriting it.
+func writeSessionMetrics(path string, result session.Result) error {
+	samples, err := readMetricSamples(path)
+	if err != nil {
+		return err
+	}
+
+	samples["sotto_sessions_total"]++
+	switch {
+	case result.Cancelled:
+		samples["sotto_sessions_cancelled_total"]++
+	case errors.Is(result.Err, session.ErrEmptyTranscript):
+		samples["sotto_empty_transcripts_total"]++
+	case result.Err != nil:
+		samples["sotto_session_failures_total"]++
+	}
+	samples["sotto_bytes_captured_total"] += float64(result.BytesCaptured)
+
+	if result.GRPCLatency > 0 {
+		latency := result.GRPCLatency.Seconds()
+		for _, le := range latencyBuckets {
+			if latency <= le {
+				samples[latencyBucketKey(formatBound(le))]++
+			}
+		}
+		samples[latencyBucketKey("+Inf")]++
+		samples["sotto_grpc_latency_seconds_sum"] += latency
+		samples["sotto_grpc_latency_seconds_count"]++
+	}
+
+	return writeFileAtomic(path, renderMetrics(samples))
+}
+
+// readMetricSamples parses `name{labels} value` lines from an existing textfile.
+// A missing file starts every counter at zero.
+func readMetricSamples(path string) (map[string]float64, error) {
+	samples := make(map[string]float64)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return samples, nil
+		}
+		return nil, fmt.Errorf("read metrics file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.LastIndexByte(line, ' ')
+		if idx < 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(line[idx+1:], 64)
+		if err != nil {
+			continue
+		}
+		samples[line[:idx]] = value
+	}
+	return samples, nil
+}
+
+// renderMetrics formats samples in the Prometheus text exposition format.
+func renderMetrics(samples map[string]float64) []byte {
+	var buf bytes.Buffer
+	counter := func(name, help string) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		fmt.Fprintf(&buf, "%s %s\n", name, formatValue(samples[name]))
+	}
+	counter("sotto_sessions_total", "Dictation sessions completed by an owner process.")
+	counter("sotto_session_failures_total", "Sessions that ended with an error other than an empty transcript.")
+	counter("sotto_empty_transcripts_total", "Sessions that produced no speech.")
+	counter("sotto_sessions_cancelled_total", "Sessions cancelled before commit.")
+	counter("sotto_bytes_captured_total", "PCM bytes captured across sessions.")
+
+	const histogram = "sotto_grpc_latency_seconds"
+	fmt.Fprintf(&buf, "# HELP %s Time from stop to the final Riva transcript.\n# TYPE %s histogram\n", histogram, histogram)
+	for _, le := range latencyBuckets {
+		key := latencyBucketKey(formatBound(le))
+		fmt.Fprintf(&buf, "%s %s\n", key, formatValue(samples[key]))
+	}
+	inf := latencyBucketKey("+Inf")
+	fmt.Fprintf(&buf, "%s %s\n", inf, formatValue(samples[inf]))
+	fmt.Fprintf(&buf, "%s_sum %s\n", histogram, formatValue(samples[histogram+"_sum"]))
+	fmt.Fprintf(&buf, "%s_count %s\n", histogram, formatValue(samples[histogram+"_count"]))
+	return buf.Bytes()
+}
+
+func latencyBucketKey(le string) string {
+	return fmt.Sprintf(`sotto_grpc_latency_seconds_bucket{le="%s"}`, le)
+}
+
+func formatBound(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writeFileAtomic replaces path via rename so collectors never read a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create metrics dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create metrics temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace metrics file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rbright/sotto/internal/ipc"
+)
+
+// monitorInterval is how often `sotto monitor` polls the owner and redraws.
+const monitorInterval = 250 * time.Millisecond
+
+// monitorBytesPerSecond converts captured 16 kHz mono s16 PCM to seconds.
+const monitorBytesPerSecond = 32000
+
+// ANSI sequences used by the monitor's full-frame redraw.
+const (
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+)
+
+// monitorSnapshot is one poll of the owner for `sotto monitor`.
+type monitorSnapshot struct {
+	// Running is false when no owner is listening on the socket.
+	Running bool
+	// Outdated marks an owner that predates the partial command, so its
+	// progress cannot be shown.
+	Outdated bool
+	State    string
+	Elapsed  time.Duration
+	Interim  string
+	Bytes    int64
+	// Err is a transient poll failure shown in place of the session.
+	Err error
+}
+
+// commandMonitor redraws live owner state a few times per second until ctx
+// ends (Ctrl-C). When stdout is not a terminal it prints one plain line per
+// change instead of redrawing.
+func (r Runner) commandMonitor(ctx context.Context) int {
+	socketPath, err := ipc.RuntimeSocketPath()
+	if err != nil {
+		fmt.Fprintf(r.Stderr, "error: %v\n", err)
+		return ExitRuntime
+	}
+
+	redraw := isTerminal(r.Stdout)
+	if redraw {
+		fmt.Fprint(r.Stdout, ansiHideCursor)
+		defer fmt.Fprint(r.Stdout, ansiShowCursor)
+	}
+
+	ticker := time.NewTicker(monitorInterval)
+	defer ticker.Stop()
+	lastLine := ""
+	for {
+		snapshot := pollMonitor(ctx, socketPath)
+		// A poll cut short by Ctrl-C would only draw a misleading frame.
+		if ctx.Err() == nil {
+			if redraw {
+				writeMonitorFrame(r.Stdout, snapshot)
+			} else if line := monitorLine(snapshot); line != lastLine {
+				fmt.Fprintln(r.Stdout, line)
+				lastLine = line
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if redraw {
+				fmt.Fprintln(r.Stdout)
+			}
+			return ExitOK
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollMonitor asks the owner for live progress. An owner without the partial
+// command is reported as outdated rather than guessed at from its status.
+func pollMonitor(ctx context.Context, socketPath string) monitorSnapshot {
+	resp, handled, err := forwardRequest(ctx, socketPath, ipc.Request{Command: "partial"})
+	if !handled {
+		return monitorSnapshot{}
+	}
+	if err != nil && strings.HasPrefix(resp.Error, "unknown command") {
+		return monitorSnapshot{Running: true, Outdated: true}
+	}
+	if err != nil {
+		return monitorSnapshot{Running: true, Err: err}
+	}
+	state := resp.State
+	if state == "" {
+		state = "idle"
+	}
+	return monitorSnapshot{
+		Running: true,
+		State:   state,
+		Elapsed: time.Duration(resp.ElapsedMS) * time.Millisecond,
+		Interim: resp.Message,
+		Bytes:   resp.Bytes,
+	}
+}
+
+// writeMonitorFrame clears the terminal and draws snapshot.
+func writeMonitorFrame(w io.Writer, snapshot monitorSnapshot) {
+	fmt.Fprint(w, ansiClear+renderMonitor(snapshot))
+}
+
+// renderMonitor formats snapshot as the monitor's frame, without ANSI
+// control sequences.
+func renderMonitor(snapshot monitorSnapshot) string {
+	return "sotto monitor (Ctrl-C to exit)\n\n" + renderMonitorBody(snapshot)
+}
+
+// monitorLine formats snapshot as one line for output that is not a
+// terminal, e.g. "state: recording, elapsed: 0:02.5, ...".
+func monitorLine(snapshot monitorSnapshot) string {
+	lines := strings.Split(strings.TrimSuffix(renderMonitorBody(snapshot), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, ", ")
+}
+
+// renderMonitorBody formats the session part of snapshot, one field per line.
+func renderMonitorBody(snapshot monitorSnapshot) string {
+	var b strings.Builder
+	switch {
+	case !snapshot.Running:
+		b.WriteString("no session\n")
+		return b.String()
+	case snapshot.Err != nil:
+		fmt.Fprintf(&b, "error: %v\n", snapshot.Err)
+		return b.String()
+	case snapshot.Outdated:
+		b.WriteString("state:   unknown (owner too old)\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "state:   %s\n", snapshot.State)
+	if snapshot.Elapsed <= 0 {
+		b.WriteString("no session\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "elapsed: %s\n", formatMonitorElapsed(snapshot.Elapsed))
+	fmt.Fprintf(&b, "audio:   %.1fs (%d bytes)\n", float64(snapshot.Bytes)/monitorBytesPerSecond, snapshot.Bytes)
+	interim := strings.Join(strings.Fields(snapshot.Interim), " ")
+	if interim == "" {
+		interim = "-"
+	}
+	fmt.Fprintf(&b, "interim: %s\n", interim)
+	return b.String()
+}
+
+// formatMonitorElapsed renders d as m:ss.t.
+func formatMonitorElapsed(d time.Duration) string {
+	d = d.Truncate(100 * time.Millisecond)
+	minutes := int(d / time.Minute)
+	seconds := d % time.Minute
+	return fmt.Sprintf("%d:%04.1f", minutes, seconds.Seconds())
+}
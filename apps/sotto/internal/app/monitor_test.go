@@ -0,0 +1,85 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rbright/sotto/internal/ipc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMonitorSnapshot(t *testing.T) {
+	got := renderMonitor(monitorSnapshot{
+		Running: true,
+		State:   "recording",
+		Elapsed: 75*time.Second + 340*time.Millisecond,
+		Interim: "hello\nthere  world",
+		Bytes:   96000,
+	})
+	require.Equal(t, "sotto monitor (Ctrl-C to exit)\n\n"+
+		"state:   recording\n"+
+		"elapsed: 1:15.3\n"+
+		"audio:   3.0s (96000 bytes)\n"+
+		"interim: hello there world\n", got)
+}
+
+func TestRenderMonitorWithoutSession(t *testing.T) {
+	require.Equal(t, "sotto monitor (Ctrl-C to exit)\n\nno session\n", renderMonitor(monitorSnapshot{}))
+	require.Equal(t, "sotto monitor (Ctrl-C to exit)\n\nstate:   idle\nno session\n", renderMonitor(monitorSnapshot{Running: true, State: "idle"}))
+}
+
+func TestMonitorLine(t *testing.T) {
+	require.Equal(t, "no session", monitorLine(monitorSnapshot{}))
+	require.Equal(t, "state: idle, no session", monitorLine(monitorSnapshot{Running: true, State: "idle"}))
+	require.Equal(t, "state: unknown (owner too old)", monitorLine(monitorSnapshot{Running: true, Outdated: true}))
+}
+
+func TestRunnerMonitorNoOwnerShowsNoSession(t *testing.T) {
+	paths := setupRunnerEnv(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*monitorInterval/2)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	runner := Runner{Stdout: &stdout, Stderr: &stderr}
+	require.Equal(t, ExitOK, runner.Execute(ctx, []string{"--config", paths.configPath, "monitor"}), stderr.String())
+	require.Equal(t, "no session\n", stdout.String(), "output that is not a terminal gets plain lines")
+}
+
+func TestRunnerMonitorRendersOwnerProgress(t *testing.T) {
+	paths := setupRunnerEnv(t)
+	shutdown := startIPCServerForRunnerTest(t, filepath.Join(paths.runtimeDir, "sotto.sock"), func(_ context.Context, req ipc.Request) ipc.Response {
+		if req.Command != "partial" {
+			return ipc.Response{OK: false, Error: "unexpected command " + req.Command}
+		}
+		return ipc.Response{OK: true, State: "recording", Message: "live words", ElapsedMS: 2500, Bytes: 64000}
+	})
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*monitorInterval/2)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	runner := Runner{Stdout: &stdout, Stderr: &stderr}
+	require.Equal(t, ExitOK, runner.Execute(ctx, []string{"--config", paths.configPath, "monitor"}), stderr.String())
+	require.Equal(t, "state: recording, elapsed: 0:02.5, audio: 2.0s (64000 bytes), interim: live words\n", stdout.String())
+}
+
+func TestPollMonitorReportsOlderOwnersAsOutdated(t *testing.T) {
+	paths := setupRunnerEnv(t)
+	socketPath := filepath.Join(paths.runtimeDir, "sotto.sock")
+	shutdown := startIPCServerForRunnerTest(t, socketPath, func(_ context.Context, req ipc.Request) ipc.Response {
+		if req.Command == "status" {
+			return ipc.Response{OK: true, State: "recording", Message: "status"}
+		}
+		return ipc.Response{OK: false, State: "recording", Error: "unknown command: " + req.Command}
+	})
+	defer shutdown()
+
+	got := pollMonitor(context.Background(), socketPath)
+	require.Equal(t, monitorSnapshot{Running: true, Outdated: true}, got)
+	require.Equal(t, "sotto monitor (Ctrl-C to exit)\n\nstate:   unknown (owner too old)\n", renderMonitor(got))
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/doctor"
+	"github.com/rbright/sotto/internal/ipc"
+	"github.com/rbright/sotto/internal/logging"
+	"github.com/rbright/sotto/internal/pipeline"
+)
+
+// runtimePaths is the `sotto paths --json` payload. A path that cannot be
+// resolved is empty and reported on stderr.
+type runtimePaths struct {
+	Config   string `json:"config"`
+	StateDir string `json:"state_dir"`
+	DebugDir string `json:"debug_dir"`
+	Socket   string `json:"socket"`
+	Log      string `json:"log"`
+}
+
+// commandPaths prints where sotto reads and writes. It does not load the
+// config, so it works even when the config file is missing or invalid.
+func (r Runner) commandPaths(configPath string, asJSON bool) int {
+	var paths runtimePaths
+	resolve := func(name string, dst *string, fn func() (string, error)) {
+		path, err := fn()
+		if err != nil {
+			fmt.Fprintf(r.Stderr, "warning: %s path unavailable: %v\n", name, err)
+			return
+		}
+		*dst = path
+	}
+	resolve("config", &paths.Config, func() (string, error) { return config.ResolvePath(configPath) })
+	resolve("state", &paths.StateDir, config.StateDir)
+	resolve("debug", &paths.DebugDir, pipeline.DebugDir)
+	resolve("socket", &paths.Socket, ipc.RuntimeSocketPath)
+	resolve("log", &paths.Log, logging.Path)
+
+	if asJSON {
+		if err := json.NewEncoder(r.Stdout).Encode(paths); err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			return ExitRuntime
+		}
+		return ExitOK
+	}
+
+	for _, row := range []struct{ name, path string }{
+		{"config", paths.Config},
+		{"state", paths.StateDir},
+		{"debug", paths.DebugDir},
+		{"socket", paths.Socket},
+		{"log", paths.Log},
+	} {
+		fmt.Fprintf(r.Stdout, "%-7s %s\n", row.name, row.path)
+	}
+	return ExitOK
+}
+
+// doctorFixPaths maps the loaded config and runtime paths onto what doctor
+// --fix may repair. Paths that cannot be resolved are left empty and skipped.
+func doctorFixPaths(cfgLoaded config.Loaded) doctor.FixPaths {
+	paths := doctor.FixPaths{Config: cfgLoaded.Path, ConfigExists: cfgLoaded.Exists}
+	for _, resolve := range []func() (string, error){config.StateDir, pipeline.DebugDir} {
+		if dir, err := resolve(); err == nil {
+			paths.Dirs = append(paths.Dirs, dir)
+		}
+	}
+	if socket, err := ipc.RuntimeSocketPath(); err == nil {
+		paths.Socket = socket
+	}
+	return paths
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rbright/sotto/internal/config"
+)
+
+// vocabCheckJSON is the `sotto vocab check --json` payload.
+type vocabCheckJSON struct {
+	Source     string         `json:"source"`
+	Sets       []vocabSetJSON `json:"sets"`
+	Phrases    int            `json:"phrases"`
+	MaxPhrases int            `json:"max_phrases"`
+	Exceeds    bool           `json:"exceeds_max"`
+	MinBoost   float64        `json:"min_boost"`
+	MaxBoost   float64        `json:"max_boost"`
+	Collisions []string       `json:"collisions"`
+}
+
+// vocabSetJSON is one element of vocabCheckJSON.Sets.
+type vocabSetJSON struct {
+	Name    string  `json:"name"`
+	Phrases int     `json:"phrases"`
+	Boost   float64 `json:"boost"`
+	Enabled bool    `json:"enabled"`
+}
+
+// commandVocabCheck reports on the config's vocab sets, or on one phrase file
+// when file is set, without starting a session. It fails when the deduped
+// phrases exceed vocab.max_phrases; dedupe collisions are reported only.
+func (r Runner) commandVocabCheck(cfg config.Config, file string, asJSON bool) int {
+	var (
+		report config.VocabReport
+		err    error
+	)
+	if file != "" {
+		report, err = config.CheckVocabFile(cfg, file)
+	} else {
+		report, err = config.CheckVocab(cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(r.Stderr, "error: %v\n", err)
+		return ExitRuntime
+	}
+
+	if asJSON {
+		out := vocabCheckJSON{
+			Source:     report.Source,
+			Sets:       make([]vocabSetJSON, 0, len(report.Sets)),
+			Phrases:    report.Phrases,
+			MaxPhrases: report.MaxPhrases,
+			Exceeds:    report.ExceedsMax(),
+			MinBoost:   report.MinBoost,
+			MaxBoost:   report.MaxBoost,
+			Collisions: make([]string, 0, len(report.Collisions)),
+		}
+		for _, set := range report.Sets {
+			out.Sets = append(out.Sets, vocabSetJSON(set))
+		}
+		for _, w := range report.Collisions {
+			out.Collisions = append(out.Collisions, w.Message)
+		}
+		if err := json.NewEncoder(r.Stdout).Encode(out); err != nil {
+			fmt.Fprintf(r.Stderr, "error: %v\n", err)
+			return ExitRuntime
+		}
+	} else {
+		fmt.Fprintf(r.Stdout, "source: %s\n", report.Source)
+		for _, set := range report.Sets {
+			state := "disabled"
+			if set.Enabled {
+				state = "enabled"
+			}
+			fmt.Fprintf(r.Stdout, "set %s: %d phrases, boost %.2f, %s\n", set.Name, set.Phrases, set.Boost, state)
+		}
+		fmt.Fprintf(r.Stdout, "phrases: %d after dedupe (vocab.max_phrases=%d)\n", report.Phrases, report.MaxPhrases)
+		if report.Phrases > 0 {
+			fmt.Fprintf(r.Stdout, "boost range: %.2f..%.2f\n", report.MinBoost, report.MaxBoost)
+		}
+		for _, w := range report.Collisions {
+			fmt.Fprintf(r.Stdout, "collision: %s\n", w.Message)
+		}
+	}
+
+	if report.ExceedsMax() {
+		fmt.Fprintf(r.Stderr, "error: %d phrases exceed vocab.max_phrases=%d\n", report.Phrases, report.MaxPhrases)
+		return ExitRuntime
+	}
+	return ExitOK
+}
@@ -0,0 +1,46 @@
+package audio
+
+import (
+	"strings"
+
+	"github.com/jfreymuth/pulse"
+	"github.com/rbright/sotto/internal/config"
+)
+
+// ClientIdentity is how sotto's Pulse clients appear in mixers such as pavucontrol.
+type ClientIdentity struct {
+	AppName  string
+	IconName string
+}
+
+// IdentityFromConfig maps audio.pulse_app_name and audio.pulse_icon to the
+// Pulse client identity.
+func IdentityFromConfig(cfg config.AudioConfig) ClientIdentity {
+	return ClientIdentity{AppName: cfg.PulseAppName, IconName: cfg.PulseIcon}
+}
+
+// DefaultClientIdentity is used for any identity field left empty.
+var DefaultClientIdentity = ClientIdentity{
+	AppName:  "sotto",
+	IconName: "audio-input-microphone",
+}
+
+// withDefaults fills empty fields from DefaultClientIdentity.
+func (id ClientIdentity) withDefaults() ClientIdentity {
+	if strings.TrimSpace(id.AppName) == "" {
+		id.AppName = DefaultClientIdentity.AppName
+	}
+	if strings.TrimSpace(id.IconName) == "" {
+		id.IconName = DefaultClientIdentity.IconName
+	}
+	return id
+}
+
+// NewClient connects to the Pulse server under the given identity.
+func NewClient(id ClientIdentity) (*pulse.Client, error) {
+	id = id.withDefaults()
+	return pulse.NewClient(
+		pulse.ClientApplicationName(id.AppName),
+		pulse.ClientApplicationIconName(id.IconName),
+	)
+}
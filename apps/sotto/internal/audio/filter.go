@@ -0,0 +1,55 @@
+package audio
+
+import "strings"
+
+// DeviceFilter narrows the sources sotto may select, matched by id or
+// description substring like audio.input.
+type DeviceFilter struct {
+	// Allow, when non-empty, keeps only devices matching at least one term.
+	Allow []string
+	// Deny drops devices matching any term; it wins over Allow.
+	Deny []string
+	// AllowMonitor keeps sink monitor sources, which are dropped otherwise.
+	AllowMonitor bool
+}
+
+// Active reports whether the filter excludes anything.
+func (f DeviceFilter) Active() bool {
+	return len(f.Allow) > 0 || len(f.Deny) > 0 || !f.AllowMonitor
+}
+
+// Permits reports whether device survives the monitor rule and the
+// allow/deny lists.
+func (f DeviceFilter) Permits(device Device) bool {
+	if device.Monitor && !f.AllowMonitor {
+		return false
+	}
+	for _, term := range f.Deny {
+		if deviceMatches(device, strings.ToLower(strings.TrimSpace(term))) {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, term := range f.Allow {
+		if deviceMatches(device, strings.ToLower(strings.TrimSpace(term))) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterDevices returns the permitted devices in their original order.
+func FilterDevices(devices []Device, filter DeviceFilter) []Device {
+	if !filter.Active() {
+		return devices
+	}
+	out := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		if filter.Permits(device) {
+			out = append(out, device)
+		}
+	}
+	return out
+}
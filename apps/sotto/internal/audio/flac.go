@@ -0,0 +1,313 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// FLAC stream layout constants for the mono s16 capture format.
+const (
+	flacMaxFixedOrder = 4
+	flacMaxRiceParam  = 14 // 15 is the escape code in 4-bit rice parameters
+	flacBitsPerSample = 16
+	flacMinBlockSize  = 16
+	flacMaxBlockSize  = 1<<16 - 1
+)
+
+// FLACEncoder turns s16le mono PCM chunks into a FLAC byte stream with one
+// frame per chunk, so each chunk can be sent to Riva as soon as it is captured.
+// The first Encode call is prefixed with the stream marker and STREAMINFO,
+// whose minimum block size is that first chunk's length: a later, shorter
+// chunk must be the last one, like the trailing chunk of a capture.
+type FLACEncoder struct {
+	sampleRate  int
+	nextSample  uint64
+	wroteHeader bool
+	// minBlock is the STREAMINFO minimum block size; ended is set once a
+	// shorter final block has been written.
+	minBlock int
+	ended    bool
+}
+
+// NewFLACEncoder constructs an encoder for mono 16-bit PCM at sampleRate.
+func NewFLACEncoder(sampleRate int) (*FLACEncoder, error) {
+	if sampleRate <= 0 || sampleRate >= 1<<20 {
+		return nil, fmt.Errorf("flac: unsupported sample rate %d", sampleRate)
+	}
+	return &FLACEncoder{sampleRate: sampleRate}, nil
+}
+
+// Encode returns the FLAC bytes for one PCM chunk.
+func (e *FLACEncoder) Encode(pcm []byte) ([]byte, error) {
+	if len(pcm)%2 != 0 {
+		return nil, errors.New("flac: pcm chunk has odd byte length")
+	}
+	if len(pcm) == 0 {
+		return nil, nil
+	}
+	if len(pcm)/2 > flacMaxBlockSize {
+		return nil, fmt.Errorf("flac: chunk of %d samples exceeds max block size", len(pcm)/2)
+	}
+	if e.ended {
+		return nil, errors.New("flac: chunk after a short final block")
+	}
+
+	samples := make([]int64, len(pcm)/2)
+	for i := range samples {
+		samples[i] = int64(int16(binary.LittleEndian.Uint16(pcm[i*2:])))
+	}
+
+	frame, err := e.frame(samples)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	if !e.wroteHeader {
+		e.minBlock = max(len(samples), flacMinBlockSize)
+		out = append(out, e.streamHeader()...)
+		e.wroteHeader = true
+	} else if len(samples) < e.minBlock {
+		e.ended = true
+	}
+	out = append(out, frame...)
+	e.nextSample += uint64(len(samples))
+	return out, nil
+}
+
+// streamHeader writes the fLaC marker and a STREAMINFO block with unknown
+// length, frame sizes, and checksum, as allowed for live streams.
+func (e *FLACEncoder) streamHeader() []byte {
+	var w bitWriter
+	w.writeBytes([]byte("fLaC"))
+	w.writeBits(1, 1)   // last metadata block
+	w.writeBits(0, 7)   // STREAMINFO
+	w.writeBits(34, 24) // block length
+	w.writeBits(uint64(e.minBlock), 16)
+	w.writeBits(flacMaxBlockSize, 16)
+	w.writeBits(0, 24) // min frame size unknown
+	w.writeBits(0, 24) // max frame size unknown
+	w.writeBits(uint64(e.sampleRate), 20)
+	w.writeBits(0, 3) // channels - 1
+	w.writeBits(flacBitsPerSample-1, 5)
+	w.writeBits(0, 36) // total samples unknown
+	w.writeBytes(make([]byte, 16))
+	return w.bytes()
+}
+
+// frame encodes one variable-blocksize frame holding samples.
+func (e *FLACEncoder) frame(samples []int64) ([]byte, error) {
+	number, err := utf8CodedNumber(e.nextSample)
+	if err != nil {
+		return nil, err
+	}
+
+	var w bitWriter
+	w.writeBits(0x3ffe, 14) // sync code
+	w.writeBits(0, 1)
+	w.writeBits(1, 1)   // variable block size: header carries sample number
+	w.writeBits(0x7, 4) // block size stored as 16 bits after the header
+	w.writeBits(0, 4)   // sample rate from STREAMINFO
+	w.writeBits(0, 4)   // mono
+	w.writeBits(0x4, 3) // 16 bits per sample
+	w.writeBits(0, 1)
+	w.writeBytes(number)
+	w.writeBits(uint64(len(samples)-1), 16)
+	w.writeBytes([]byte{crc8(w.bytes())})
+
+	writeSubframe(&w, samples)
+	w.alignByte()
+	crc := crc16(w.bytes())
+	w.writeBits(uint64(crc), 16)
+	return w.bytes(), nil
+}
+
+// writeSubframe picks the cheapest of constant, fixed-predictor, and verbatim coding.
+func writeSubframe(w *bitWriter, samples []int64) {
+	if isConstant(samples) {
+		w.writeBits(0, 1)
+		w.writeBits(0, 6) // SUBFRAME_CONSTANT
+		w.writeBits(0, 1)
+		w.writeSigned(samples[0], flacBitsPerSample)
+		return
+	}
+
+	verbatimBits := len(samples) * flacBitsPerSample
+	bestOrder, bestParam, bestBits := -1, 0, verbatimBits
+	for order := 0; order <= flacMaxFixedOrder && order < len(samples); order++ {
+		param, bits := bestRiceParam(fixedResidual(samples, order))
+		bits += order*flacBitsPerSample + 2 + 4 + 4
+		if bits < bestBits {
+			bestOrder, bestParam, bestBits = order, param, bits
+		}
+	}
+
+	if bestOrder < 0 {
+		w.writeBits(0, 1)
+		w.writeBits(1, 6) // SUBFRAME_VERBATIM
+		w.writeBits(0, 1)
+		for _, s := range samples {
+			w.writeSigned(s, flacBitsPerSample)
+		}
+		return
+	}
+
+	w.writeBits(0, 1)
+	w.writeBits(uint64(0x8|bestOrder), 6) // SUBFRAME_FIXED
+	w.writeBits(0, 1)
+	for _, s := range samples[:bestOrder] {
+		w.writeSigned(s, flacBitsPerSample)
+	}
+	w.writeBits(0, 2) // RESIDUAL_CODING_METHOD_PARTITIONED_RICE
+	w.writeBits(0, 4) // partition order 0: one partition
+	w.writeBits(uint64(bestParam), 4)
+	for _, r := range fixedResidual(samples, bestOrder) {
+		u := zigzag(r)
+		for q := u >> uint(bestParam); q > 0; q-- {
+			w.writeBits(0, 1)
+		}
+		w.writeBits(1, 1)
+		w.writeBits(u&(1<<uint(bestParam)-1), bestParam)
+	}
+}
+
+func isConstant(samples []int64) bool {
+	for _, s := range samples[1:] {
+		if s != samples[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// fixedResidual applies the FLAC fixed polynomial predictor of the given order.
+func fixedResidual(samples []int64, order int) []int64 {
+	out := make([]int64, 0, len(samples)-order)
+	for i := order; i < len(samples); i++ {
+		var predicted int64
+		switch order {
+		case 1:
+			predicted = samples[i-1]
+		case 2:
+			predicted = 2*samples[i-1] - samples[i-2]
+		case 3:
+			predicted = 3*samples[i-1] - 3*samples[i-2] + samples[i-3]
+		case 4:
+			predicted = 4*samples[i-1] - 6*samples[i-2] + 4*samples[i-3] - samples[i-4]
+		}
+		out = append(out, samples[i]-predicted)
+	}
+	return out
+}
+
+// bestRiceParam returns the rice parameter with the fewest residual bits.
+func bestRiceParam(residual []int64) (int, int) {
+	bestParam, bestBits := 0, -1
+	for param := 0; param <= flacMaxRiceParam; param++ {
+		bits := 0
+		for _, r := range residual {
+			bits += int(zigzag(r)>>uint(param)) + 1 + param
+		}
+		if bestBits < 0 || bits < bestBits {
+			bestParam, bestBits = param, bits
+		}
+	}
+	return bestParam, bestBits
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// utf8CodedNumber encodes n with FLAC's extended UTF-8 scheme (up to 36 bits).
+func utf8CodedNumber(n uint64) ([]byte, error) {
+	if n < 0x80 {
+		return []byte{byte(n)}, nil
+	}
+	for length := 2; length <= 7; length++ {
+		payload := uint(5*length + 1)
+		if length == 7 {
+			payload = 36
+		}
+		if n >= 1<<payload {
+			continue
+		}
+		out := make([]byte, length)
+		for i := length - 1; i > 0; i-- {
+			out[i] = 0x80 | byte(n&0x3f)
+			n >>= 6
+		}
+		out[0] = byte(0xff<<(8-length)) | byte(n)
+		return out, nil
+	}
+	return nil, fmt.Errorf("flac: sample number %d exceeds 36 bits", n)
+}
+
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// bitWriter accumulates an MSB-first bit stream.
+type bitWriter struct {
+	buf   []byte
+	acc   byte
+	nbits uint
+}
+
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.acc = w.acc<<1 | byte(v>>uint(i)&1)
+		w.nbits++
+		if w.nbits == 8 {
+			w.buf = append(w.buf, w.acc)
+			w.acc, w.nbits = 0, 0
+		}
+	}
+}
+
+func (w *bitWriter) writeSigned(v int64, n int) {
+	w.writeBits(uint64(v)&(1<<uint(n)-1), n)
+}
+
+func (w *bitWriter) writeBytes(b []byte) {
+	for _, c := range b {
+		w.writeBits(uint64(c), 8)
+	}
+}
+
+func (w *bitWriter) alignByte() {
+	if w.nbits > 0 {
+		w.writeBits(0, int(8-w.nbits))
+	}
+}
+
+// bytes returns the completed bytes; callers align before relying on it.
+func (w *bitWriter) bytes() []byte {
+	return w.buf
+}
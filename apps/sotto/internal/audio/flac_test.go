@@ -0,0 +1,265 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFLACEncoderRoundTrip(t *testing.T) {
+	enc, err := NewFLACEncoder(16000)
+	require.NoError(t, err)
+
+	rng := rand.New(rand.NewSource(1))
+	var want []int16
+	var stream []byte
+	chunks := [][]int16{
+		make([]int16, 320), // silence -> constant subframe
+		sineSamples(320, 440, 0),
+		sineSamples(320, 440, 320),
+		noiseSamples(rng, 320),   // incompressible -> verbatim subframe
+		sineSamples(111, 880, 0), // short trailing flush
+	}
+	for _, chunk := range chunks {
+		out, err := enc.Encode(pcmBytes(chunk))
+		require.NoError(t, err)
+		stream = append(stream, out...)
+		want = append(want, chunk...)
+	}
+
+	got := decodeFLACStream(t, stream)
+	require.Equal(t, want, got)
+}
+
+func TestFLACEncoderCompressesSpeechLikeAudio(t *testing.T) {
+	enc, err := NewFLACEncoder(16000)
+	require.NoError(t, err)
+
+	pcm := pcmBytes(sineSamples(320, 220, 0))
+	_, err = enc.Encode(pcm) // header + first frame
+	require.NoError(t, err)
+	out, err := enc.Encode(pcm)
+	require.NoError(t, err)
+	require.Less(t, len(out), len(pcm)*3/4)
+}
+
+func TestFLACEncoderRejectsInvalidInput(t *testing.T) {
+	_, err := NewFLACEncoder(0)
+	require.Error(t, err)
+
+	enc, err := NewFLACEncoder(16000)
+	require.NoError(t, err)
+	_, err = enc.Encode([]byte{1, 2, 3})
+	require.ErrorContains(t, err, "odd byte length")
+
+	out, err := enc.Encode(nil)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func TestFLACEncoderMatchesGoldenBytes(t *testing.T) {
+	enc, err := NewFLACEncoder(16000)
+	require.NoError(t, err)
+
+	var stream []byte
+	for _, chunk := range [][]int16{
+		make([]int16, 16),
+		{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+		{-1, -1, -1, -1}, // short final block
+	} {
+		out, err := enc.Encode(pcmBytes(chunk))
+		require.NoError(t, err)
+		stream = append(stream, out...)
+	}
+
+	golden := []byte{
+		// "fLaC", last-block STREAMINFO of 34 bytes
+		0x66, 0x4c, 0x61, 0x43, 0x80, 0x00, 0x00, 0x22,
+		// min block 16, max block 65535, frame sizes unknown
+		0x00, 0x10, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// 16000 Hz, mono, 16-bit, total samples unknown
+		0x03, 0xe8, 0x00, 0xf0, 0x00, 0x00, 0x00, 0x00,
+		// MD5 unset
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// frame at sample 0: 16 samples, constant 0
+		0xff, 0xf9, 0x70, 0x08, 0x00, 0x00, 0x0f, 0x1e, 0x00, 0x00, 0x00, 0x08, 0xc1,
+		// frame at sample 16: 16 samples, constant 5
+		0xff, 0xf9, 0x70, 0x08, 0x10, 0x00, 0x0f, 0xbc, 0x00, 0x00, 0x05, 0x31, 0xec,
+		// frame at sample 32: 4 samples, constant -1
+		0xff, 0xf9, 0x70, 0x08, 0x20, 0x00, 0x03, 0x79, 0x00, 0xff, 0xff, 0xc4, 0xfd,
+	}
+	require.Equal(t, golden, stream)
+}
+
+func TestFLACEncoderBlockSizeLimits(t *testing.T) {
+	enc, err := NewFLACEncoder(16000)
+	require.NoError(t, err)
+	_, err = enc.Encode(make([]byte, 2*(1<<16)))
+	require.ErrorContains(t, err, "exceeds max block size")
+
+	out, err := enc.Encode(make([]byte, 2*320))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01, 0x40}, out[8:10], "STREAMINFO min block size is the first chunk's")
+	_, err = enc.Encode(make([]byte, 2*100))
+	require.NoError(t, err)
+	_, err = enc.Encode(make([]byte, 2*320))
+	require.ErrorContains(t, err, "after a short final block")
+}
+
+func TestUTF8CodedNumber(t *testing.T) {
+	for _, tc := range []struct {
+		n    uint64
+		want []byte
+	}{
+		{0x7f, []byte{0x7f}},
+		{0x80, []byte{0xc2, 0x80}},
+		{0x800, []byte{0xe0, 0xa0, 0x80}},
+	} {
+		got, err := utf8CodedNumber(tc.n)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got)
+	}
+
+	_, err := utf8CodedNumber(1 << 36)
+	require.ErrorContains(t, err, "exceeds 36 bits")
+}
+
+func sineSamples(n int, freq float64, offset int) []int16 {
+	out := make([]int16, n)
+	for i := range out {
+		out[i] = int16(8000 * math.Sin(2*math.Pi*freq*float64(i+offset)/16000))
+	}
+	return out
+}
+
+func noiseSamples(rng *rand.Rand, n int) []int16 {
+	out := make([]int16, n)
+	for i := range out {
+		out[i] = int16(rng.Intn(1<<16) - 1<<15)
+	}
+	return out
+}
+
+func pcmBytes(samples []int16) []byte {
+	out := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(s))
+	}
+	return out
+}
+
+// decodeFLACStream decodes the subset of FLAC that FLACEncoder produces,
+// checking header and frame CRCs along the way.
+func decodeFLACStream(t *testing.T, data []byte) []int16 {
+	t.Helper()
+
+	require.Equal(t, "fLaC", string(data[:4]))
+	require.Equal(t, byte(0x80), data[4], "single, final STREAMINFO block")
+	require.Equal(t, uint32(34), uint32(data[5])<<16|uint32(data[6])<<8|uint32(data[7]))
+	r := &bitReader{data: data[8+34:]}
+
+	var out []int16
+	for !r.done() {
+		start := r.pos / 8
+		require.Equal(t, uint64(0xfff9), r.read(16), "frame sync, variable block size")
+		require.Equal(t, uint64(0x70), r.read(8))
+		require.Equal(t, uint64(0x08), r.read(8))
+		first := r.read(8)
+		for mask := uint64(0x40); first&0x80 != 0 && first&mask != 0 && mask > 0x01; mask >>= 1 {
+			r.read(8)
+		}
+		blockSize := int(r.read(16)) + 1
+		headerEnd := r.pos / 8
+		require.Equal(t, crc8(r.data[start:headerEnd]), byte(r.read(8)), "header crc")
+
+		require.Equal(t, uint64(0), r.read(1))
+		kind := r.read(6)
+		require.Equal(t, uint64(0), r.read(1))
+		samples := make([]int64, 0, blockSize)
+		switch {
+		case kind == 0:
+			v := r.readSigned(16)
+			for i := 0; i < blockSize; i++ {
+				samples = append(samples, v)
+			}
+		case kind == 1:
+			for i := 0; i < blockSize; i++ {
+				samples = append(samples, r.readSigned(16))
+			}
+		case kind&0x38 == 0x08:
+			order := int(kind & 0x7)
+			for i := 0; i < order; i++ {
+				samples = append(samples, r.readSigned(16))
+			}
+			require.Equal(t, uint64(0), r.read(2))
+			require.Equal(t, uint64(0), r.read(4))
+			param := uint(r.read(4))
+			residual := make([]int64, 0, blockSize-order)
+			for i := order; i < blockSize; i++ {
+				var q uint64
+				for r.read(1) == 0 {
+					q++
+				}
+				u := q<<param | r.read(int(param))
+				residual = append(residual, int64(u>>1)^-int64(u&1))
+			}
+			for _, res := range residual {
+				i := len(samples)
+				var predicted int64
+				switch order {
+				case 1:
+					predicted = samples[i-1]
+				case 2:
+					predicted = 2*samples[i-1] - samples[i-2]
+				case 3:
+					predicted = 3*samples[i-1] - 3*samples[i-2] + samples[i-3]
+				case 4:
+					predicted = 4*samples[i-1] - 6*samples[i-2] + 4*samples[i-3] - samples[i-4]
+				}
+				samples = append(samples, predicted+res)
+			}
+		default:
+			t.Fatalf("unexpected subframe type %#x", kind)
+		}
+
+		r.align()
+		frameEnd := r.pos / 8
+		require.Equal(t, crc16(r.data[start:frameEnd]), uint16(r.read(16)), "frame crc")
+		for _, s := range samples {
+			out = append(out, int16(s))
+		}
+	}
+	return out
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) done() bool { return r.pos >= len(r.data)*8 }
+
+func (r *bitReader) read(n int) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := r.data[r.pos/8] >> (7 - uint(r.pos%8)) & 1
+		v = v<<1 | uint64(bit)
+		r.pos++
+	}
+	return v
+}
+
+func (r *bitReader) readSigned(n int) int64 {
+	v := r.read(n)
+	return int64(v<<(64-uint(n))) >> (64 - uint(n))
+}
+
+func (r *bitReader) align() {
+	if r.pos%8 != 0 {
+		r.pos += 8 - r.pos%8
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/jfreymuth/pulse"
 	pulseproto "github.com/jfreymuth/pulse/proto"
@@ -16,8 +17,14 @@ import (
 
 const (
 	chunkSizeBytes = 640 // 20ms @ 16kHz mono s16
+	captureRate    = 16000
+
+	disconnectPollInterval = 100 * time.Millisecond
 )
 
+// ErrDisconnected reports that the Pulse server went away during capture.
+var ErrDisconnected = errors.New("pulse server disconnected during capture")
+
 // Device describes one Pulse input source surfaced to sotto.
 type Device struct {
 	ID          string
@@ -26,6 +33,9 @@ type Device struct {
 	Available   bool
 	Muted       bool
 	Default     bool
+	// Monitor marks a sink's loopback source, which captures what the sink
+	// plays rather than a microphone.
+	Monitor bool
 }
 
 // Selection is the resolved capture source plus optional fallback warning context.
@@ -36,11 +46,13 @@ type Selection struct {
 }
 
 // ListDevices returns available Pulse input sources with default/availability metadata.
-func ListDevices(_ context.Context) ([]Device, error) {
-	client, err := pulse.NewClient(
-		pulse.ClientApplicationName("sotto"),
-		pulse.ClientApplicationIconName("audio-input-microphone"),
-	)
+func ListDevices(_ context.Context, id ClientIdentity) ([]Device, error) {
+	return listDevices(id, NewClient)
+}
+
+// listDevices lists input sources over the client connect builds for id.
+func listDevices(id ClientIdentity, connect func(ClientIdentity) (*pulse.Client, error)) ([]Device, error) {
+	client, err := connect(id)
 	if err != nil {
 		return nil, fmt.Errorf("connect pulse server: %w", err)
 	}
@@ -69,30 +81,65 @@ func ListDevices(_ context.Context) ([]Device, error) {
 			Available:   sourceAvailable(source),
 			Muted:       source.Mute,
 			Default:     source.SourceName == defaultID,
+			Monitor:     isMonitorSource(source),
 		})
 	}
 	return devices, nil
 }
 
+// ListSinks returns the names of the Pulse playback sinks.
+func ListSinks(_ context.Context, id ClientIdentity) ([]string, error) {
+	client, err := NewClient(id)
+	if err != nil {
+		return nil, fmt.Errorf("connect pulse server: %w", err)
+	}
+	defer client.Close()
+
+	sinks, err := client.ListSinks()
+	if err != nil {
+		return nil, fmt.Errorf("list sinks: %w", err)
+	}
+	names := make([]string, 0, len(sinks))
+	for _, sink := range sinks {
+		names = append(names, sink.ID())
+	}
+	return names, nil
+}
+
 // SelectDevice resolves audio.input/audio.fallback preferences against live devices.
-func SelectDevice(ctx context.Context, input string, fallback string) (Selection, error) {
-	devices, err := ListDevices(ctx)
+func SelectDevice(ctx context.Context, input string, fallback string, filter DeviceFilter, id ClientIdentity) (Selection, error) {
+	devices, err := ListDevices(ctx, id)
 	if err != nil {
 		return Selection{}, err
 	}
-	return selectDeviceFromList(devices, input, fallback)
+	return selectDeviceFromList(devices, input, fallback, filter)
 }
 
 // selectDeviceFromList applies selection policy to a pre-fetched device list.
-func selectDeviceFromList(devices []Device, input string, fallback string) (Selection, error) {
+// Devices rejected by filter are never selected; when the system default is
+// rejected, the first usable permitted device stands in for it.
+func selectDeviceFromList(devices []Device, input string, fallback string, filter DeviceFilter) (Selection, error) {
 	if len(devices) == 0 {
 		return Selection{}, errors.New("no audio input devices found")
 	}
 
+	var systemDefault *Device
+	for i := range devices {
+		if devices[i].Default {
+			systemDefault = &devices[i]
+		}
+	}
+	all := devices
+	devices = FilterDevices(devices, filter)
+	if len(devices) == 0 {
+		return Selection{}, errors.New("no audio input devices left after audio.allow/audio.deny/audio.allow_monitor filtering")
+	}
+
 	var (
-		defaultDevice *Device
-		byInput       *Device
-		byFallback    *Device
+		defaultDevice  *Device
+		defaultWarning string
+		byInput        *Device
+		byFallback     *Device
 	)
 
 	input = strings.TrimSpace(strings.ToLower(input))
@@ -111,6 +158,16 @@ func selectDeviceFromList(devices []Device, input string, fallback string) (Sele
 		}
 	}
 
+	if defaultDevice == nil && systemDefault != nil {
+		for i := range devices {
+			if devices[i].Available && !devices[i].Muted {
+				defaultDevice = &devices[i]
+				defaultWarning = fmt.Sprintf("default source %q is excluded by audio.allow/audio.deny/audio.allow_monitor; using %q", systemDefault.ID, defaultDevice.ID)
+				break
+			}
+		}
+	}
+
 	chooseDefault := func() (*Device, error) {
 		if defaultDevice == nil {
 			return nil, errors.New("default audio source is unavailable")
@@ -125,6 +182,13 @@ func selectDeviceFromList(devices []Device, input string, fallback string) (Sele
 		if byInput != nil {
 			return byInput, nil
 		}
+		if !filter.AllowMonitor {
+			for _, dev := range all {
+				if dev.Monitor && deviceMatches(dev, input) {
+					return nil, fmt.Errorf("audio.input %q matches monitor source %q; set audio.allow_monitor=true to capture it", input, dev.ID)
+				}
+			}
+		}
 		return nil, fmt.Errorf("audio.input %q did not match any device", input)
 	}
 
@@ -133,6 +197,9 @@ func selectDeviceFromList(devices []Device, input string, fallback string) (Sele
 		return Selection{}, err
 	}
 	if primary.Available && !primary.Muted {
+		if primary == defaultDevice {
+			return Selection{Device: *primary, Warning: defaultWarning}, nil
+		}
 		return Selection{Device: *primary}, nil
 	}
 
@@ -185,25 +252,61 @@ type Capture struct {
 
 	client *pulse.Client
 	stream *pulse.RecordStream
+	// streamLost reports a server-side stream teardown; the pulse client
+	// only exposes this by polling.
+	streamLost func() bool
 
 	chunks chan []byte
 	stopCh chan struct{}
 
+	// resampler converts native-rate PCM to captureRate when the internal
+	// resampler is in use; nil when Pulse delivers captureRate directly.
+	resampler *Resampler
+
 	mu      sync.Mutex
 	pending []byte
 	rawPCM  []byte
 	stopped bool
+	err     error
 
 	inflight sync.WaitGroup
+	// stopping is held by the first Stop until Chunks closes, so concurrent
+	// callers never return before the flush.
+	stopping sync.WaitGroup
 	bytes    atomic.Int64
 }
 
-// StartCapture creates and starts a 16kHz mono s16 record stream.
-func StartCapture(ctx context.Context, selected Device) (*Capture, error) {
-	client, err := pulse.NewClient(
-		pulse.ClientApplicationName("sotto"),
-		pulse.ClientApplicationIconName("audio-input-microphone"),
-	)
+// CaptureOptions tunes the Pulse record stream.
+type CaptureOptions struct {
+	// Latency is the record latency requested from Pulse; zero keeps the
+	// fixed chunkSizeBytes fragment.
+	Latency time.Duration
+	// InternalResample records sources at their native rate and converts to
+	// 16kHz in-process instead of asking Pulse to resample.
+	InternalResample bool
+}
+
+// recordBufferOption sizes a record stream's buffer. A latency hint goes to
+// pulse.RecordLatency, which lets Pulse adjust the source latency; otherwise
+// the fragment is fixed to one chunk at the stream's sample rate. Both read
+// the stream's rate and channels, so the option must follow those.
+func recordBufferOption(latency time.Duration, rate int) pulse.RecordOption {
+	if latency > 0 {
+		return pulse.RecordLatency(latency.Seconds())
+	}
+	return pulse.RecordBufferFragmentSize(fixedFragmentBytes(rate))
+}
+
+// fixedFragmentBytes is one capture chunk's worth of mono s16 audio at rate.
+func fixedFragmentBytes(rate int) uint32 {
+	return uint32(chunkSizeBytes * rate / captureRate)
+}
+
+// StartCapture creates and starts a 16kHz mono s16 record stream. With
+// opts.InternalResample, a source running at another rate is recorded at that
+// rate and resampled before chunking.
+func StartCapture(ctx context.Context, selected Device, id ClientIdentity, opts CaptureOptions) (*Capture, error) {
+	client, err := NewClient(id)
 	if err != nil {
 		return nil, fmt.Errorf("connect pulse server: %w", err)
 	}
@@ -221,13 +324,19 @@ func StartCapture(ctx context.Context, selected Device) (*Capture, error) {
 		stopCh: make(chan struct{}),
 	}
 
+	rate := captureRate
+	if native := source.SampleRate(); opts.InternalResample && native > 0 && native != captureRate {
+		rate = native
+		capture.resampler = NewResampler(native, captureRate)
+	}
+
 	writer := pulse.NewWriter(writerFunc(capture.onPCM), pulseproto.FormatInt16LE)
 	stream, err := client.NewRecord(
 		writer,
 		pulse.RecordSource(source),
 		pulse.RecordMono,
-		pulse.RecordSampleRate(16000),
-		pulse.RecordBufferFragmentSize(chunkSizeBytes),
+		pulse.RecordSampleRate(rate),
+		recordBufferOption(opts.Latency, rate),
 		pulse.RecordMediaName("sotto dictation"),
 	)
 	if err != nil {
@@ -236,7 +345,9 @@ func StartCapture(ctx context.Context, selected Device) (*Capture, error) {
 	}
 
 	capture.stream = stream
+	capture.streamLost = stream.Closed
 	stream.Start()
+	go capture.watchConnection()
 
 	go func() {
 		<-ctx.Done()
@@ -256,7 +367,7 @@ func (c *Capture) Chunks() <-chan []byte {
 	return c.chunks
 }
 
-// BytesCaptured reports total bytes accepted from Pulse.
+// BytesCaptured reports total 16kHz PCM bytes captured, after any resampling.
 func (c *Capture) BytesCaptured() int64 {
 	return c.bytes.Load()
 }
@@ -271,13 +382,18 @@ func (c *Capture) RawPCM() []byte {
 }
 
 // Stop halts the stream, flushes residual PCM, and closes Chunks exactly once.
+// It is safe before any PCM arrives and from concurrent callers; every call
+// returns only after Chunks is closed.
 func (c *Capture) Stop() error {
 	c.mu.Lock()
 	if c.stopped {
 		c.mu.Unlock()
+		c.stopping.Wait()
 		return nil
 	}
 	c.stopped = true
+	c.stopping.Add(1)
+	defer c.stopping.Done()
 	close(c.stopCh)
 	c.mu.Unlock()
 
@@ -292,10 +408,18 @@ func (c *Capture) Stop() error {
 	c.inflight.Wait()
 
 	c.mu.Lock()
+	if c.resampler != nil {
+		tail := c.resampler.Flush()
+		c.rawPCM = append(c.rawPCM, tail...)
+		c.pending = append(c.pending, tail...)
+		c.bytes.Add(int64(len(tail)))
+	}
 	pending := append([]byte(nil), c.pending...)
 	c.pending = nil
 	c.mu.Unlock()
 
+	// Never block here: the reader may be the caller (sendLoop stops capture
+	// on send errors), so a full buffer drops the residual tail.
 	if len(pending) > 0 {
 		chunk := make([]byte, len(pending))
 		copy(chunk, pending)
@@ -309,6 +433,42 @@ func (c *Capture) Stop() error {
 	return nil
 }
 
+// Err reports why capture ended on its own: ErrDisconnected when the Pulse
+// server dropped the stream, nil after a normal Stop.
+func (c *Capture) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// watchConnection stops capture with ErrDisconnected when Pulse drops the
+// stream, so Chunks closes instead of stalling silently.
+func (c *Capture) watchConnection() {
+	ticker := time.NewTicker(disconnectPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+		}
+		if !c.streamLost() {
+			continue
+		}
+
+		c.mu.Lock()
+		if c.stopped {
+			c.mu.Unlock()
+			return
+		}
+		c.err = ErrDisconnected
+		c.mu.Unlock()
+		_ = c.Stop()
+		return
+	}
+}
+
 // Close is a convenience alias for Stop.
 func (c *Capture) Close() {
 	_ = c.Stop()
@@ -316,6 +476,7 @@ func (c *Capture) Close() {
 
 // onPCM receives raw Pulse frames and emits chunkSizeBytes slices to c.chunks.
 func (c *Capture) onPCM(buffer []byte) (int, error) {
+	accepted := len(buffer)
 	if len(buffer) == 0 {
 		return 0, nil
 	}
@@ -334,6 +495,9 @@ func (c *Capture) onPCM(buffer []byte) (int, error) {
 	// Guard Add under the same mutex as c.stopped to avoid Add/Wait races.
 	c.inflight.Add(1)
 
+	if c.resampler != nil {
+		buffer = c.resampler.Process(buffer)
+	}
 	c.rawPCM = append(c.rawPCM, buffer...)
 	c.pending = append(c.pending, buffer...)
 
@@ -357,7 +521,7 @@ func (c *Capture) onPCM(buffer []byte) (int, error) {
 		}
 	}
 
-	return len(buffer), nil
+	return accepted, nil
 }
 
 // writerFunc adapts a function to io.Writer for pulse.NewWriter.
@@ -381,12 +545,20 @@ func sourceStateString(state uint32) string {
 	}
 }
 
+// isMonitorSource reports whether source is a sink's monitor. The reply field
+// carries monitor_of_sink_name for sources, empty for non-monitors.
+func isMonitorSource(source *pulseproto.GetSourceInfoReply) bool {
+	return source != nil && source.MonitorSourceName != ""
+}
+
 // sourceAvailable maps Pulse source port availability to a simple boolean.
+// A monitor is always usable: it carries whatever the sink plays, even when
+// the sink's output port (e.g. an unplugged headphone jack) is not.
 func sourceAvailable(source *pulseproto.GetSourceInfoReply) bool {
 	if source == nil {
 		return false
 	}
-	if len(source.Ports) == 0 {
+	if isMonitorSource(source) || len(source.Ports) == 0 {
 		return true
 	}
 	for _, port := range source.Ports {
@@ -14,7 +14,7 @@ func TestListDevicesIntegration(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	devices, err := ListDevices(ctx)
+	devices, err := ListDevices(ctx, DefaultClientIdentity)
 	require.NoError(t, err)
 	require.NotEmpty(t, devices)
 }
@@ -2,21 +2,32 @@ package audio
 
 import (
 	"context"
+	"errors"
 	"io"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/jfreymuth/pulse"
 	pulseproto "github.com/jfreymuth/pulse/proto"
+	"github.com/rbright/sotto/internal/config"
 	"github.com/stretchr/testify/require"
 )
 
+func TestFixedFragmentBytes(t *testing.T) {
+	require.Equal(t, uint32(chunkSizeBytes), fixedFragmentBytes(captureRate))
+	require.Equal(t, uint32(3*chunkSizeBytes), fixedFragmentBytes(48000))
+}
+
 func TestSelectDeviceFromListPrimaryDefault(t *testing.T) {
 	devices := []Device{
 		{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true, Default: true},
 		{ID: "sony", Description: "Sony WH-1000XM6", Available: true},
 	}
 
-	selection, err := selectDeviceFromList(devices, "default", "default")
+	selection, err := selectDeviceFromList(devices, "default", "default", DeviceFilter{})
 	require.NoError(t, err)
 	require.Equal(t, "elgato", selection.Device.ID)
 	require.Empty(t, selection.Warning)
@@ -28,7 +39,7 @@ func TestSelectDeviceFromListMutedPrimaryUsesFallback(t *testing.T) {
 		{ID: "sony", Description: "Sony WH-1000XM6", Available: true},
 	}
 
-	selection, err := selectDeviceFromList(devices, "elgato", "sony")
+	selection, err := selectDeviceFromList(devices, "elgato", "sony", DeviceFilter{})
 	require.NoError(t, err)
 	require.Equal(t, "sony", selection.Device.ID)
 	require.Contains(t, selection.Warning, "muted")
@@ -40,7 +51,7 @@ func TestSelectDeviceFromListFailsWhenSelectedAndFallbackMuted(t *testing.T) {
 		{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true, Muted: true, Default: true},
 	}
 
-	_, err := selectDeviceFromList(devices, "default", "default")
+	_, err := selectDeviceFromList(devices, "default", "default", DeviceFilter{})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "muted")
 }
@@ -48,11 +59,95 @@ func TestSelectDeviceFromListFailsWhenSelectedAndFallbackMuted(t *testing.T) {
 func TestSelectDeviceFromListUnknownInput(t *testing.T) {
 	devices := []Device{{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true, Default: true}}
 
-	_, err := selectDeviceFromList(devices, "missing", "default")
+	_, err := selectDeviceFromList(devices, "missing", "default", DeviceFilter{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "did not match")
+}
+
+func TestSelectDeviceFromListDenyExcludesDefaultDevice(t *testing.T) {
+	devices := []Device{
+		{ID: "alsa_output.hdmi.monitor", Description: "Monitor of HDMI", Available: true, Default: true},
+		{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true},
+	}
+
+	selection, err := selectDeviceFromList(devices, "default", "default", DeviceFilter{Deny: []string{"Monitor"}})
+	require.NoError(t, err)
+	require.Equal(t, "elgato", selection.Device.ID)
+	require.Contains(t, selection.Warning, "excluded")
+	require.False(t, selection.Fallback)
+}
+
+func TestSelectDeviceFromListDenyRejectsExplicitInput(t *testing.T) {
+	devices := []Device{
+		{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true, Default: true},
+		{ID: "sony", Description: "Sony WH-1000XM6", Available: true},
+	}
+
+	_, err := selectDeviceFromList(devices, "sony", "default", DeviceFilter{Deny: []string{"sony"}})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "did not match")
 }
 
+func TestSelectDeviceFromListAllowListKeepsOnlyMatches(t *testing.T) {
+	devices := []Device{
+		{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true, Default: true},
+		{ID: "sony", Description: "Sony WH-1000XM6", Available: true},
+		{ID: "webcam", Description: "USB Webcam", Available: true},
+	}
+
+	selection, err := selectDeviceFromList(devices, "default", "default", DeviceFilter{Allow: []string{"sony"}})
+	require.NoError(t, err)
+	require.Equal(t, "sony", selection.Device.ID)
+
+	_, err = selectDeviceFromList(devices, "default", "default", DeviceFilter{Allow: []string{"missing"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "audio.allow/audio.deny")
+}
+
+func TestSelectDeviceFromListMonitorSelectableByID(t *testing.T) {
+	devices := []Device{
+		{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true, Default: true},
+		{ID: "alsa_output.pci-0000_00_1f.3.analog-stereo.monitor", Description: "Monitor of Built-in Audio", State: "suspended", Available: true, Monitor: true},
+	}
+
+	selection, err := selectDeviceFromList(devices, "alsa_output.pci-0000_00_1f.3.analog-stereo.monitor", "default", DeviceFilter{AllowMonitor: true})
+	require.NoError(t, err)
+	require.Equal(t, devices[1].ID, selection.Device.ID)
+	require.Empty(t, selection.Warning)
+
+	_, err = selectDeviceFromList(devices, "alsa_output.pci-0000_00_1f.3.analog-stereo.monitor", "default", DeviceFilter{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "audio.allow_monitor=true")
+}
+
+func TestSelectDeviceFromListSkipsMonitorUnlessAllowed(t *testing.T) {
+	devices := []Device{
+		{ID: "alsa_output.hdmi.monitor", Description: "Monitor of HDMI", Available: true, Default: true, Monitor: true},
+		{ID: "elgato", Description: "Elgato Wave 3 Mono", Available: true},
+	}
+
+	selection, err := selectDeviceFromList(devices, "default", "default", DeviceFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "elgato", selection.Device.ID)
+	require.Contains(t, selection.Warning, "excluded")
+
+	selection, err = selectDeviceFromList(devices, "default", "default", DeviceFilter{AllowMonitor: true})
+	require.NoError(t, err)
+	require.Equal(t, "alsa_output.hdmi.monitor", selection.Device.ID)
+}
+
+func TestFilterDevicesDenyWinsOverAllow(t *testing.T) {
+	devices := []Device{
+		{ID: "usb-elgato", Description: "Elgato Wave 3 Mono"},
+		{ID: "usb-webcam", Description: "USB Webcam"},
+		{ID: "sony", Description: "Sony WH-1000XM6"},
+	}
+
+	got := FilterDevices(devices, DeviceFilter{Allow: []string{"usb"}, Deny: []string{"webcam"}})
+	require.Equal(t, []Device{devices[0]}, got)
+	require.Equal(t, devices, FilterDevices(devices, DeviceFilter{}))
+}
+
 func TestDeviceMatchesByIDAndDescription(t *testing.T) {
 	dev := Device{ID: "alsa_input.usb-elgato", Description: "Elgato Wave 3 Mono"}
 	require.True(t, deviceMatches(dev, "elgato"))
@@ -62,13 +157,13 @@ func TestDeviceMatchesByIDAndDescription(t *testing.T) {
 
 func TestListDevicesFailsWhenPulseUnavailable(t *testing.T) {
 	t.Setenv("PULSE_SERVER", "unix:/tmp/definitely-missing-pulse-server")
-	_, err := ListDevices(context.Background())
+	_, err := ListDevices(context.Background(), DefaultClientIdentity)
 	require.Error(t, err)
 }
 
 func TestSelectDeviceFailsWhenPulseUnavailable(t *testing.T) {
 	t.Setenv("PULSE_SERVER", "unix:/tmp/definitely-missing-pulse-server")
-	_, err := SelectDevice(context.Background(), "default", "default")
+	_, err := SelectDevice(context.Background(), "default", "default", DeviceFilter{}, DefaultClientIdentity)
 	require.Error(t, err)
 }
 
@@ -90,6 +185,13 @@ func TestSourceAvailable(t *testing.T) {
 	notAvailable := &pulseproto.GetSourceInfoReply{ActivePortName: "mic"}
 	setSourcePorts(t, notAvailable, []sourcePort{{name: "mic", available: 1}})
 	require.False(t, sourceAvailable(notAvailable))
+
+	// A monitor stays usable when its sink's jack reports unplugged.
+	monitor := &pulseproto.GetSourceInfoReply{ActivePortName: "headphones", MonitorSourceName: "alsa_output.pci"}
+	setSourcePorts(t, monitor, []sourcePort{{name: "headphones", available: 1}})
+	require.True(t, isMonitorSource(monitor))
+	require.True(t, sourceAvailable(monitor))
+	require.False(t, isMonitorSource(notAvailable))
 }
 
 func TestWriterFuncDelegatesWrite(t *testing.T) {
@@ -162,6 +264,102 @@ func TestCaptureDeviceAndCloseAlias(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestCaptureWatchConnectionStopsWithErrDisconnected(t *testing.T) {
+	var lost atomic.Bool
+	capture := &Capture{
+		chunks:     make(chan []byte, 1),
+		stopCh:     make(chan struct{}),
+		streamLost: lost.Load,
+	}
+	done := make(chan struct{})
+	go func() {
+		capture.watchConnection()
+		close(done)
+	}()
+
+	lost.Store(true)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("watchConnection did not notice the lost stream")
+	}
+
+	require.ErrorIs(t, capture.Err(), ErrDisconnected)
+	_, ok := <-capture.Chunks()
+	require.False(t, ok)
+}
+
+func TestCaptureStopLeavesErrNil(t *testing.T) {
+	capture := &Capture{
+		chunks:     make(chan []byte, 1),
+		stopCh:     make(chan struct{}),
+		streamLost: func() bool { return false },
+	}
+	go capture.watchConnection()
+
+	require.NoError(t, capture.Stop())
+	require.NoError(t, capture.Err())
+}
+
+func TestCaptureImmediateStopStress(t *testing.T) {
+	for i := range 500 {
+		capture := &Capture{
+			chunks:     make(chan []byte, 128),
+			stopCh:     make(chan struct{}),
+			streamLost: func() bool { return false },
+		}
+		go capture.watchConnection()
+
+		drained := make(chan int)
+		go func() {
+			received := 0
+			for chunk := range capture.Chunks() {
+				received += len(chunk)
+			}
+			drained <- received
+		}()
+
+		// Odd iterations race one Pulse callback against Stop.
+		var wg sync.WaitGroup
+		if i%2 == 1 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = capture.onPCM(make([]byte, chunkSizeBytes+7))
+			}()
+		}
+		// Two concurrent Stops mirror the caller and the ctx-cancel goroutine.
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, capture.Stop())
+				select {
+				case _, ok := <-capture.Chunks():
+					if ok {
+						return // residual chunk raced the drain goroutine
+					}
+				default:
+					t.Error("Stop returned before Chunks closed")
+				}
+			}()
+		}
+		wg.Wait()
+
+		select {
+		case received := <-drained:
+			require.LessOrEqual(t, int64(received), capture.BytesCaptured())
+			if i%2 == 0 {
+				require.Zero(t, received)
+				require.Zero(t, capture.BytesCaptured())
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("iteration %d: Chunks never closed", i)
+		}
+		require.NoError(t, capture.Err())
+	}
+}
+
 type sourcePort struct {
 	name      string
 	available uint32
@@ -182,3 +380,25 @@ func setSourcePorts(t *testing.T, reply *pulseproto.GetSourceInfoReply, ports []
 	replyValue := reflect.ValueOf(reply).Elem().FieldByName("Ports")
 	replyValue.Set(sliceValue)
 }
+
+func TestListDevicesUsesConfiguredClientIdentity(t *testing.T) {
+	var got ClientIdentity
+	connect := func(id ClientIdentity) (*pulse.Client, error) {
+		got = id
+		return nil, errors.New("pulse unavailable")
+	}
+
+	_, err := listDevices(ClientIdentity{AppName: "sotto-work"}, connect)
+	require.ErrorContains(t, err, "pulse unavailable")
+	require.Equal(t, "sotto-work", got.AppName)
+}
+
+func TestClientIdentityDefaults(t *testing.T) {
+	cfg := config.Default().Audio
+	cfg.PulseAppName = "sotto-work"
+	cfg.PulseIcon = " "
+
+	id := IdentityFromConfig(cfg).withDefaults()
+	require.Equal(t, "sotto-work", id.AppName)
+	require.Equal(t, DefaultClientIdentity.IconName, id.IconName)
+}
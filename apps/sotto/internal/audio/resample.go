@@ -0,0 +1,181 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// resampleZeroCrossings is how many sinc zero crossings each side of the
+// kernel spans at the output cutoff; eight keeps the passband flat to well
+// past the speech band at a few dozen taps per sample.
+const resampleZeroCrossings = 8
+
+// Resampler converts mono s16le PCM between sample rates with a polyphase
+// windowed-sinc filter. It keeps state between Process calls, so a stream can
+// be fed in arbitrary chunks and produce the same output as one large call.
+type Resampler struct {
+	inRate  int64
+	outRate int64
+	gcd     int64
+
+	halfTaps int
+	// phases holds one normalized kernel per fractional input offset; taps
+	// cover input samples floor(t)-halfTaps+1 .. floor(t)+halfTaps.
+	phases [][]float64
+
+	// history starts at absolute input sample index base; samples before
+	// index zero read as silence.
+	history []float64
+	base    int64
+	inputs  int64
+	next    int64
+	odd     []byte
+}
+
+// NewResampler returns a resampler from inRate to outRate (Hz). Equal rates
+// pass PCM through unchanged.
+func NewResampler(inRate int, outRate int) *Resampler {
+	in, out := int64(inRate), int64(outRate)
+	g := gcd(in, out)
+	r := &Resampler{inRate: in, outRate: out, gcd: g}
+	if in == out {
+		return r
+	}
+
+	// Cutoff relative to the input Nyquist; downsampling narrows it to the
+	// output Nyquist so content above it does not alias.
+	cutoff := 1.0
+	if out < in {
+		cutoff = float64(out) / float64(in)
+	}
+	r.halfTaps = int(math.Ceil(resampleZeroCrossings / cutoff))
+
+	phaseCount := out / g
+	r.phases = make([][]float64, phaseCount)
+	for p := range r.phases {
+		frac := float64(int64(p)*g) / float64(out)
+		taps := make([]float64, 2*r.halfTaps)
+		var sum float64
+		for i := range taps {
+			d := frac - float64(i-r.halfTaps+1)
+			taps[i] = cutoff * sinc(cutoff*d) * blackman(d/float64(r.halfTaps))
+			sum += taps[i]
+		}
+		for i := range taps {
+			taps[i] /= sum
+		}
+		r.phases[p] = taps
+	}
+
+	r.history = make([]float64, r.halfTaps)
+	r.base = -int64(r.halfTaps)
+	return r
+}
+
+// Process consumes s16le input and returns the resampled PCM available so
+// far. Output lags input by halfTaps samples until Flush.
+func (r *Resampler) Process(pcm []byte) []byte {
+	if r.inRate == r.outRate {
+		return append([]byte(nil), pcm...)
+	}
+
+	if len(r.odd) > 0 {
+		pcm = append(r.odd, pcm...)
+		r.odd = nil
+	}
+	if len(pcm)%2 != 0 {
+		r.odd = []byte{pcm[len(pcm)-1]}
+		pcm = pcm[:len(pcm)-1]
+	}
+	for i := 0; i+1 < len(pcm); i += 2 {
+		r.history = append(r.history, float64(int16(binary.LittleEndian.Uint16(pcm[i:]))))
+	}
+	r.inputs += int64(len(pcm) / 2)
+
+	return r.drain()
+}
+
+// Flush pads the input with silence and returns the remaining output, so the
+// total output length is ceil(inputs*outRate/inRate). It ends the stream;
+// Process must not be called afterwards.
+func (r *Resampler) Flush() []byte {
+	if r.inRate == r.outRate {
+		return nil
+	}
+	r.odd = nil
+	r.history = append(r.history, make([]float64, r.halfTaps)...)
+	return r.drain()
+}
+
+// drain emits every output sample whose kernel is fully inside history and
+// whose position falls before the end of real input, then drops history the
+// next output no longer needs.
+func (r *Resampler) drain() []byte {
+	var out []byte
+	available := r.base + int64(len(r.history))
+	for {
+		pos := r.next * r.inRate
+		if pos >= r.inputs*r.outRate {
+			break
+		}
+		whole := pos / r.outRate
+		if whole+int64(r.halfTaps) >= available {
+			break
+		}
+		taps := r.phases[(pos%r.outRate)/r.gcd]
+		start := int(whole - int64(r.halfTaps) + 1 - r.base)
+
+		var acc float64
+		for i, tap := range taps {
+			acc += r.history[start+i] * tap
+		}
+		out = binary.LittleEndian.AppendUint16(out, uint16(clampInt16(acc)))
+		r.next++
+	}
+
+	keepFrom := (r.next*r.inRate)/r.outRate - int64(r.halfTaps) + 1
+	if drop := keepFrom - r.base; drop > 0 {
+		if drop > int64(len(r.history)) {
+			drop = int64(len(r.history))
+		}
+		r.history = append(r.history[:0], r.history[drop:]...)
+		r.base += drop
+	}
+	return out
+}
+
+// sinc is the normalized sinc function sin(pi x)/(pi x).
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman is a Blackman window over x in [-1, 1], zero outside.
+func blackman(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return 0.42 + 0.5*math.Cos(math.Pi*x) + 0.08*math.Cos(2*math.Pi*x)
+}
+
+// clampInt16 rounds v to the nearest int16, saturating at the type limits.
+func clampInt16(v float64) int16 {
+	v = math.Round(v)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
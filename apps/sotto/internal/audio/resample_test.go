@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResamplerOutputLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		inRate  int
+		samples int
+		want    int
+	}{
+		{name: "48k one second", inRate: 48000, samples: 48000, want: 16000},
+		{name: "44.1k one second", inRate: 44100, samples: 44100, want: 16000},
+		{name: "44.1k partial", inRate: 44100, samples: 1000, want: 363},
+		{name: "8k upsample", inRate: 8000, samples: 8000, want: 16000},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewResampler(tc.inRate, 16000)
+			out := r.Process(pcmBytes(make([]int16, tc.samples)))
+			out = append(out, r.Flush()...)
+			require.Len(t, out, tc.want*2)
+		})
+	}
+}
+
+func TestResamplerPassthroughAtEqualRates(t *testing.T) {
+	pcm := pcmBytes([]int16{1, -2, 300, -400})
+	r := NewResampler(16000, 16000)
+	require.Equal(t, pcm, r.Process(pcm))
+	require.Empty(t, r.Flush())
+}
+
+func TestResamplerChunkingMatchesSingleCall(t *testing.T) {
+	pcm := pcmBytes(sine(44100, 440, 4410, 8000))
+
+	whole := NewResampler(44100, 16000)
+	want := append(whole.Process(pcm), whole.Flush()...)
+
+	chunked := NewResampler(44100, 16000)
+	var got []byte
+	for rest, size := pcm, 1; len(rest) > 0; size = size%977 + 3 {
+		n := min(size, len(rest))
+		got = append(got, chunked.Process(rest[:n])...)
+		rest = rest[n:]
+	}
+	got = append(got, chunked.Flush()...)
+
+	require.Equal(t, want, got)
+}
+
+func TestResamplerPreservesTone(t *testing.T) {
+	r := NewResampler(48000, 16000)
+	out := r.Process(pcmBytes(sine(48000, 1000, 48000, 10000)))
+	out = append(out, r.Flush()...)
+
+	want := sine(16000, 1000, 16000, 10000)
+	// Skip the edges, where the kernel overlaps the implicit silence.
+	var maxErr float64
+	for i := 100; i < len(want)-100; i++ {
+		got := int16(binary.LittleEndian.Uint16(out[i*2:]))
+		maxErr = math.Max(maxErr, math.Abs(float64(got)-float64(want[i])))
+	}
+	require.Less(t, maxErr, 50.0)
+}
+
+func TestResamplerRejectsAboveNyquist(t *testing.T) {
+	// 12kHz is above the 8kHz output Nyquist and must not alias into band.
+	r := NewResampler(48000, 16000)
+	out := r.Process(pcmBytes(sine(48000, 12000, 48000, 10000)))
+	out = append(out, r.Flush()...)
+
+	var peak float64
+	for i := 100; i < len(out)/2-100; i++ {
+		peak = math.Max(peak, math.Abs(float64(int16(binary.LittleEndian.Uint16(out[i*2:])))))
+	}
+	require.Less(t, peak, 100.0)
+}
+
+func sine(rate int, freq float64, samples int, amplitude float64) []int16 {
+	out := make([]int16, samples)
+	for i := range out {
+		out[i] = int16(amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	return out
+}
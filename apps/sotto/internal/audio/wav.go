@@ -0,0 +1,65 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReadPCM16WAV returns the sample bytes of a WAV file in the capture format:
+// 16 kHz mono s16le PCM. Chunks other than fmt and data (e.g. LIST/INFO from
+// debug dumps) are skipped.
+func ReadPCM16WAV(r io.Reader) ([]byte, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("wav: read header: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, errors.New("wav: not a RIFF/WAVE file")
+	}
+
+	sawFormat := false
+	for {
+		chunkHeader := make([]byte, 8)
+		if _, err := io.ReadFull(r, chunkHeader); err != nil {
+			return nil, fmt.Errorf("wav: no data chunk: %w", err)
+		}
+		id := string(chunkHeader[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunkHeader[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("wav: fmt chunk too short (%d bytes)", size)
+			}
+			body := make([]byte, size+size%2) // chunks are padded to even sizes
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, fmt.Errorf("wav: read fmt chunk: %w", err)
+			}
+			format := binary.LittleEndian.Uint16(body[0:2])
+			channels := binary.LittleEndian.Uint16(body[2:4])
+			sampleRate := binary.LittleEndian.Uint32(body[4:8])
+			bits := binary.LittleEndian.Uint16(body[14:16])
+			if format != 1 || channels != 1 || sampleRate != 16000 || bits != 16 {
+				return nil, fmt.Errorf("wav: want 16000 Hz mono 16-bit PCM, got format %d, %d Hz, %d channel(s), %d-bit", format, sampleRate, channels, bits)
+			}
+			sawFormat = true
+		case "data":
+			if !sawFormat {
+				return nil, errors.New("wav: data chunk before fmt chunk")
+			}
+			// The header size is untrusted: read at most that much and
+			// tolerate a truncated recording, keeping whole samples only.
+			pcm, err := io.ReadAll(io.LimitReader(r, size))
+			if err != nil {
+				return nil, fmt.Errorf("wav: read data chunk: %w", err)
+			}
+			return pcm[:len(pcm)-len(pcm)%2], nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+				return nil, fmt.Errorf("wav: skip %q chunk: %w", id, err)
+			}
+		}
+	}
+}
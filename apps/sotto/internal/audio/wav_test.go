@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPCM16WAVSkipsInfoChunk(t *testing.T) {
+	pcm := pcmBytes([]int16{1, -2, 300, -400})
+	wav := buildWAV(16000, 1, []byte("LIST\x05\x00\x00\x00INFOx\x00"), pcm)
+
+	got, err := ReadPCM16WAV(bytes.NewReader(wav))
+	require.NoError(t, err)
+	require.Equal(t, pcm, got)
+}
+
+func TestReadPCM16WAVRejectsOtherFormats(t *testing.T) {
+	_, err := ReadPCM16WAV(bytes.NewReader(buildWAV(44100, 2, nil, []byte{0, 0})))
+	require.ErrorContains(t, err, "want 16000 Hz mono 16-bit PCM, got format 1, 44100 Hz, 2 channel(s)")
+
+	_, err = ReadPCM16WAV(bytes.NewReader([]byte("not a wav file at all")))
+	require.ErrorContains(t, err, "not a RIFF/WAVE file")
+}
+
+func TestReadPCM16WAVToleratesTruncatedData(t *testing.T) {
+	wav := buildWAV(16000, 1, nil, pcmBytes([]int16{7, 8, 9}))
+	got, err := ReadPCM16WAV(bytes.NewReader(wav[:len(wav)-3]))
+	require.NoError(t, err)
+	require.Equal(t, pcmBytes([]int16{7}), got)
+}
+
+func TestReadPCM16WAVIgnoresOversizedDataHeader(t *testing.T) {
+	pcm := pcmBytes([]int16{1, 2})
+	wav := buildWAV(16000, 1, nil, pcm)
+	binary.LittleEndian.PutUint32(wav[40:44], 0xFFFFFFFF)
+
+	got, err := ReadPCM16WAV(bytes.NewReader(wav))
+	require.NoError(t, err)
+	require.Equal(t, pcm, got)
+}
+
+func buildWAV(sampleRate int, channels int, extra []byte, pcm []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(36+len(extra)+len(pcm)))
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*2))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(channels*2))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.Write(extra)
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(pcm)))
+	buf.Write(pcm)
+	return buf.Bytes()
+}
@@ -4,39 +4,99 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Command is the user-facing subcommand vocabulary for the CLI.
 type Command string
 
 const (
-	CommandToggle  Command = "toggle"
-	CommandStop    Command = "stop"
-	CommandCancel  Command = "cancel"
-	CommandStatus  Command = "status"
-	CommandDevices Command = "devices"
-	CommandDoctor  Command = "doctor"
-	CommandVersion Command = "version"
-	CommandHelp    Command = "help"
+	CommandToggle       Command = "toggle"
+	CommandPTTStart     Command = "ptt-start"
+	CommandPTTStop      Command = "ptt-stop"
+	CommandStop         Command = "stop"
+	CommandFlush        Command = "flush"
+	CommandDaemon       Command = "daemon"
+	CommandWarmupStatus Command = "warmup-status"
+	CommandCancel       Command = "cancel"
+	CommandStatus       Command = "status"
+	CommandLast         Command = "last"
+	CommandDevices      Command = "devices"
+	CommandPaths        Command = "paths"
+	CommandBench        Command = "bench"
+	CommandTestCue      Command = "test-cue"
+	CommandVocab        Command = "vocab"
+	CommandMonitor      Command = "monitor"
+	CommandDoctor       Command = "doctor"
+	CommandVersion      Command = "version"
+	CommandHelp         Command = "help"
 )
 
 var validCommands = map[Command]struct{}{
-	CommandToggle:  {},
-	CommandStop:    {},
-	CommandCancel:  {},
-	CommandStatus:  {},
-	CommandDevices: {},
-	CommandDoctor:  {},
-	CommandVersion: {},
-	CommandHelp:    {},
+	CommandToggle:       {},
+	CommandPTTStart:     {},
+	CommandPTTStop:      {},
+	CommandStop:         {},
+	CommandFlush:        {},
+	CommandDaemon:       {},
+	CommandWarmupStatus: {},
+	CommandCancel:       {},
+	CommandStatus:       {},
+	CommandLast:         {},
+	CommandDevices:      {},
+	CommandPaths:        {},
+	CommandBench:        {},
+	CommandTestCue:      {},
+	CommandVocab:        {},
+	CommandMonitor:      {},
+	CommandDoctor:       {},
+	CommandVersion:      {},
+	CommandHelp:         {},
 }
 
 // Parsed contains normalized argument parsing output.
 type Parsed struct {
 	Command    Command
 	ConfigPath string
+	// Strict turns config warnings into exit code 2.
+	Strict     bool
 	ShowHelp   bool
+	NoPaste    bool
+	AllDevices bool
+	JSON       bool
+	// Check makes version look up the latest release (update.url).
+	Check bool
+	// Warm makes daemon keep a pre-warmed Riva connection between sessions.
+	Warm bool
+	// Fix makes doctor apply safe remediations before running its checks.
+	Fix bool
+	// Punctuation is "on" or "off" to override asr.automatic_punctuation for
+	// one session, or empty to use the configured value.
+	Punctuation string
+	// Code enables transcript.code_mode for one session.
+	Code bool
+	// CueKind is the cue previewed by test-cue: start, stop, complete, or cancel.
+	CueKind string
+	// VocabFile is the phrase file checked by `vocab check FILE`; empty checks
+	// the config's vocab sets.
+	VocabFile string
+	// Model and Vocab override asr.model and vocab.global for one session when
+	// non-empty.
+	Model string
+	Vocab []string
+	// Phrases are ad-hoc speech contexts from repeated --phrase TERM[=BOOST]
+	// flags.
+	Phrases []Phrase
+	// BenchFile and BenchDuration pick the bench audio: a 16 kHz mono WAV, or
+	// live capture for the duration (default 5s).
+	BenchFile     string
+	BenchDuration time.Duration
+	// RivaGRPC and RivaHTTP override the configured endpoints when non-empty.
+	RivaGRPC string
+	RivaHTTP string
 }
 
 // Parse converts argv into a Parsed command contract with validation.
@@ -59,7 +119,30 @@ func Parse(args []string) (Parsed, error) {
 				return Parsed{}, errors.New("--config requires a path")
 			}
 			parsed.ConfigPath = args[i]
+		case "--riva-grpc":
+			i++
+			if i >= len(args) {
+				return Parsed{}, errors.New("--riva-grpc requires HOST:PORT")
+			}
+			if _, _, err := net.SplitHostPort(args[i]); err != nil {
+				return Parsed{}, fmt.Errorf("--riva-grpc must be HOST:PORT: %w", err)
+			}
+			parsed.RivaGRPC = args[i]
+		case "--riva-http":
+			i++
+			if i >= len(args) || strings.TrimSpace(args[i]) == "" {
+				return Parsed{}, errors.New("--riva-http requires an address")
+			}
+			parsed.RivaHTTP = args[i]
 		default:
+			next, ok, err := parseFlag(args, i, &parsed)
+			if err != nil {
+				return Parsed{}, err
+			}
+			if ok {
+				i = next
+				continue
+			}
 			if strings.HasPrefix(arg, "-") {
 				return Parsed{}, fmt.Errorf("unknown flag: %s", arg)
 			}
@@ -71,32 +154,312 @@ func Parse(args []string) (Parsed, error) {
 
 			parsed.Command = cmd
 			parsed.ShowHelp = cmd == CommandHelp
-			if i != len(args)-1 {
-				return Parsed{}, fmt.Errorf("unexpected arguments after command %q", arg)
+			remaining := args[i+1:]
+			if cmd == CommandTestCue {
+				if len(remaining) == 0 || strings.HasPrefix(remaining[0], "-") {
+					return Parsed{}, errors.New("test-cue requires a cue kind: start, stop, complete, or cancel")
+				}
+				kind, err := parseCueKind(remaining[0])
+				if err != nil {
+					return Parsed{}, err
+				}
+				parsed.CueKind = kind
+				remaining = remaining[1:]
+			}
+			if cmd == CommandVocab {
+				if len(remaining) == 0 || remaining[0] != "check" {
+					return Parsed{}, errors.New("vocab requires a subcommand: check [FILE]")
+				}
+				remaining = remaining[1:]
+				if len(remaining) > 0 && !strings.HasPrefix(remaining[0], "-") {
+					parsed.VocabFile = remaining[0]
+					remaining = remaining[1:]
+				}
 			}
+			for j := 0; j < len(remaining); j++ {
+				next, ok, err := parseFlag(remaining, j, &parsed)
+				if err != nil {
+					return Parsed{}, err
+				}
+				if !ok {
+					return Parsed{}, fmt.Errorf("unexpected arguments after command %q", arg)
+				}
+				j = next
+			}
+			i = len(args)
 		}
 	}
 
+	if parsed.NoPaste && !startsOrStopsSession(parsed.Command) {
+		return Parsed{}, errors.New("--no-paste is only valid with toggle, stop, ptt-start, or ptt-stop")
+	}
+	if parsed.AllDevices && parsed.Command != CommandDevices {
+		return Parsed{}, errors.New("--all is only valid with devices")
+	}
+	if parsed.JSON && parsed.Command != CommandLast && parsed.Command != CommandDevices && parsed.Command != CommandPaths && parsed.Command != CommandBench && parsed.Command != CommandToggle && parsed.Command != CommandVocab {
+		return Parsed{}, errors.New("--json is only valid with last, devices, paths, bench, toggle, or vocab")
+	}
+	if (parsed.BenchFile != "" || parsed.BenchDuration != 0) && parsed.Command != CommandBench {
+		return Parsed{}, errors.New("--file and --duration are only valid with bench")
+	}
+	if parsed.BenchFile != "" && parsed.BenchDuration != 0 {
+		return Parsed{}, errors.New("--file and --duration cannot be combined")
+	}
+	if parsed.Check && parsed.Command != CommandVersion {
+		return Parsed{}, errors.New("--check is only valid with version")
+	}
+	if parsed.Warm && parsed.Command != CommandDaemon {
+		return Parsed{}, errors.New("--warm is only valid with daemon")
+	}
+	if parsed.Fix && parsed.Command != CommandDoctor {
+		return Parsed{}, errors.New("--fix is only valid with doctor")
+	}
+	if parsed.Punctuation != "" && parsed.Command != CommandToggle && parsed.Command != CommandPTTStart {
+		return Parsed{}, errors.New("--punctuation is only valid with toggle or ptt-start")
+	}
+	if parsed.Code && parsed.Command != CommandToggle && parsed.Command != CommandPTTStart {
+		return Parsed{}, errors.New("--code is only valid with toggle or ptt-start")
+	}
+	if (parsed.Model != "" || parsed.Vocab != nil) && parsed.Command != CommandToggle && parsed.Command != CommandPTTStart {
+		return Parsed{}, errors.New("--model and --vocab are only valid with toggle or ptt-start")
+	}
+	if parsed.Phrases != nil && parsed.Command != CommandToggle && parsed.Command != CommandPTTStart {
+		return Parsed{}, errors.New("--phrase is only valid with toggle or ptt-start")
+	}
+
 	return parsed, nil
 }
 
+// parseFlag applies the command flag at args[i] to parsed, consuming the
+// following argument when the flag takes a value. It returns the index of the
+// last argument used, and false when args[i] is not a command flag. Both the
+// flags before and after the command go through it.
+func parseFlag(args []string, i int, parsed *Parsed) (int, bool, error) {
+	switch arg := args[i]; arg {
+	case "--model":
+		model, err := parseModel(args, i+1)
+		if err != nil {
+			return i, true, err
+		}
+		parsed.Model = model
+		return i + 1, true, nil
+	case "--vocab":
+		sets, err := parseVocabSets(args, i+1)
+		if err != nil {
+			return i, true, err
+		}
+		parsed.Vocab = sets
+		return i + 1, true, nil
+	case "--phrase":
+		phrase, err := parsePhrase(args, i+1)
+		if err != nil {
+			return i, true, err
+		}
+		parsed.Phrases = append(parsed.Phrases, phrase)
+		return i + 1, true, nil
+	case "--file":
+		if i+1 >= len(args) || strings.TrimSpace(args[i+1]) == "" {
+			return i, true, errors.New("--file requires a WAV path")
+		}
+		parsed.BenchFile = args[i+1]
+		return i + 1, true, nil
+	case "--duration":
+		duration, err := parseBenchDuration(args, i+1)
+		if err != nil {
+			return i, true, err
+		}
+		parsed.BenchDuration = duration
+		return i + 1, true, nil
+	case "--strict":
+		parsed.Strict = true
+	case "--no-paste":
+		parsed.NoPaste = true
+	case "--all":
+		parsed.AllDevices = true
+	case "--json":
+		parsed.JSON = true
+	case "--check":
+		parsed.Check = true
+	case "--warm":
+		parsed.Warm = true
+	case "--fix":
+		parsed.Fix = true
+	case "--code":
+		parsed.Code = true
+	default:
+		if !strings.HasPrefix(arg, "--punctuation=") {
+			return i, false, nil
+		}
+		value, err := parsePunctuation(arg)
+		if err != nil {
+			return i, true, err
+		}
+		parsed.Punctuation = value
+	}
+	return i, true, nil
+}
+
+// startsOrStopsSession reports whether cmd starts or commits a recording.
+func startsOrStopsSession(cmd Command) bool {
+	switch cmd {
+	case CommandToggle, CommandStop, CommandPTTStart, CommandPTTStop:
+		return true
+	default:
+		return false
+	}
+}
+
+// parsePunctuation validates a --punctuation=on|off flag and returns its value.
+func parsePunctuation(arg string) (string, error) {
+	value := strings.ToLower(strings.TrimPrefix(arg, "--punctuation="))
+	switch value {
+	case "on", "off":
+		return value, nil
+	default:
+		return "", fmt.Errorf("--punctuation must be on or off, got %q", value)
+	}
+}
+
+// parseModel reads the --model value at args[i].
+func parseModel(args []string, i int) (string, error) {
+	if i >= len(args) || strings.TrimSpace(args[i]) == "" || strings.HasPrefix(args[i], "-") {
+		return "", errors.New("--model requires a model name")
+	}
+	return strings.TrimSpace(args[i]), nil
+}
+
+// parseVocabSets reads the comma-separated --vocab value at args[i].
+func parseVocabSets(args []string, i int) ([]string, error) {
+	if i >= len(args) || strings.HasPrefix(args[i], "-") {
+		return nil, errors.New("--vocab requires a comma-separated list of vocab set names")
+	}
+	sets := make([]string, 0)
+	for _, name := range strings.Split(args[i], ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		sets = append(sets, name)
+	}
+	if len(sets) == 0 {
+		return nil, errors.New("--vocab requires a comma-separated list of vocab set names")
+	}
+	return sets, nil
+}
+
+// Phrase is one --phrase speech context.
+type Phrase struct {
+	Term string
+	// Boost is zero when the flag gave none, meaning asr.default_boost.
+	Boost float32
+}
+
+// parsePhrase reads the --phrase TERM[=BOOST] value at args[i]. The boost is
+// split at the last '=' so terms may contain colons, as in "10:30 standup".
+func parsePhrase(args []string, i int) (Phrase, error) {
+	if i >= len(args) || strings.HasPrefix(args[i], "-") {
+		return Phrase{}, errors.New("--phrase requires TERM or TERM=BOOST")
+	}
+	term := strings.TrimSpace(args[i])
+	var boost float64
+	if idx := strings.LastIndex(term, "="); idx >= 0 {
+		value, err := strconv.ParseFloat(strings.TrimSpace(term[idx+1:]), 32)
+		if err != nil || value <= 0 {
+			return Phrase{}, fmt.Errorf("--phrase boost must be a number > 0, got %q", term[idx+1:])
+		}
+		term = strings.TrimSpace(term[:idx])
+		boost = value
+	}
+	if term == "" {
+		return Phrase{}, errors.New("--phrase requires TERM or TERM=BOOST")
+	}
+	return Phrase{Term: term, Boost: float32(boost)}, nil
+}
+
+// parseBenchDuration reads the --duration value at args[i]: whole or
+// fractional seconds, or a Go duration such as 1500ms.
+func parseBenchDuration(args []string, i int) (time.Duration, error) {
+	if i >= len(args) {
+		return 0, errors.New("--duration requires a number of seconds")
+	}
+	raw := strings.TrimSpace(args[i])
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, ferr := strconv.ParseFloat(raw, 64)
+		if ferr != nil {
+			return 0, fmt.Errorf("--duration must be seconds or a duration like 1500ms, got %q", raw)
+		}
+		duration = time.Duration(seconds * float64(time.Second))
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("--duration must be > 0, got %q", raw)
+	}
+	return duration, nil
+}
+
+// parseCueKind validates the test-cue kind argument.
+func parseCueKind(arg string) (string, error) {
+	kind := strings.ToLower(arg)
+	switch kind {
+	case "start", "stop", "complete", "cancel":
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown cue kind %q (want start, stop, complete, or cancel)", arg)
+	}
+}
+
 // HelpText returns full usage text shown for --help and parse errors.
 func HelpText(binaryName string) string {
 	return fmt.Sprintf(`Usage:
-  %[1]s [--config PATH] <command>
+  %[1]s [--config PATH] [--strict] [--riva-grpc HOST:PORT] [--riva-http ADDR] <command> [--strict] [--no-paste] [--punctuation=on|off] [--code] [--model NAME] [--vocab SET,...] [--phrase TERM[=BOOST]] [--all] [--file PATH] [--duration N] [--json] [--check] [--warm] [--fix]
 
 Commands:
   toggle    Start recording or stop+commit when already recording
   stop      Stop active recording and commit transcript
+  flush     Commit the transcript so far and keep recording
+  ptt-start Push-to-talk key-down: start recording (no-op while recording)
+  ptt-stop  Push-to-talk key-up: stop recording and commit transcript
   cancel    Cancel active recording and discard transcript
-  status    Print current state
-  devices   List available input devices
-  doctor    Run configuration and environment checks
-  version   Print version information
+  daemon    Run a long-lived owner that serves toggle/ptt-* across sessions
+  warmup-status
+            Print the daemon's pre-warmed Riva connection state (cold, dialing, ready, failed)
+  status    Print current state ("stopped" when no owner is running)
+  last      Print the most recently committed transcript
+  devices   List selectable input devices (audio.allow/audio.deny applied)
+  paths     Print resolved config, state, debug, socket, and log paths
+  bench     Measure dial, first-partial, final, and total latency against Riva
+  test-cue <start|stop|complete|cancel>
+            Play one indicator cue to preview sound output
+  vocab check [FILE]
+            Report phrase counts, dedupe collisions, boosts, and the vocab.max_phrases
+            headroom of the config's vocab sets or one phrase file
+  monitor   Show live state, elapsed time, interim text, and captured audio until Ctrl-C
+  doctor    Run configuration and environment checks (--fix repairs what it safely can)
+  version   Print version information (--check compares against the latest release)
   help      Show this help
 
 Flags:
   --config PATH   Config file path (default: $XDG_CONFIG_HOME/sotto/config.jsonc)
+  --strict        Exit 2 after printing config warnings (for CI config checks)
+  --riva-grpc HOST:PORT
+                  Override riva_grpc for this invocation
+  --riva-http ADDR
+                  Override riva_http for this invocation
+  --no-paste      Set the clipboard only for this session (toggle/stop/ptt-*)
+  --punctuation=on|off
+                  Override asr.automatic_punctuation for this session (toggle/ptt-start)
+  --code          Turn spoken symbol words into symbols for this session (toggle/ptt-start)
+  --model NAME    Override asr.model for this session (toggle/ptt-start)
+  --vocab SET,... Override vocab.global for this session (toggle/ptt-start)
+  --phrase TERM[=BOOST]
+                  Add a speech context for this session; repeatable (toggle/ptt-start)
+  --all           Include devices hidden by audio.allow/audio.deny (devices)
+  --file PATH     Send a 16 kHz mono WAV instead of live audio (bench)
+  --duration N    Seconds of live capture to send, default 5 (bench)
+  --json          Print machine-readable JSON (last/devices/paths/bench/toggle/vocab)
+  --check         Report whether a newer release is available (version)
+  --warm          Keep a Riva connection ready between sessions (daemon)
+  --fix           Create a missing config and state dirs, remove a stale socket (doctor)
   -h, --help      Show help
   --version       Show version
 `, binaryName)
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -23,12 +25,30 @@ func TestParseCommandWithConfig(t *testing.T) {
 
 func TestParseArgMatrix(t *testing.T) {
 	tests := []struct {
-		name     string
-		args     []string
-		wantErr  string
-		wantCmd  Command
-		wantHelp bool
-		wantPath string
+		name          string
+		args          []string
+		wantErr       string
+		wantCmd       Command
+		wantHelp      bool
+		wantPath      string
+		wantNoPaste   bool
+		wantAll       bool
+		wantJSON      bool
+		wantPunct     string
+		wantCode      bool
+		wantCue       string
+		wantVocabFile string
+		wantCheck     bool
+		wantWarm      bool
+		wantFix       bool
+		wantStrict    bool
+		wantModel     string
+		wantVocab     []string
+		wantPhrases   []Phrase
+		wantFile      string
+		wantDur       time.Duration
+		wantGRPC      string
+		wantHTTP      string
 	}{
 		{
 			name:     "help short flag",
@@ -86,6 +106,345 @@ func TestParseArgMatrix(t *testing.T) {
 			wantHelp: false,
 			wantPath: "/tmp/cfg",
 		},
+		{
+			name:        "no-paste after toggle",
+			args:        []string{"toggle", "--no-paste"},
+			wantCmd:     CommandToggle,
+			wantNoPaste: true,
+		},
+		{
+			name:        "no-paste before stop",
+			args:        []string{"--no-paste", "stop"},
+			wantCmd:     CommandStop,
+			wantNoPaste: true,
+		},
+		{
+			name:    "no-paste rejected for status",
+			args:    []string{"status", "--no-paste"},
+			wantErr: "--no-paste is only valid with toggle, stop, ptt-start, or ptt-stop",
+		},
+		{
+			name:    "all after devices",
+			args:    []string{"devices", "--all"},
+			wantCmd: CommandDevices,
+			wantAll: true,
+		},
+		{
+			name:    "all rejected for toggle",
+			args:    []string{"--all", "toggle"},
+			wantErr: "only valid with devices",
+		},
+		{
+			name:      "punctuation override after toggle",
+			args:      []string{"toggle", "--punctuation=off"},
+			wantCmd:   CommandToggle,
+			wantPunct: "off",
+		},
+		{
+			name:        "punctuation override before toggle",
+			args:        []string{"--punctuation=ON", "toggle", "--no-paste"},
+			wantCmd:     CommandToggle,
+			wantPunct:   "on",
+			wantNoPaste: true,
+		},
+		{
+			name:    "punctuation invalid value",
+			args:    []string{"toggle", "--punctuation=maybe"},
+			wantErr: "--punctuation must be on or off",
+		},
+		{
+			name:    "punctuation requires toggle",
+			args:    []string{"stop", "--punctuation=on"},
+			wantErr: "--punctuation is only valid with toggle",
+		},
+		{
+			name:     "code mode for toggle",
+			args:     []string{"toggle", "--code"},
+			wantCmd:  CommandToggle,
+			wantCode: true,
+		},
+		{
+			name:    "code mode requires toggle",
+			args:    []string{"--code", "stop"},
+			wantErr: "--code is only valid with toggle or ptt-start",
+		},
+		{
+			name:        "ptt start with session flags",
+			args:        []string{"ptt-start", "--no-paste", "--punctuation=off"},
+			wantCmd:     CommandPTTStart,
+			wantNoPaste: true,
+			wantPunct:   "off",
+		},
+		{
+			name:    "ptt stop",
+			args:    []string{"ptt-stop"},
+			wantCmd: CommandPTTStop,
+		},
+		{
+			name:     "last with json",
+			args:     []string{"last", "--json"},
+			wantCmd:  CommandLast,
+			wantJSON: true,
+		},
+		{
+			name:     "devices with json",
+			args:     []string{"devices", "--all", "--json"},
+			wantCmd:  CommandDevices,
+			wantAll:  true,
+			wantJSON: true,
+		},
+		{
+			name:    "json requires last",
+			args:    []string{"status", "--json"},
+			wantErr: "--json is only valid with last, devices, paths, bench, toggle, or vocab",
+		},
+		{
+			name:     "toggle with json",
+			args:     []string{"toggle", "--json"},
+			wantCmd:  CommandToggle,
+			wantJSON: true,
+		},
+		{
+			name:     "riva endpoint overrides",
+			args:     []string{"--riva-grpc", "10.0.0.5:50051", "--riva-http", "10.0.0.5:9000", "doctor"},
+			wantCmd:  CommandDoctor,
+			wantGRPC: "10.0.0.5:50051",
+			wantHTTP: "10.0.0.5:9000",
+		},
+		{
+			name:    "daemon",
+			args:    []string{"daemon"},
+			wantCmd: CommandDaemon,
+		},
+		{
+			name:     "daemon warm",
+			args:     []string{"daemon", "--warm"},
+			wantCmd:  CommandDaemon,
+			wantWarm: true,
+		},
+		{
+			name:    "warm rejected for toggle",
+			args:    []string{"toggle", "--warm"},
+			wantErr: "--warm is only valid with daemon",
+		},
+		{
+			name:    "doctor fix",
+			args:    []string{"doctor", "--fix"},
+			wantCmd: CommandDoctor,
+			wantFix: true,
+		},
+		{
+			name:    "fix rejected for status",
+			args:    []string{"status", "--fix"},
+			wantErr: "--fix is only valid with doctor",
+		},
+		{
+			name:    "warmup status",
+			args:    []string{"warmup-status"},
+			wantCmd: CommandWarmupStatus,
+		},
+		{
+			name:    "flush",
+			args:    []string{"flush"},
+			wantCmd: CommandFlush,
+		},
+		{
+			name:    "flush rejects no-paste",
+			args:    []string{"flush", "--no-paste"},
+			wantErr: "--no-paste is only valid",
+		},
+		{
+			name:    "test cue",
+			args:    []string{"test-cue", "Complete"},
+			wantCmd: CommandTestCue,
+			wantCue: "complete",
+		},
+		{
+			name:    "test cue missing kind",
+			args:    []string{"test-cue"},
+			wantErr: "test-cue requires a cue kind",
+		},
+		{
+			name:    "test cue unknown kind",
+			args:    []string{"test-cue", "fallback"},
+			wantErr: `unknown cue kind "fallback"`,
+		},
+		{
+			name:    "test cue extra argument",
+			args:    []string{"test-cue", "stop", "start"},
+			wantErr: "unexpected arguments after command",
+		},
+		{
+			name:    "monitor",
+			args:    []string{"monitor"},
+			wantCmd: CommandMonitor,
+		},
+		{
+			name:    "vocab check config",
+			args:    []string{"vocab", "check"},
+			wantCmd: CommandVocab,
+		},
+		{
+			name:          "vocab check file json",
+			args:          []string{"vocab", "check", "names.txt", "--json"},
+			wantCmd:       CommandVocab,
+			wantVocabFile: "names.txt",
+			wantJSON:      true,
+		},
+		{
+			name:    "vocab missing subcommand",
+			args:    []string{"vocab"},
+			wantErr: "vocab requires a subcommand",
+		},
+		{
+			name:    "vocab extra argument",
+			args:    []string{"vocab", "check", "a.txt", "b.txt"},
+			wantErr: "unexpected arguments after command",
+		},
+		{
+			name:    "riva grpc missing port",
+			args:    []string{"--riva-grpc", "10.0.0.5", "doctor"},
+			wantErr: "must be HOST:PORT",
+		},
+		{
+			name:    "riva grpc missing value",
+			args:    []string{"--riva-grpc"},
+			wantErr: "requires HOST:PORT",
+		},
+		{
+			name:       "strict before doctor",
+			args:       []string{"--strict", "--config", "/tmp/sotto.jsonc", "doctor"},
+			wantCmd:    CommandDoctor,
+			wantPath:   "/tmp/sotto.jsonc",
+			wantStrict: true,
+		},
+		{
+			name:       "strict after doctor",
+			args:       []string{"--config", "/tmp/sotto.jsonc", "doctor", "--strict"},
+			wantCmd:    CommandDoctor,
+			wantPath:   "/tmp/sotto.jsonc",
+			wantStrict: true,
+		},
+		{
+			name:     "paths with json",
+			args:     []string{"paths", "--json"},
+			wantCmd:  CommandPaths,
+			wantJSON: true,
+		},
+		{
+			name:      "check after version",
+			args:      []string{"version", "--check"},
+			wantCmd:   CommandVersion,
+			wantCheck: true,
+		},
+		{
+			name:    "check rejected for status",
+			args:    []string{"status", "--check"},
+			wantErr: "--check is only valid with version",
+		},
+		{
+			name:      "model and vocab after toggle",
+			args:      []string{"toggle", "--model", "parakeet-1.1b", "--vocab", "setA, setB"},
+			wantCmd:   CommandToggle,
+			wantModel: "parakeet-1.1b",
+			wantVocab: []string{"setA", "setB"},
+		},
+		{
+			name:      "model before ptt-start",
+			args:      []string{"--model", "canary-1b", "ptt-start"},
+			wantCmd:   CommandPTTStart,
+			wantModel: "canary-1b",
+		},
+		{
+			name:    "model requires a value",
+			args:    []string{"toggle", "--model"},
+			wantErr: "--model requires a model name",
+		},
+		{
+			name:    "vocab requires set names",
+			args:    []string{"toggle", "--vocab", ","},
+			wantErr: "--vocab requires a comma-separated list",
+		},
+		{
+			name:    "vocab rejected for stop",
+			args:    []string{"stop", "--vocab", "setA"},
+			wantErr: "--model and --vocab are only valid with toggle or ptt-start",
+		},
+		{
+			name:    "phrases with and without boost",
+			args:    []string{"toggle", "--phrase", "Ada Lovelace=15.5", "--phrase", "Kubernetes"},
+			wantCmd: CommandToggle,
+			wantPhrases: []Phrase{
+				{Term: "Ada Lovelace", Boost: 15.5},
+				{Term: "Kubernetes"},
+			},
+		},
+		{
+			name:        "phrase keeps colons in the term",
+			args:        []string{"--phrase", "10:30 standup=8", "ptt-start"},
+			wantCmd:     CommandPTTStart,
+			wantPhrases: []Phrase{{Term: "10:30 standup", Boost: 8}},
+		},
+		{
+			name:        "phrase with colon and no boost",
+			args:        []string{"toggle", "--phrase", "10:30"},
+			wantCmd:     CommandToggle,
+			wantPhrases: []Phrase{{Term: "10:30"}},
+		},
+		{
+			name:    "phrase boost must be numeric",
+			args:    []string{"toggle", "--phrase", "Ada=loud"},
+			wantErr: "--phrase boost must be a number > 0",
+		},
+		{
+			name:    "phrase requires a term",
+			args:    []string{"toggle", "--phrase", "=10"},
+			wantErr: "--phrase requires TERM or TERM=BOOST",
+		},
+		{
+			name:    "phrase rejected for stop",
+			args:    []string{"stop", "--phrase", "Ada"},
+			wantErr: "--phrase is only valid with toggle or ptt-start",
+		},
+		{
+			name:     "bench with file and json",
+			args:     []string{"bench", "--file", "sample.wav", "--json"},
+			wantCmd:  CommandBench,
+			wantFile: "sample.wav",
+			wantJSON: true,
+		},
+		{
+			name:    "bench duration in seconds",
+			args:    []string{"bench", "--duration", "2.5"},
+			wantCmd: CommandBench,
+			wantDur: 2500 * time.Millisecond,
+		},
+		{
+			name:    "bench duration as go duration",
+			args:    []string{"--duration", "1500ms", "bench"},
+			wantCmd: CommandBench,
+			wantDur: 1500 * time.Millisecond,
+		},
+		{
+			name:    "bench duration must be positive",
+			args:    []string{"bench", "--duration", "0"},
+			wantErr: "--duration must be > 0",
+		},
+		{
+			name:    "bench file and duration conflict",
+			args:    []string{"bench", "--file", "a.wav", "--duration", "3"},
+			wantErr: "--file and --duration cannot be combined",
+		},
+		{
+			name:    "file rejected outside bench",
+			args:    []string{"toggle", "--file", "a.wav"},
+			wantErr: "--file and --duration are only valid with bench",
+		},
+		{
+			name:    "riva http empty value",
+			args:    []string{"--riva-http", " ", "doctor"},
+			wantErr: "requires an address",
+		},
 	}
 
 	for _, tc := range tests {
@@ -101,6 +460,24 @@ func TestParseArgMatrix(t *testing.T) {
 			require.Equal(t, tc.wantCmd, parsed.Command)
 			require.Equal(t, tc.wantHelp, parsed.ShowHelp)
 			require.Equal(t, tc.wantPath, parsed.ConfigPath)
+			require.Equal(t, tc.wantNoPaste, parsed.NoPaste)
+			require.Equal(t, tc.wantAll, parsed.AllDevices)
+			require.Equal(t, tc.wantJSON, parsed.JSON)
+			require.Equal(t, tc.wantPunct, parsed.Punctuation)
+			require.Equal(t, tc.wantCode, parsed.Code)
+			require.Equal(t, tc.wantCue, parsed.CueKind)
+			require.Equal(t, tc.wantVocabFile, parsed.VocabFile)
+			require.Equal(t, tc.wantCheck, parsed.Check)
+			require.Equal(t, tc.wantWarm, parsed.Warm)
+			require.Equal(t, tc.wantFix, parsed.Fix)
+			require.Equal(t, tc.wantStrict, parsed.Strict)
+			require.Equal(t, tc.wantModel, parsed.Model)
+			require.Equal(t, tc.wantVocab, parsed.Vocab)
+			require.Equal(t, tc.wantPhrases, parsed.Phrases)
+			require.Equal(t, tc.wantFile, parsed.BenchFile)
+			require.Equal(t, tc.wantDur, parsed.BenchDuration)
+			require.Equal(t, tc.wantGRPC, parsed.RivaGRPC)
+			require.Equal(t, tc.wantHTTP, parsed.RivaHTTP)
 		})
 	}
 }
@@ -113,3 +490,28 @@ func TestHelpTextIncludesCoreCommands(t *testing.T) {
 	require.Contains(t, text, "doctor")
 	require.Contains(t, text, "--config PATH")
 }
+
+func TestParseFlagsBeforeAndAfterCommandMatch(t *testing.T) {
+	flags := [][]string{
+		{"--model", "parakeet"},
+		{"--vocab", "core,team"},
+		{"--phrase", "Hyprland=20"},
+		{"--punctuation=off"},
+		{"--code"},
+		{"--no-paste"},
+	}
+	for _, flag := range flags {
+		before, err := Parse(append(append([]string{}, flag...), "toggle"))
+		require.NoError(t, err, flag)
+		after, err := Parse(append([]string{"toggle"}, flag...))
+		require.NoError(t, err, flag)
+		require.Equal(t, before, after, flag)
+	}
+}
+
+func TestHelpTextSynopsisListsCommandFlags(t *testing.T) {
+	synopsis := strings.SplitN(HelpText("sotto"), "\n", 3)[1]
+	for _, flag := range []string{"--phrase TERM[=BOOST]", "--file PATH", "--duration N", "--model NAME"} {
+		require.Contains(t, synopsis, flag)
+	}
+}
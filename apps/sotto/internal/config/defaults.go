@@ -1,40 +1,107 @@
 package config
 
+// DefaultPasteShortcutFormat is Hyprland's sendshortcut payload targeting the
+// active window by address.
+const DefaultPasteShortcutFormat = "{shortcut},address:{address}"
+
 // Default returns the canonical runtime configuration used when no file is present.
 func Default() Config {
 	clipboard := "wl-copy --trim-newline"
+	clipboardRead := "wl-paste --no-newline --type text"
+	clipboardRestore := "wl-copy --type text/plain"
 
 	return Config{
-		RivaGRPC:       "127.0.0.1:50051",
-		RivaHTTP:       "127.0.0.1:9000",
-		RivaHealthPath: "/v1/health/ready",
+		RivaGRPC:                   "127.0.0.1:50051",
+		RivaHTTP:                   "127.0.0.1:9000",
+		RivaHealthPath:             "/v1/health/ready",
+		RivaMaxRecvMB:              16,
+		RivaMaxSendMB:              16,
+		RivaKeepaliveMS:            60000,
+		RivaFirstResponseTimeoutMS: 0,
 		Audio: AudioConfig{
-			Input:    "default",
-			Fallback: "default",
+			Input:              "default",
+			Fallback:           "default",
+			PulseAppName:       "sotto",
+			PulseIcon:          "audio-input-microphone",
+			AllowMonitor:       false,
+			CommitOnDisconnect: false,
+			LatencyMS:          0,
+			Resampler:          "pulse",
 		},
-		Paste: PasteConfig{Enable: true, Shortcut: "CTRL,V"},
+		Paste: PasteConfig{Enable: true, Shortcut: "CTRL,V", ShortcutFormat: DefaultPasteShortcutFormat, WindowRetries: 5, WindowRetryMS: 10},
 		ASR: ASRConfig{
-			AutomaticPunctuation: true,
-			LanguageCode:         "en-US",
-			Model:                "",
+			AutomaticPunctuation:  true,
+			LanguageCode:          "en-US",
+			Model:                 "",
+			SpokenDigits:          false,
+			Verbatim:              false,
+			Encoding:              "linear_pcm",
+			DefaultBoost:          0,
+			InterimMaxAge:         2,
+			FinalDedupeWindow:     0,
+			InterimFuzzyThreshold: 0,
 		},
 		Transcript: TranscriptConfig{
-			TrailingSpace:       true,
-			CapitalizeSentences: true,
+			TrailingSpace:        true,
+			TrailingNewline:      false,
+			Capitalize:           "sentences",
+			SingleLine:           false,
+			TrimPolicy:           "both",
+			MinCommitChars:       0,
+			CollapseInitialisms:  false,
+			InitialismMinLetters: 3,
+			CodeMode:             false,
+			SymbolWords: map[string]string{
+				"dot":        ".",
+				"dash":       "-",
+				"underscore": "_",
+				"slash":      "/",
+				"colon":      ":",
+			},
 		},
 		Indicator: IndicatorConfig{
-			Enable:         true,
-			Backend:        "hypr",
-			DesktopAppName: "sotto-indicator",
-			SoundEnable:    true,
-			Height:         28,
-			ErrorTimeoutMS: 1600,
-		},
-		Clipboard: CommandConfig{Raw: clipboard, Argv: mustParseArgv(clipboard)},
+			Enable:              true,
+			Backend:             "hypr",
+			DesktopAppName:      "sotto-indicator",
+			SoundEnable:         true,
+			SoundSink:           "",
+			Height:              28,
+			ErrorTimeoutMS:      1600,
+			DispatchTimeoutMS:   400,
+			TranscribingDelayMS: 0,
+			TrackMonitor:        true,
+			ShowInterim:         false,
+		},
+		Clipboard:           CommandConfig{Raw: clipboard, Argv: mustParseArgv(clipboard)},
+		ClipboardReadCmd:    CommandConfig{Raw: clipboardRead, Argv: mustParseArgv(clipboardRead)},
+		ClipboardRestoreCmd: CommandConfig{Raw: clipboardRestore, Argv: mustParseArgv(clipboardRestore)},
+		Output: OutputConfig{
+			RecoverOnFailure:   true,
+			ClipboardEnable:    true,
+			RestoreClipboardMS: 0,
+			FIFOPath:           "",
+			History:            false,
+			ClipboardTimeoutMS: 2000,
+			PasteTimeoutMS:     0,
+		},
+		Session: SessionConfig{
+			PTTTimeoutMS:     120000,
+			PTTTimeoutAction: "cancel",
+			NoAudioAction:    "cancel",
+			ConfirmCancel:    false,
+		},
+		Owner: OwnerConfig{
+			IdleTimeoutMS: 0,
+		},
+		Update: UpdateConfig{
+			URL:     "https://api.github.com/repos/rbright/sotto/releases/latest",
+			Offline: false,
+		},
 		Vocab: VocabConfig{
-			GlobalSets: nil,
-			Sets:       map[string]VocabSet{},
-			MaxPhrases: 1024,
+			GlobalSets:            nil,
+			Sets:                  map[string]VocabSet{},
+			MaxPhrases:            1024,
+			CaseInsensitiveDedupe: true,
 		},
 		Debug: DebugConfig{},
 	}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -40,6 +41,7 @@ func Load(explicitPath string) (Loaded, error) {
 					content = legacyContent
 					loadedPath = legacyPath
 					warnings = append(warnings, Warning{
+						Code:    WarningLegacyPath,
 						Message: fmt.Sprintf("loaded legacy config path %q; migrate to %q (JSONC)", legacyPath, resolvedPath),
 					})
 				} else if !errors.Is(legacyErr, os.ErrNotExist) {
@@ -50,6 +52,7 @@ func Load(explicitPath string) (Loaded, error) {
 
 		if content == nil {
 			warnings = append(warnings, Warning{
+				Code:    WarningConfigMissing,
 				Message: fmt.Sprintf("config file %q not found; using defaults", resolvedPath),
 			})
 			return Loaded{
@@ -59,9 +62,13 @@ func Load(explicitPath string) (Loaded, error) {
 				Exists:   false,
 			}, nil
 		}
+	} else if strings.TrimSpace(explicitPath) == "" {
+		if w, ok := shadowedLegacyWarning(resolvedPath); ok {
+			warnings = append(warnings, w)
+		}
 	}
 
-	cfg, parseWarnings, err := Parse(string(content), base)
+	cfg, parseWarnings, err := ParseInDir(string(content), base, filepath.Dir(loadedPath))
 	if err != nil {
 		return Loaded{}, fmt.Errorf("parse config %q: %w", loadedPath, err)
 	}
@@ -1,14 +1,27 @@
 // Package config resolves, parses, validates, and defaults sotto configuration.
 package config
 
-import "strings"
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
 
 const legacyFormatWarning = "legacy key=value config format is deprecated; migrate to JSONC"
 
 // Parse reads configuration content as JSONC (preferred) or legacy key/value format.
 //
 // JSONC is selected when the first non-whitespace character is `{`.
+// Relative vocab set `file` paths resolve against the working directory.
 func Parse(content string, base Config) (Config, []Warning, error) {
+	return ParseInDir(content, base, "")
+}
+
+// ParseInDir is Parse with relative vocab set `file` paths resolved against dir,
+// normally the directory holding the config file.
+func ParseInDir(content string, base Config, dir string) (Config, []Warning, error) {
 	trimmed := strings.TrimSpace(content)
 	if trimmed == "" {
 		validatedWarnings, err := Validate(base)
@@ -19,13 +32,72 @@ func Parse(content string, base Config) (Config, []Warning, error) {
 	}
 
 	if strings.HasPrefix(trimmed, "{") {
-		return parseJSONC(content, base)
+		return parseJSONC(content, base, dir)
 	}
 
-	cfg, warnings, err := parseLegacy(content, base)
+	cfg, warnings, err := parseLegacy(content, base, dir)
 	if err != nil {
 		return Config{}, nil, err
 	}
-	warnings = append([]Warning{{Message: legacyFormatWarning}}, warnings...)
+	warnings = append([]Warning{{Code: WarningLegacyFormat, Message: legacyFormatWarning}}, warnings...)
 	return cfg, warnings, nil
 }
+
+// loadVocabFiles appends phrases from each vocab set's `file` to its inline
+// phrases. Files hold one phrase per line; blank lines and `#` comments are
+// skipped.
+func loadVocabFiles(cfg *Config, dir string) error {
+	for name, set := range cfg.Vocab.Sets {
+		if strings.TrimSpace(set.File) == "" {
+			continue
+		}
+
+		path := set.File
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+
+		phrases, err := readPhraseFile(path)
+		if err != nil {
+			return fmt.Errorf("vocabset %q: read phrase file %q: %w", name, path, err)
+		}
+
+		merged := make([]string, 0, len(set.Phrases)+len(phrases))
+		merged = append(merged, set.Phrases...)
+		merged = append(merged, phrases...)
+		set.Phrases = merged
+		cfg.Vocab.Sets[name] = set
+	}
+	return nil
+}
+
+func readPhraseFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	phrases := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		phrases = append(phrases, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return phrases, nil
+}
+
+// capitalizeModeFromBool maps the older transcript.capitalize_sentences boolean
+// onto transcript.capitalize.
+func capitalizeModeFromBool(enabled bool) string {
+	if enabled {
+		return "sentences"
+	}
+	return "none"
+}
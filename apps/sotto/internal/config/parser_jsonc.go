@@ -16,62 +16,140 @@ type jsoncConfig struct {
 	Transcript *jsoncTranscript `json:"transcript"`
 	Indicator  *jsoncIndicator  `json:"indicator"`
 
-	ClipboardCmd *string     `json:"clipboard_cmd"`
-	PasteCmd     *string     `json:"paste_cmd"`
-	Vocab        *jsoncVocab `json:"vocab"`
-	Debug        *jsoncDebug `json:"debug"`
+	ClipboardCmd        *jsoncCommandList `json:"clipboard_cmd"`
+	PasteCmd            *string           `json:"paste_cmd"`
+	ClipboardReadCmd    *string           `json:"clipboard_read_cmd"`
+	ClipboardRestoreCmd *string           `json:"clipboard_restore_cmd"`
+	Output              *jsoncOutput      `json:"output"`
+	Session             *jsoncSession     `json:"session"`
+	Owner               *jsoncOwner       `json:"owner"`
+	Update              *jsoncUpdate      `json:"update"`
+	Vocab               *jsoncVocab       `json:"vocab"`
+	Debug               *jsoncDebug       `json:"debug"`
 }
 
 type jsoncRiva struct {
-	GRPC       *string `json:"grpc"`
-	HTTP       *string `json:"http"`
-	HealthPath *string `json:"health_path"`
+	GRPC                   *string `json:"grpc"`
+	HTTP                   *string `json:"http"`
+	HealthPath             *string `json:"health_path"`
+	MaxRecvMB              *int    `json:"max_recv_mb"`
+	MaxSendMB              *int    `json:"max_send_mb"`
+	KeepaliveMS            *int    `json:"keepalive_ms"`
+	FirstResponseTimeoutMS *int    `json:"first_response_timeout_ms"`
 }
 
 type jsoncAudio struct {
-	Input    *string `json:"input"`
-	Fallback *string `json:"fallback"`
+	Input              *string          `json:"input"`
+	Fallback           *string          `json:"fallback"`
+	PulseAppName       *string          `json:"pulse_app_name"`
+	PulseIcon          *string          `json:"pulse_icon"`
+	Allow              *jsoncStringList `json:"allow"`
+	Deny               *jsoncStringList `json:"deny"`
+	AllowMonitor       *bool            `json:"allow_monitor"`
+	CommitOnDisconnect *bool            `json:"commit_on_disconnect"`
+	LatencyMS          *int             `json:"latency_ms"`
+	Resampler          *string          `json:"resampler"`
 }
 
 type jsoncPaste struct {
-	Enable   *bool   `json:"enable"`
-	Shortcut *string `json:"shortcut"`
+	Enable         *bool            `json:"enable"`
+	Shortcut       *string          `json:"shortcut"`
+	ShortcutFormat *string          `json:"shortcut_format"`
+	WindowRetries  *int             `json:"window_retries"`
+	WindowRetryMS  *int             `json:"window_retry_ms"`
+	AllowedClasses *jsoncStringList `json:"allowed_classes"`
+	DeniedClasses  *jsoncStringList `json:"denied_classes"`
 }
 
 type jsoncASR struct {
-	AutomaticPunctuation *bool   `json:"automatic_punctuation"`
-	LanguageCode         *string `json:"language_code"`
-	Model                *string `json:"model"`
+	AutomaticPunctuation  *bool    `json:"automatic_punctuation"`
+	LanguageCode          *string  `json:"language_code"`
+	Model                 *string  `json:"model"`
+	SpokenDigits          *bool    `json:"spoken_digits"`
+	Verbatim              *bool    `json:"verbatim"`
+	Encoding              *string  `json:"encoding"`
+	DefaultBoost          *float64 `json:"default_boost"`
+	InterimMaxAge         *int     `json:"interim_max_age"`
+	FinalDedupeWindow     *int     `json:"final_dedupe_window"`
+	InterimFuzzyThreshold *float64 `json:"interim_fuzzy_threshold"`
 }
 
 type jsoncTranscript struct {
-	TrailingSpace       *bool `json:"trailing_space"`
-	CapitalizeSentences *bool `json:"capitalize_sentences"`
+	TrailingSpace        *bool             `json:"trailing_space"`
+	TrailingNewline      *bool             `json:"trailing_newline"`
+	Capitalize           *string           `json:"capitalize"`
+	CapitalizeSentences  *bool             `json:"capitalize_sentences"`
+	SingleLine           *bool             `json:"single_line"`
+	TrimPolicy           *string           `json:"trim_policy"`
+	MinCommitChars       *int              `json:"min_commit_chars"`
+	CollapseInitialisms  *bool             `json:"collapse_initialisms"`
+	Initialisms          *jsoncStringList  `json:"initialisms"`
+	InitialismMinLetters *int              `json:"initialism_min_letters"`
+	PostprocessCmd       *string           `json:"postprocess_cmd"`
+	CodeMode             *bool             `json:"code_mode"`
+	SymbolWords          map[string]string `json:"symbol_words"`
 }
 
 type jsoncIndicator struct {
-	Enable         *bool   `json:"enable"`
-	Backend        *string `json:"backend"`
-	DesktopAppName *string `json:"desktop_app_name"`
-	SoundEnable    *bool   `json:"sound_enable"`
-	Height         *int    `json:"height"`
-	ErrorTimeoutMS *int    `json:"error_timeout_ms"`
+	Enable              *bool   `json:"enable"`
+	Backend             *string `json:"backend"`
+	DesktopAppName      *string `json:"desktop_app_name"`
+	SoundEnable         *bool   `json:"sound_enable"`
+	SoundSink           *string `json:"sound_sink"`
+	Height              *int    `json:"height"`
+	ErrorTimeoutMS      *int    `json:"error_timeout_ms"`
+	DispatchTimeoutMS   *int    `json:"dispatch_timeout_ms"`
+	TranscribingDelayMS *int    `json:"transcribing_delay_ms"`
+	TrackMonitor        *bool   `json:"track_monitor"`
+	ShowInterim         *bool   `json:"show_interim"`
 }
 
 type jsoncVocab struct {
-	Global     *jsoncStringList         `json:"global"`
-	MaxPhrases *int                     `json:"max_phrases"`
-	Sets       map[string]jsoncVocabSet `json:"sets"`
+	Global                *jsoncStringList         `json:"global"`
+	MaxPhrases            *int                     `json:"max_phrases"`
+	CaseInsensitiveDedupe *bool                    `json:"case_insensitive_dedupe"`
+	Sets                  map[string]jsoncVocabSet `json:"sets"`
 }
 
 type jsoncVocabSet struct {
 	Boost   *float64 `json:"boost"`
 	Phrases []string `json:"phrases"`
+	File    *string  `json:"file"`
+}
+
+type jsoncOutput struct {
+	RecoverOnFailure   *bool   `json:"recover_on_failure"`
+	ClipboardEnable    *bool   `json:"clipboard_enable"`
+	MaxClipboardBytes  *int    `json:"max_clipboard_bytes"`
+	RestoreClipboardMS *int    `json:"restore_clipboard_ms"`
+	FIFOPath           *string `json:"fifo_path"`
+	History            *bool   `json:"history"`
+	ClipboardTimeoutMS *int    `json:"clipboard_timeout_ms"`
+	PasteTimeoutMS     *int    `json:"paste_timeout_ms"`
+}
+
+type jsoncSession struct {
+	IdempotentStop   *bool   `json:"idempotent_stop"`
+	PTTTimeoutMS     *int    `json:"ptt_timeout_ms"`
+	PTTTimeoutAction *string `json:"ptt_timeout_action"`
+	NoAudioAction    *string `json:"no_audio_action"`
+	ConfirmCancel    *bool   `json:"confirm_cancel"`
+}
+
+type jsoncOwner struct {
+	IdleTimeoutMS *int `json:"idle_timeout_ms"`
+}
+
+type jsoncUpdate struct {
+	URL     *string `json:"url"`
+	Offline *bool   `json:"offline"`
 }
 
 type jsoncDebug struct {
-	AudioDump *bool `json:"audio_dump"`
-	GRPCDump  *bool `json:"grpc_dump"`
+	AudioDump   *bool   `json:"audio_dump"`
+	GRPCDump    *bool   `json:"grpc_dump"`
+	WAVMetadata *bool   `json:"wav_metadata"`
+	MetricsFile *string `json:"metrics_file"`
 }
 
 type jsoncStringList []string
@@ -101,7 +179,45 @@ func (l *jsoncStringList) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("expected string array or comma-delimited string")
 }
 
-func parseJSONC(content string, base Config) (Config, []Warning, error) {
+// trimmedList drops blank entries and surrounding whitespace.
+func trimmedList(list []string) []string {
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// symbolWordKey normalizes a transcript.symbol_words word; matching is
+// case-insensitive.
+func symbolWordKey(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
+
+// jsoncCommandList accepts a single command string or a prioritized array of them.
+type jsoncCommandList []string
+
+func (l *jsoncCommandList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = []string{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = list
+		return nil
+	}
+
+	return fmt.Errorf("expected command string or array of command strings")
+}
+
+func parseJSONC(content string, base Config, dir string) (Config, []Warning, error) {
 	normalized, err := normalizeJSONC(content)
 	if err != nil {
 		return Config{}, nil, err
@@ -123,6 +239,9 @@ func parseJSONC(content string, base Config) (Config, []Warning, error) {
 	if err != nil {
 		return Config{}, nil, err
 	}
+	if err := loadVocabFiles(&cfg, dir); err != nil {
+		return Config{}, nil, err
+	}
 
 	validatedWarnings, err := Validate(cfg)
 	if err != nil {
@@ -145,6 +264,18 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		if payload.Riva.HealthPath != nil {
 			cfg.RivaHealthPath = *payload.Riva.HealthPath
 		}
+		if payload.Riva.MaxRecvMB != nil {
+			cfg.RivaMaxRecvMB = *payload.Riva.MaxRecvMB
+		}
+		if payload.Riva.MaxSendMB != nil {
+			cfg.RivaMaxSendMB = *payload.Riva.MaxSendMB
+		}
+		if payload.Riva.KeepaliveMS != nil {
+			cfg.RivaKeepaliveMS = *payload.Riva.KeepaliveMS
+		}
+		if payload.Riva.FirstResponseTimeoutMS != nil {
+			cfg.RivaFirstResponseTimeoutMS = *payload.Riva.FirstResponseTimeoutMS
+		}
 	}
 
 	if payload.Audio != nil {
@@ -154,6 +285,30 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		if payload.Audio.Fallback != nil {
 			cfg.Audio.Fallback = *payload.Audio.Fallback
 		}
+		if payload.Audio.PulseAppName != nil {
+			cfg.Audio.PulseAppName = strings.TrimSpace(*payload.Audio.PulseAppName)
+		}
+		if payload.Audio.PulseIcon != nil {
+			cfg.Audio.PulseIcon = strings.TrimSpace(*payload.Audio.PulseIcon)
+		}
+		if payload.Audio.Allow != nil {
+			cfg.Audio.Allow = trimmedList(*payload.Audio.Allow)
+		}
+		if payload.Audio.Deny != nil {
+			cfg.Audio.Deny = trimmedList(*payload.Audio.Deny)
+		}
+		if payload.Audio.AllowMonitor != nil {
+			cfg.Audio.AllowMonitor = *payload.Audio.AllowMonitor
+		}
+		if payload.Audio.CommitOnDisconnect != nil {
+			cfg.Audio.CommitOnDisconnect = *payload.Audio.CommitOnDisconnect
+		}
+		if payload.Audio.LatencyMS != nil {
+			cfg.Audio.LatencyMS = *payload.Audio.LatencyMS
+		}
+		if payload.Audio.Resampler != nil {
+			cfg.Audio.Resampler = strings.ToLower(strings.TrimSpace(*payload.Audio.Resampler))
+		}
 	}
 
 	if payload.Paste != nil {
@@ -163,6 +318,21 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		if payload.Paste.Shortcut != nil {
 			cfg.Paste.Shortcut = strings.TrimSpace(*payload.Paste.Shortcut)
 		}
+		if payload.Paste.ShortcutFormat != nil {
+			cfg.Paste.ShortcutFormat = strings.TrimSpace(*payload.Paste.ShortcutFormat)
+		}
+		if payload.Paste.WindowRetries != nil {
+			cfg.Paste.WindowRetries = *payload.Paste.WindowRetries
+		}
+		if payload.Paste.WindowRetryMS != nil {
+			cfg.Paste.WindowRetryMS = *payload.Paste.WindowRetryMS
+		}
+		if payload.Paste.AllowedClasses != nil {
+			cfg.Paste.AllowedClasses = trimmedList(*payload.Paste.AllowedClasses)
+		}
+		if payload.Paste.DeniedClasses != nil {
+			cfg.Paste.DeniedClasses = trimmedList(*payload.Paste.DeniedClasses)
+		}
 	}
 
 	if payload.ASR != nil {
@@ -175,14 +345,76 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		if payload.ASR.Model != nil {
 			cfg.ASR.Model = *payload.ASR.Model
 		}
+		if payload.ASR.SpokenDigits != nil {
+			cfg.ASR.SpokenDigits = *payload.ASR.SpokenDigits
+		}
+		if payload.ASR.Verbatim != nil {
+			cfg.ASR.Verbatim = *payload.ASR.Verbatim
+		}
+		if payload.ASR.Encoding != nil {
+			cfg.ASR.Encoding = strings.ToLower(strings.TrimSpace(*payload.ASR.Encoding))
+		}
+		if payload.ASR.DefaultBoost != nil {
+			cfg.ASR.DefaultBoost = *payload.ASR.DefaultBoost
+		}
+		if payload.ASR.InterimMaxAge != nil {
+			cfg.ASR.InterimMaxAge = *payload.ASR.InterimMaxAge
+		}
+		if payload.ASR.FinalDedupeWindow != nil {
+			cfg.ASR.FinalDedupeWindow = *payload.ASR.FinalDedupeWindow
+		}
+		if payload.ASR.InterimFuzzyThreshold != nil {
+			cfg.ASR.InterimFuzzyThreshold = *payload.ASR.InterimFuzzyThreshold
+		}
 	}
 
 	if payload.Transcript != nil {
 		if payload.Transcript.TrailingSpace != nil {
 			cfg.Transcript.TrailingSpace = *payload.Transcript.TrailingSpace
 		}
+		if payload.Transcript.TrailingNewline != nil {
+			cfg.Transcript.TrailingNewline = *payload.Transcript.TrailingNewline
+		}
 		if payload.Transcript.CapitalizeSentences != nil {
-			cfg.Transcript.CapitalizeSentences = *payload.Transcript.CapitalizeSentences
+			cfg.Transcript.Capitalize = capitalizeModeFromBool(*payload.Transcript.CapitalizeSentences)
+		}
+		if payload.Transcript.Capitalize != nil {
+			cfg.Transcript.Capitalize = strings.ToLower(strings.TrimSpace(*payload.Transcript.Capitalize))
+		}
+		if payload.Transcript.SingleLine != nil {
+			cfg.Transcript.SingleLine = *payload.Transcript.SingleLine
+		}
+		if payload.Transcript.TrimPolicy != nil {
+			cfg.Transcript.TrimPolicy = strings.ToLower(strings.TrimSpace(*payload.Transcript.TrimPolicy))
+		}
+		if payload.Transcript.MinCommitChars != nil {
+			cfg.Transcript.MinCommitChars = *payload.Transcript.MinCommitChars
+		}
+		if payload.Transcript.CollapseInitialisms != nil {
+			cfg.Transcript.CollapseInitialisms = *payload.Transcript.CollapseInitialisms
+		}
+		if payload.Transcript.Initialisms != nil {
+			cfg.Transcript.Initialisms = trimmedList(*payload.Transcript.Initialisms)
+		}
+		if payload.Transcript.InitialismMinLetters != nil {
+			cfg.Transcript.InitialismMinLetters = *payload.Transcript.InitialismMinLetters
+		}
+		if payload.Transcript.PostprocessCmd != nil {
+			raw := *payload.Transcript.PostprocessCmd
+			argv, err := parseArgv(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid transcript.postprocess_cmd: %w", err)
+			}
+			cfg.Transcript.PostprocessCmd = CommandConfig{Raw: raw, Argv: argv}
+		}
+		if payload.Transcript.CodeMode != nil {
+			cfg.Transcript.CodeMode = *payload.Transcript.CodeMode
+		}
+		if payload.Transcript.SymbolWords != nil {
+			cfg.Transcript.SymbolWords = make(map[string]string, len(payload.Transcript.SymbolWords))
+			for word, symbol := range payload.Transcript.SymbolWords {
+				cfg.Transcript.SymbolWords[symbolWordKey(word)] = symbol
+			}
 		}
 	}
 
@@ -199,21 +431,44 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		if payload.Indicator.SoundEnable != nil {
 			cfg.Indicator.SoundEnable = *payload.Indicator.SoundEnable
 		}
+		if payload.Indicator.SoundSink != nil {
+			cfg.Indicator.SoundSink = strings.TrimSpace(*payload.Indicator.SoundSink)
+		}
 		if payload.Indicator.Height != nil {
 			cfg.Indicator.Height = *payload.Indicator.Height
 		}
 		if payload.Indicator.ErrorTimeoutMS != nil {
 			cfg.Indicator.ErrorTimeoutMS = *payload.Indicator.ErrorTimeoutMS
 		}
+		if payload.Indicator.DispatchTimeoutMS != nil {
+			cfg.Indicator.DispatchTimeoutMS = *payload.Indicator.DispatchTimeoutMS
+		}
+		if payload.Indicator.TranscribingDelayMS != nil {
+			cfg.Indicator.TranscribingDelayMS = *payload.Indicator.TranscribingDelayMS
+		}
+		if payload.Indicator.TrackMonitor != nil {
+			cfg.Indicator.TrackMonitor = *payload.Indicator.TrackMonitor
+		}
+		if payload.Indicator.ShowInterim != nil {
+			cfg.Indicator.ShowInterim = *payload.Indicator.ShowInterim
+		}
 	}
 
 	if payload.ClipboardCmd != nil {
-		raw := *payload.ClipboardCmd
-		argv, err := parseArgv(raw)
-		if err != nil {
-			return nil, fmt.Errorf("invalid clipboard_cmd: %w", err)
+		commands := make([]CommandConfig, 0, len(*payload.ClipboardCmd))
+		for _, raw := range *payload.ClipboardCmd {
+			argv, err := parseArgv(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid clipboard_cmd: %w", err)
+			}
+			commands = append(commands, CommandConfig{Raw: raw, Argv: argv})
+		}
+		cfg.Clipboard = CommandConfig{}
+		cfg.ClipboardFallbacks = nil
+		if len(commands) > 0 {
+			cfg.Clipboard = commands[0]
+			cfg.ClipboardFallbacks = commands[1:]
 		}
-		cfg.Clipboard = CommandConfig{Raw: raw, Argv: argv}
 	}
 
 	if payload.PasteCmd != nil {
@@ -225,6 +480,24 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		cfg.PasteCmd = CommandConfig{Raw: raw, Argv: argv}
 	}
 
+	if payload.ClipboardReadCmd != nil {
+		raw := *payload.ClipboardReadCmd
+		argv, err := parseArgv(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clipboard_read_cmd: %w", err)
+		}
+		cfg.ClipboardReadCmd = CommandConfig{Raw: raw, Argv: argv}
+	}
+
+	if payload.ClipboardRestoreCmd != nil {
+		raw := *payload.ClipboardRestoreCmd
+		argv, err := parseArgv(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clipboard_restore_cmd: %w", err)
+		}
+		cfg.ClipboardRestoreCmd = CommandConfig{Raw: raw, Argv: argv}
+	}
+
 	if payload.Vocab != nil {
 		if payload.Vocab.Global != nil {
 			cfg.Vocab.GlobalSets = cfg.Vocab.GlobalSets[:0]
@@ -239,6 +512,9 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		if payload.Vocab.MaxPhrases != nil {
 			cfg.Vocab.MaxPhrases = *payload.Vocab.MaxPhrases
 		}
+		if payload.Vocab.CaseInsensitiveDedupe != nil {
+			cfg.Vocab.CaseInsensitiveDedupe = *payload.Vocab.CaseInsensitiveDedupe
+		}
 		if payload.Vocab.Sets != nil {
 			if cfg.Vocab.Sets == nil {
 				cfg.Vocab.Sets = make(map[string]VocabSet)
@@ -256,11 +532,72 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 				if set.Boost != nil {
 					entry.Boost = *set.Boost
 				}
+				if set.File != nil {
+					entry.File = *set.File
+				}
 				cfg.Vocab.Sets[trimmedName] = entry
 			}
 		}
 	}
 
+	if payload.Output != nil {
+		if payload.Output.RecoverOnFailure != nil {
+			cfg.Output.RecoverOnFailure = *payload.Output.RecoverOnFailure
+		}
+		if payload.Output.ClipboardEnable != nil {
+			cfg.Output.ClipboardEnable = *payload.Output.ClipboardEnable
+		}
+		if payload.Output.MaxClipboardBytes != nil {
+			cfg.Output.MaxClipboardBytes = *payload.Output.MaxClipboardBytes
+		}
+		if payload.Output.RestoreClipboardMS != nil {
+			cfg.Output.RestoreClipboardMS = *payload.Output.RestoreClipboardMS
+		}
+		if payload.Output.FIFOPath != nil {
+			cfg.Output.FIFOPath = strings.TrimSpace(*payload.Output.FIFOPath)
+		}
+		if payload.Output.History != nil {
+			cfg.Output.History = *payload.Output.History
+		}
+		if payload.Output.ClipboardTimeoutMS != nil {
+			cfg.Output.ClipboardTimeoutMS = *payload.Output.ClipboardTimeoutMS
+		}
+		if payload.Output.PasteTimeoutMS != nil {
+			cfg.Output.PasteTimeoutMS = *payload.Output.PasteTimeoutMS
+		}
+	}
+
+	if payload.Session != nil {
+		if payload.Session.IdempotentStop != nil {
+			cfg.Session.IdempotentStop = *payload.Session.IdempotentStop
+		}
+		if payload.Session.PTTTimeoutMS != nil {
+			cfg.Session.PTTTimeoutMS = *payload.Session.PTTTimeoutMS
+		}
+		if payload.Session.PTTTimeoutAction != nil {
+			cfg.Session.PTTTimeoutAction = strings.ToLower(strings.TrimSpace(*payload.Session.PTTTimeoutAction))
+		}
+		if payload.Session.NoAudioAction != nil {
+			cfg.Session.NoAudioAction = strings.ToLower(strings.TrimSpace(*payload.Session.NoAudioAction))
+		}
+		if payload.Session.ConfirmCancel != nil {
+			cfg.Session.ConfirmCancel = *payload.Session.ConfirmCancel
+		}
+	}
+
+	if payload.Owner != nil && payload.Owner.IdleTimeoutMS != nil {
+		cfg.Owner.IdleTimeoutMS = *payload.Owner.IdleTimeoutMS
+	}
+
+	if payload.Update != nil {
+		if payload.Update.URL != nil {
+			cfg.Update.URL = strings.TrimSpace(*payload.Update.URL)
+		}
+		if payload.Update.Offline != nil {
+			cfg.Update.Offline = *payload.Update.Offline
+		}
+	}
+
 	if payload.Debug != nil {
 		if payload.Debug.AudioDump != nil {
 			cfg.Debug.EnableAudioDump = *payload.Debug.AudioDump
@@ -268,6 +605,12 @@ func (payload jsoncConfig) applyTo(cfg *Config) ([]Warning, error) {
 		if payload.Debug.GRPCDump != nil {
 			cfg.Debug.EnableGRPCDump = *payload.Debug.GRPCDump
 		}
+		if payload.Debug.WAVMetadata != nil {
+			cfg.Debug.WAVMetadata = *payload.Debug.WAVMetadata
+		}
+		if payload.Debug.MetricsFile != nil {
+			cfg.Debug.MetricsFile = strings.TrimSpace(*payload.Debug.MetricsFile)
+		}
 	}
 
 	return warnings, nil
@@ -81,18 +81,40 @@ func TestJSONCStringListUnmarshal(t *testing.T) {
 	require.Contains(t, err.Error(), "expected string array")
 }
 
+func TestParseJSONCClipboardCmdAcceptsStringOrArray(t *testing.T) {
+	cfg, _, err := parseJSONC(`{"clipboard_cmd":"xclip -selection clipboard"}`, Default(), "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"xclip", "-selection", "clipboard"}, cfg.Clipboard.Argv)
+	require.Empty(t, cfg.ClipboardFallbacks)
+
+	cfg, _, err = parseJSONC(`{"clipboard_cmd":["wl-copy --trim-newline","xclip -selection clipboard"]}`, Default(), "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"wl-copy", "--trim-newline"}, cfg.Clipboard.Argv)
+	require.Len(t, cfg.ClipboardFallbacks, 1)
+	require.Equal(t, []string{"xclip", "-selection", "clipboard"}, cfg.ClipboardFallbacks[0].Argv)
+
+	_, _, err = parseJSONC(`{"clipboard_cmd":[]}`, Default(), "")
+	require.ErrorContains(t, err, "clipboard_cmd must not be empty")
+
+	_, _, err = parseJSONC(`{"clipboard_cmd":["wl-copy",""]}`, Default(), "")
+	require.ErrorContains(t, err, "clipboard_cmd entries must not be empty")
+
+	_, _, err = parseJSONC(`{"clipboard_cmd":42}`, Default(), "")
+	require.Error(t, err)
+}
+
 func TestParseJSONCRejectsInvalidCommandArgv(t *testing.T) {
-	_, _, err := parseJSONC(`{"clipboard_cmd":"unterminated ' quote"}`, Default())
+	_, _, err := parseJSONC(`{"clipboard_cmd":"unterminated ' quote"}`, Default(), "")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid clipboard_cmd")
 
-	_, _, err = parseJSONC(`{"paste_cmd":"unterminated ' quote"}`, Default())
+	_, _, err = parseJSONC(`{"paste_cmd":"unterminated ' quote"}`, Default(), "")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid paste_cmd")
 }
 
 func TestParseJSONCVocabRejectsEmptySetName(t *testing.T) {
-	_, _, err := parseJSONC(`{"vocab":{"sets":{" ":{"phrases":["x"]}}}}`, Default())
+	_, _, err := parseJSONC(`{"vocab":{"sets":{" ":{"phrases":["x"]}}}}`, Default(), "")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "empty set name")
 }
@@ -104,7 +126,7 @@ func TestParseJSONCTrimsIndicatorAndPasteFields(t *testing.T) {
     "backend": " desktop ",
     "desktop_app_name": "  sotto-indicator  "
   }
-}`, Default())
+}`, Default(), "")
 	require.NoError(t, err)
 	require.Equal(t, "CTRL,V", cfg.Paste.Shortcut)
 	require.Equal(t, "desktop", cfg.Indicator.Backend)
@@ -112,7 +134,7 @@ func TestParseJSONCTrimsIndicatorAndPasteFields(t *testing.T) {
 }
 
 func TestParseJSONCRejectsMultipleTopLevelValues(t *testing.T) {
-	_, _, err := parseJSONC(`{"paste":{"enable":false}}{"paste":{"enable":true}}`, Default())
+	_, _, err := parseJSONC(`{"paste":{"enable":false}}{"paste":{"enable":true}}`, Default(), "")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "multiple JSON values")
 }
@@ -120,7 +142,7 @@ func TestParseJSONCRejectsMultipleTopLevelValues(t *testing.T) {
 func TestParseJSONCTypeErrorIncludesLocation(t *testing.T) {
 	_, _, err := parseJSONC(`{
   "riva": {"grpc": 123}
-}`, Default())
+}`, Default(), "")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "line")
 	require.Contains(t, err.Error(), "column")
@@ -136,7 +158,7 @@ func TestParseJSONCVocabGlobalSupportsCommaString(t *testing.T) {
       "three": {"phrases": ["three"]}
     }
   }
-}`, Default())
+}`, Default(), "")
 	require.NoError(t, err)
 	require.Equal(t, []string{"one", "two", "three"}, cfg.Vocab.GlobalSets)
 }
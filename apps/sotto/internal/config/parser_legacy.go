@@ -11,10 +11,13 @@ import (
 type parseState struct {
 	inVocabSet        *VocabSet
 	vocabSetStartLine int
+	// capitalizeLine is where transcript.capitalize or its boolean form was
+	// last set, so a later setting can warn that it replaces it.
+	capitalizeLine int
 }
 
 // parseLegacy applies the legacy line-oriented key/value config grammar.
-func parseLegacy(content string, base Config) (Config, []Warning, error) {
+func parseLegacy(content string, base Config, dir string) (Config, []Warning, error) {
 	cfg := base
 	warnings := make([]Warning, 0)
 	state := &parseState{}
@@ -56,6 +59,7 @@ func parseLegacy(content string, base Config) (Config, []Warning, error) {
 			if _, exists := cfg.Vocab.Sets[set.Name]; exists {
 				warnings = append(warnings, Warning{
 					Line:    line,
+					Code:    WarningVocabSetRedefined,
 					Message: fmt.Sprintf("vocabset %q redefined; last definition wins", set.Name),
 				})
 			}
@@ -68,6 +72,16 @@ func parseLegacy(content string, base Config) (Config, []Warning, error) {
 		if err != nil {
 			return Config{}, nil, lineError(line, err)
 		}
+		if key == "transcript.capitalize" || key == "transcript.capitalize_sentences" {
+			if state.capitalizeLine > 0 {
+				warnings = append(warnings, Warning{
+					Line:    line,
+					Code:    WarningCapitalizeRedefined,
+					Message: fmt.Sprintf("%s replaces the capitalization set on line %d; last setting wins", key, state.capitalizeLine),
+				})
+			}
+			state.capitalizeLine = line
+		}
 		if err := applyRootKey(&cfg, key, value); err != nil {
 			return Config{}, nil, lineError(line, err)
 		}
@@ -85,6 +99,10 @@ func parseLegacy(content string, base Config) (Config, []Warning, error) {
 		return Config{}, nil, fmt.Errorf("line %d: unterminated vocabset %q block", line, state.inVocabSet.Name)
 	}
 
+	if err := loadVocabFiles(&cfg, dir); err != nil {
+		return Config{}, nil, err
+	}
+
 	validatedWarnings, err := Validate(cfg)
 	if err != nil {
 		return Config{}, nil, err
@@ -143,6 +161,12 @@ func applyVocabSetKey(set *VocabSet, key, value string) error {
 			return err
 		}
 		set.Phrases = phrases
+	case "file":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		set.File = v
 	default:
 		return fmt.Errorf("unknown vocabset key %q", key)
 	}
@@ -170,6 +194,30 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return err
 		}
 		cfg.RivaHealthPath = v
+	case "riva_max_recv_mb":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for riva_max_recv_mb: %w", err)
+		}
+		cfg.RivaMaxRecvMB = n
+	case "riva_max_send_mb":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for riva_max_send_mb: %w", err)
+		}
+		cfg.RivaMaxSendMB = n
+	case "riva_keepalive_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for riva_keepalive_ms: %w", err)
+		}
+		cfg.RivaKeepaliveMS = n
+	case "riva_first_response_timeout_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for riva_first_response_timeout_ms: %w", err)
+		}
+		cfg.RivaFirstResponseTimeoutMS = n
 	case "audio.input":
 		v, err := parseStringValue(value)
 		if err != nil {
@@ -182,6 +230,54 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return err
 		}
 		cfg.Audio.Fallback = v
+	case "audio.pulse_app_name":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Audio.PulseAppName = strings.TrimSpace(v)
+	case "audio.pulse_icon":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Audio.PulseIcon = strings.TrimSpace(v)
+	case "audio.allow":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Audio.Allow = trimmedList(strings.Split(v, ","))
+	case "audio.deny":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Audio.Deny = trimmedList(strings.Split(v, ","))
+	case "audio.allow_monitor":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for audio.allow_monitor: %w", err)
+		}
+		cfg.Audio.AllowMonitor = b
+	case "audio.commit_on_disconnect":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for audio.commit_on_disconnect: %w", err)
+		}
+		cfg.Audio.CommitOnDisconnect = b
+	case "audio.latency_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for audio.latency_ms: %w", err)
+		}
+		cfg.Audio.LatencyMS = n
+	case "audio.resampler":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Audio.Resampler = strings.ToLower(strings.TrimSpace(v))
 	case "paste.enable":
 		b, err := strconv.ParseBool(value)
 		if err != nil {
@@ -194,6 +290,36 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return err
 		}
 		cfg.Paste.Shortcut = strings.TrimSpace(v)
+	case "paste.shortcut_format":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Paste.ShortcutFormat = strings.TrimSpace(v)
+	case "paste.window_retries":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for paste.window_retries: %w", err)
+		}
+		cfg.Paste.WindowRetries = n
+	case "paste.window_retry_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for paste.window_retry_ms: %w", err)
+		}
+		cfg.Paste.WindowRetryMS = n
+	case "paste.allowed_classes":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Paste.AllowedClasses = trimmedList(strings.Split(v, ","))
+	case "paste.denied_classes":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Paste.DeniedClasses = trimmedList(strings.Split(v, ","))
 	case "asr.automatic_punctuation":
 		b, err := strconv.ParseBool(value)
 		if err != nil {
@@ -212,18 +338,124 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return err
 		}
 		cfg.ASR.Model = v
+	case "asr.spoken_digits":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for asr.spoken_digits: %w", err)
+		}
+		cfg.ASR.SpokenDigits = b
+	case "asr.verbatim":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for asr.verbatim: %w", err)
+		}
+		cfg.ASR.Verbatim = b
+	case "asr.encoding":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.ASR.Encoding = strings.ToLower(strings.TrimSpace(v))
+	case "asr.default_boost":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid float for asr.default_boost: %w", err)
+		}
+		cfg.ASR.DefaultBoost = f
+	case "asr.interim_max_age":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for asr.interim_max_age: %w", err)
+		}
+		cfg.ASR.InterimMaxAge = n
+	case "asr.final_dedupe_window":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for asr.final_dedupe_window: %w", err)
+		}
+		cfg.ASR.FinalDedupeWindow = n
+	case "asr.interim_fuzzy_threshold":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid float for asr.interim_fuzzy_threshold: %w", err)
+		}
+		cfg.ASR.InterimFuzzyThreshold = f
 	case "transcript.trailing_space":
 		b, err := strconv.ParseBool(value)
 		if err != nil {
 			return fmt.Errorf("invalid bool for transcript.trailing_space: %w", err)
 		}
 		cfg.Transcript.TrailingSpace = b
+	case "transcript.trailing_newline":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for transcript.trailing_newline: %w", err)
+		}
+		cfg.Transcript.TrailingNewline = b
 	case "transcript.capitalize_sentences":
 		b, err := strconv.ParseBool(value)
 		if err != nil {
 			return fmt.Errorf("invalid bool for transcript.capitalize_sentences: %w", err)
 		}
-		cfg.Transcript.CapitalizeSentences = b
+		cfg.Transcript.Capitalize = capitalizeModeFromBool(b)
+	case "transcript.capitalize":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Transcript.Capitalize = strings.ToLower(strings.TrimSpace(v))
+	case "transcript.single_line":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for transcript.single_line: %w", err)
+		}
+		cfg.Transcript.SingleLine = b
+	case "transcript.min_commit_chars":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for transcript.min_commit_chars: %w", err)
+		}
+		cfg.Transcript.MinCommitChars = n
+	case "transcript.collapse_initialisms":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for transcript.collapse_initialisms: %w", err)
+		}
+		cfg.Transcript.CollapseInitialisms = b
+	case "transcript.initialisms":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Transcript.Initialisms = trimmedList(strings.Split(v, ","))
+	case "transcript.initialism_min_letters":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for transcript.initialism_min_letters: %w", err)
+		}
+		cfg.Transcript.InitialismMinLetters = n
+	case "transcript.postprocess_cmd":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		argv, err := parseArgv(v)
+		if err != nil {
+			return fmt.Errorf("invalid transcript.postprocess_cmd: %w", err)
+		}
+		cfg.Transcript.PostprocessCmd = CommandConfig{Raw: v, Argv: argv}
+	case "transcript.code_mode":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for transcript.code_mode: %w", err)
+		}
+		cfg.Transcript.CodeMode = b
+	case "transcript.trim_policy":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Transcript.TrimPolicy = strings.ToLower(strings.TrimSpace(v))
 	case "indicator.enable":
 		b, err := strconv.ParseBool(value)
 		if err != nil {
@@ -248,6 +480,12 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return fmt.Errorf("invalid bool for indicator.sound_enable: %w", err)
 		}
 		cfg.Indicator.SoundEnable = b
+	case "indicator.sound_sink":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Indicator.SoundSink = strings.TrimSpace(v)
 	case "indicator.height":
 		n, err := strconv.Atoi(value)
 		if err != nil {
@@ -260,6 +498,30 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return fmt.Errorf("invalid int for indicator.error_timeout_ms: %w", err)
 		}
 		cfg.Indicator.ErrorTimeoutMS = n
+	case "indicator.dispatch_timeout_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for indicator.dispatch_timeout_ms: %w", err)
+		}
+		cfg.Indicator.DispatchTimeoutMS = n
+	case "indicator.transcribing_delay_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for indicator.transcribing_delay_ms: %w", err)
+		}
+		cfg.Indicator.TranscribingDelayMS = n
+	case "indicator.track_monitor":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for indicator.track_monitor: %w", err)
+		}
+		cfg.Indicator.TrackMonitor = b
+	case "indicator.show_interim":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for indicator.show_interim: %w", err)
+		}
+		cfg.Indicator.ShowInterim = b
 	case "clipboard_cmd":
 		v, err := parseStringValue(value)
 		if err != nil {
@@ -270,6 +532,7 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return fmt.Errorf("invalid clipboard_cmd: %w", err)
 		}
 		cfg.Clipboard = CommandConfig{Raw: v, Argv: argv}
+		cfg.ClipboardFallbacks = nil
 	case "paste_cmd":
 		v, err := parseStringValue(value)
 		if err != nil {
@@ -280,6 +543,26 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return fmt.Errorf("invalid paste_cmd: %w", err)
 		}
 		cfg.PasteCmd = CommandConfig{Raw: v, Argv: argv}
+	case "clipboard_read_cmd":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		argv, err := parseArgv(v)
+		if err != nil {
+			return fmt.Errorf("invalid clipboard_read_cmd: %w", err)
+		}
+		cfg.ClipboardReadCmd = CommandConfig{Raw: v, Argv: argv}
+	case "clipboard_restore_cmd":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		argv, err := parseArgv(v)
+		if err != nil {
+			return fmt.Errorf("invalid clipboard_restore_cmd: %w", err)
+		}
+		cfg.ClipboardRestoreCmd = CommandConfig{Raw: v, Argv: argv}
 	case "vocab.global":
 		sets := strings.Split(value, ",")
 		cfg.Vocab.GlobalSets = cfg.Vocab.GlobalSets[:0]
@@ -296,6 +579,108 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return fmt.Errorf("invalid int for vocab.max_phrases: %w", err)
 		}
 		cfg.Vocab.MaxPhrases = n
+	case "vocab.case_insensitive_dedupe":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for vocab.case_insensitive_dedupe: %w", err)
+		}
+		cfg.Vocab.CaseInsensitiveDedupe = b
+	case "output.recover_on_failure":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for output.recover_on_failure: %w", err)
+		}
+		cfg.Output.RecoverOnFailure = b
+	case "output.clipboard_enable":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for output.clipboard_enable: %w", err)
+		}
+		cfg.Output.ClipboardEnable = b
+	case "output.max_clipboard_bytes":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for output.max_clipboard_bytes: %w", err)
+		}
+		cfg.Output.MaxClipboardBytes = n
+	case "output.restore_clipboard_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for output.restore_clipboard_ms: %w", err)
+		}
+		cfg.Output.RestoreClipboardMS = n
+	case "output.fifo_path":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Output.FIFOPath = strings.TrimSpace(v)
+	case "output.history":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for output.history: %w", err)
+		}
+		cfg.Output.History = b
+	case "output.clipboard_timeout_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for output.clipboard_timeout_ms: %w", err)
+		}
+		cfg.Output.ClipboardTimeoutMS = n
+	case "output.paste_timeout_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for output.paste_timeout_ms: %w", err)
+		}
+		cfg.Output.PasteTimeoutMS = n
+	case "session.idempotent_stop":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for session.idempotent_stop: %w", err)
+		}
+		cfg.Session.IdempotentStop = b
+	case "session.confirm_cancel":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for session.confirm_cancel: %w", err)
+		}
+		cfg.Session.ConfirmCancel = b
+	case "session.ptt_timeout_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for session.ptt_timeout_ms: %w", err)
+		}
+		cfg.Session.PTTTimeoutMS = n
+	case "session.ptt_timeout_action":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Session.PTTTimeoutAction = strings.ToLower(strings.TrimSpace(v))
+	case "session.no_audio_action":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Session.NoAudioAction = strings.ToLower(strings.TrimSpace(v))
+	case "owner.idle_timeout_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid int for owner.idle_timeout_ms: %w", err)
+		}
+		cfg.Owner.IdleTimeoutMS = n
+	case "update.url":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Update.URL = strings.TrimSpace(v)
+	case "update.offline":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for update.offline: %w", err)
+		}
+		cfg.Update.Offline = b
 	case "debug.audio_dump":
 		b, err := strconv.ParseBool(value)
 		if err != nil {
@@ -308,7 +693,31 @@ func applyRootKey(cfg *Config, key, value string) error {
 			return fmt.Errorf("invalid bool for debug.grpc_dump: %w", err)
 		}
 		cfg.Debug.EnableGRPCDump = b
+	case "debug.wav_metadata":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool for debug.wav_metadata: %w", err)
+		}
+		cfg.Debug.WAVMetadata = b
+	case "debug.metrics_file":
+		v, err := parseStringValue(value)
+		if err != nil {
+			return err
+		}
+		cfg.Debug.MetricsFile = strings.TrimSpace(v)
 	default:
+		// transcript.symbol_words.<word> = "<symbol>" adds or overrides one word.
+		if word, ok := strings.CutPrefix(key, "transcript.symbol_words."); ok {
+			v, err := parseStringValue(value)
+			if err != nil {
+				return err
+			}
+			if cfg.Transcript.SymbolWords == nil {
+				cfg.Transcript.SymbolWords = make(map[string]string)
+			}
+			cfg.Transcript.SymbolWords[symbolWordKey(word)] = v
+			return nil
+		}
 		return fmt.Errorf("unknown key %q", key)
 	}
 
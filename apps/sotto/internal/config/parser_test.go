@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -85,16 +87,30 @@ paste.enable = false
 		t.Fatalf("expected paste.enable=false")
 	}
 
-	found := false
+	if len(warnings) == 0 || warnings[0].Code != WarningLegacyFormat {
+		t.Fatalf("expected legacy format warning, warnings=%+v", warnings)
+	}
+}
+
+func TestParseLegacyVocabSetRedefinedWarningCode(t *testing.T) {
+	_, warnings, err := Parse(`vocabset team {
+  phrases = ["sotto"]
+}
+vocabset team {
+  phrases = ["Riva"]
+}
+`, Default())
+	require.NoError(t, err)
+
+	var redefined []Warning
 	for _, w := range warnings {
-		if strings.Contains(w.Message, "legacy") {
-			found = true
-			break
+		if w.Code == WarningVocabSetRedefined {
+			redefined = append(redefined, w)
 		}
 	}
-	if !found {
-		t.Fatalf("expected legacy format warning, warnings=%+v", warnings)
-	}
+	require.Len(t, redefined, 1)
+	require.Equal(t, 4, redefined[0].Line)
+	require.Contains(t, redefined[0].Message, `vocabset "team" redefined`)
 }
 
 func TestParseJSONCUnknownKeyFails(t *testing.T) {
@@ -178,13 +194,878 @@ func TestParsePasteShortcut(t *testing.T) {
 func TestParseTranscriptCapitalizeSentencesJSONC(t *testing.T) {
 	cfg, _, err := Parse(`{"transcript":{"capitalize_sentences":false}}`, Default())
 	require.NoError(t, err)
-	require.False(t, cfg.Transcript.CapitalizeSentences)
+	require.Equal(t, "none", cfg.Transcript.Capitalize)
+}
+
+func TestParseTranscriptCapitalizeJSONC(t *testing.T) {
+	require.Equal(t, "sentences", Default().Transcript.Capitalize)
+
+	cfg, _, err := Parse(`{"transcript":{"capitalize":" First "}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "first", cfg.Transcript.Capitalize)
+
+	// The mode wins over the older boolean when both are set.
+	cfg, _, err = Parse(`{"transcript":{"capitalize":"first","capitalize_sentences":false}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "first", cfg.Transcript.Capitalize)
+
+	_, _, err = Parse(`{"transcript":{"capitalize":"title"}}`, Default())
+	require.ErrorContains(t, err, "transcript.capitalize")
+}
+
+func TestParseTranscriptCapitalizeLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.capitalize = none\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "none", cfg.Transcript.Capitalize)
+
+	cfg, _, err = Parse("transcript.capitalize_sentences = true\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "sentences", cfg.Transcript.Capitalize)
+}
+
+func TestParseTranscriptCapitalizeLegacyRedefinedWarningCode(t *testing.T) {
+	cfg, warnings, err := Parse("transcript.capitalize = first\ntranscript.capitalize_sentences = false\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "none", cfg.Transcript.Capitalize)
+
+	var redefined []Warning
+	for _, w := range warnings {
+		if w.Code == WarningCapitalizeRedefined {
+			redefined = append(redefined, w)
+		}
+	}
+	require.Len(t, redefined, 1)
+	require.Equal(t, 2, redefined[0].Line)
+	require.Contains(t, redefined[0].Message, "replaces the capitalization set on line 1")
+}
+
+func TestParseAudioPulseIdentityJSONC(t *testing.T) {
+	cfg, _, err := Parse(`{"audio":{"pulse_app_name":"sotto-work","pulse_icon":"microphone-sensitivity-high"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "sotto-work", cfg.Audio.PulseAppName)
+	require.Equal(t, "microphone-sensitivity-high", cfg.Audio.PulseIcon)
+}
+
+func TestParseAudioPulseIdentityLegacy(t *testing.T) {
+	cfg, _, err := Parse("audio.pulse_app_name = \"sotto-work\"\naudio.pulse_icon = \"microphone-sensitivity-high\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "sotto-work", cfg.Audio.PulseAppName)
+	require.Equal(t, "microphone-sensitivity-high", cfg.Audio.PulseIcon)
+}
+
+func TestParsePasteWindowRetriesJSONC(t *testing.T) {
+	cfg, _, err := Parse(`{"paste":{"window_retries":12,"window_retry_ms":50}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 12, cfg.Paste.WindowRetries)
+	require.Equal(t, 50, cfg.Paste.WindowRetryMS)
+}
+
+func TestParsePasteWindowRetriesLegacy(t *testing.T) {
+	cfg, _, err := Parse("paste.window_retries = 12\npaste.window_retry_ms = 50\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 12, cfg.Paste.WindowRetries)
+	require.Equal(t, 50, cfg.Paste.WindowRetryMS)
+}
+
+func TestParsePasteShortcutFormatJSONC(t *testing.T) {
+	require.Equal(t, "{shortcut},address:{address}", Default().Paste.ShortcutFormat)
+
+	cfg, _, err := Parse(`{"paste":{"shortcut_format":"{shortcut},class:{class}"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "{shortcut},class:{class}", cfg.Paste.ShortcutFormat)
+}
+
+func TestParsePasteShortcutFormatLegacy(t *testing.T) {
+	cfg, _, err := Parse("paste.shortcut_format = \"{shortcut},class:{class}\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "{shortcut},class:{class}", cfg.Paste.ShortcutFormat)
+}
+
+func TestParsePasteClassListsJSONC(t *testing.T) {
+	require.Empty(t, Default().Paste.AllowedClasses)
+	require.Empty(t, Default().Paste.DeniedClasses)
+
+	cfg, _, err := Parse(`{"paste":{"allowed_classes":["ghostty", " "],"denied_classes":"KeePassXC, 1Password"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghostty"}, cfg.Paste.AllowedClasses)
+	require.Equal(t, []string{"KeePassXC", "1Password"}, cfg.Paste.DeniedClasses)
+}
+
+func TestParsePasteClassListsLegacy(t *testing.T) {
+	cfg, _, err := Parse("paste.allowed_classes = ghostty\npaste.denied_classes = \"KeePassXC, 1Password\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghostty"}, cfg.Paste.AllowedClasses)
+	require.Equal(t, []string{"KeePassXC", "1Password"}, cfg.Paste.DeniedClasses)
+}
+
+func TestParseASRSpokenDigitsJSONC(t *testing.T) {
+	cfg, _, err := Parse(`{"asr":{"spoken_digits":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.ASR.SpokenDigits)
+}
+
+func TestParseASRSpokenDigitsLegacy(t *testing.T) {
+	cfg, _, err := Parse("asr.spoken_digits = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.ASR.SpokenDigits)
+}
+
+func TestParseASRVerbatimJSONC(t *testing.T) {
+	require.False(t, Default().ASR.Verbatim)
+
+	cfg, _, err := Parse(`{"asr":{"verbatim":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.ASR.Verbatim)
+}
+
+func TestParseASRVerbatimLegacy(t *testing.T) {
+	cfg, _, err := Parse("asr.verbatim = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.ASR.Verbatim)
+
+	_, _, err = Parse("asr.verbatim = exact\n", Default())
+	require.ErrorContains(t, err, "invalid bool for asr.verbatim")
+}
+
+func TestParseTranscriptSingleLineJSONC(t *testing.T) {
+	cfg, _, err := Parse(`{"transcript":{"single_line":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.SingleLine)
+}
+
+func TestParseTranscriptSingleLineLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.single_line = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.SingleLine)
+
+	_, _, err = Parse("transcript.single_line = maybe\n", Default())
+	require.Error(t, err)
+}
+
+func TestParseAudioAllowDenyJSONC(t *testing.T) {
+	cfg, _, err := Parse(`{"audio":{"allow":["elgato", " "],"deny":"monitor, loopback"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, []string{"elgato"}, cfg.Audio.Allow)
+	require.Equal(t, []string{"monitor", "loopback"}, cfg.Audio.Deny)
+}
+
+func TestParseAudioAllowDenyLegacy(t *testing.T) {
+	cfg, _, err := Parse("audio.allow = elgato\naudio.deny = \"monitor, loopback\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, []string{"elgato"}, cfg.Audio.Allow)
+	require.Equal(t, []string{"monitor", "loopback"}, cfg.Audio.Deny)
+}
+
+func TestParseAudioAllowMonitorJSONC(t *testing.T) {
+	require.False(t, Default().Audio.AllowMonitor)
+
+	cfg, _, err := Parse(`{"audio":{"allow_monitor":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Audio.AllowMonitor)
+}
+
+func TestParseAudioAllowMonitorLegacy(t *testing.T) {
+	cfg, _, err := Parse("audio.allow_monitor = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Audio.AllowMonitor)
+
+	_, _, err = Parse("audio.allow_monitor = maybe\n", Default())
+	require.Error(t, err)
+}
+
+func TestParseAudioCommitOnDisconnectJSONC(t *testing.T) {
+	require.False(t, Default().Audio.CommitOnDisconnect)
+
+	cfg, _, err := Parse(`{"audio":{"commit_on_disconnect":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Audio.CommitOnDisconnect)
+}
+
+func TestParseAudioCommitOnDisconnectLegacy(t *testing.T) {
+	cfg, _, err := Parse("audio.commit_on_disconnect = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Audio.CommitOnDisconnect)
+
+	_, _, err = Parse("audio.commit_on_disconnect = maybe\n", Default())
+	require.Error(t, err)
+}
+
+func TestParseAudioLatencyJSONC(t *testing.T) {
+	require.Zero(t, Default().Audio.LatencyMS)
+
+	cfg, _, err := Parse(`{"audio":{"latency_ms":60}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 60, cfg.Audio.LatencyMS)
+}
+
+func TestParseAudioLatencyLegacy(t *testing.T) {
+	cfg, _, err := Parse("audio.latency_ms = 60\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 60, cfg.Audio.LatencyMS)
+
+	_, _, err = Parse("audio.latency_ms = fast\n", Default())
+	require.ErrorContains(t, err, "invalid int for audio.latency_ms")
+}
+
+func TestParseAudioResamplerJSONC(t *testing.T) {
+	require.Equal(t, "pulse", Default().Audio.Resampler)
+
+	cfg, _, err := Parse(`{"audio":{"resampler":"Internal"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "internal", cfg.Audio.Resampler)
+}
+
+func TestParseAudioResamplerLegacy(t *testing.T) {
+	cfg, _, err := Parse("audio.resampler = internal\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "internal", cfg.Audio.Resampler)
+}
+
+func TestParseASREncodingJSONC(t *testing.T) {
+	require.Equal(t, "linear_pcm", Default().ASR.Encoding)
+
+	cfg, _, err := Parse(`{"asr":{"encoding":" FLAC "}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "flac", cfg.ASR.Encoding)
+
+	_, _, err = Parse(`{"asr":{"encoding":"opus"}}`, Default())
+	require.ErrorContains(t, err, "asr.encoding")
+}
+
+func TestParseASREncodingLegacy(t *testing.T) {
+	cfg, _, err := Parse("asr.encoding = flac\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "flac", cfg.ASR.Encoding)
+}
+
+func TestParseASRDefaultBoostJSONC(t *testing.T) {
+	require.Zero(t, Default().ASR.DefaultBoost)
+
+	cfg, _, err := Parse(`{"asr":{"default_boost":-2.5}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, -2.5, cfg.ASR.DefaultBoost)
+
+	_, _, err = Parse(`{"asr":{"default_boost":101}}`, Default())
+	require.ErrorContains(t, err, "asr.default_boost")
+}
+
+func TestParseASRDefaultBoostLegacy(t *testing.T) {
+	cfg, _, err := Parse("asr.default_boost = 4\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 4.0, cfg.ASR.DefaultBoost)
+
+	_, _, err = Parse("asr.default_boost = high\n", Default())
+	require.ErrorContains(t, err, "invalid float for asr.default_boost")
+}
+
+func TestParseVocabSetFileJSONC(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "team.txt"), []byte("# team names\nKubernetes\n\n  Riva  \n"), 0o600))
+
+	cfg, _, err := ParseInDir(`{
+  "vocab": {
+    "global": ["team"],
+    "sets": {"team": {"phrases": ["sotto"], "file": "team.txt"}}
+  }
+}`, Default(), dir)
+	require.NoError(t, err)
+	require.Equal(t, "team.txt", cfg.Vocab.Sets["team"].File)
+	require.Equal(t, []string{"sotto", "Kubernetes", "Riva"}, cfg.Vocab.Sets["team"].Phrases)
+
+	_, _, err = ParseInDir(`{
+  "vocab": {
+    "global": ["team"],
+    "max_phrases": 2,
+    "sets": {"team": {"phrases": ["sotto"], "file": "team.txt"}}
+  }
+}`, Default(), dir)
+	require.ErrorContains(t, err, "exceeds vocab.max_phrases=2")
+
+	_, _, err = ParseInDir(`{"vocab":{"sets":{"team":{"file":"missing.txt"}}}}`, Default(), dir)
+	require.ErrorContains(t, err, `vocabset "team": read phrase file`)
+	require.ErrorContains(t, err, "missing.txt")
+}
+
+func TestParseVocabSetFileLegacy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "team.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Kubernetes\nRiva\n"), 0o600))
+
+	cfg, _, err := Parse(`vocab.global = team
+vocabset team {
+  phrases = ["sotto"]
+  file = "`+path+`"
+}
+`, Default())
+	require.NoError(t, err)
+	require.Equal(t, []string{"sotto", "Kubernetes", "Riva"}, cfg.Vocab.Sets["team"].Phrases)
+
+	_, _, err = ParseInDir("vocabset team {\n  file = missing.txt\n}\n", Default(), dir)
+	require.ErrorContains(t, err, filepath.Join(dir, "missing.txt"))
+}
+
+func TestParseVocabCaseInsensitiveDedupeJSONC(t *testing.T) {
+	require.True(t, Default().Vocab.CaseInsensitiveDedupe)
+
+	cfg, _, err := Parse(`{"vocab":{"case_insensitive_dedupe":false}}`, Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Vocab.CaseInsensitiveDedupe)
+}
+
+func TestParseVocabCaseInsensitiveDedupeLegacy(t *testing.T) {
+	cfg, _, err := Parse("vocab.case_insensitive_dedupe = false\n", Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Vocab.CaseInsensitiveDedupe)
+
+	_, _, err = Parse("vocab.case_insensitive_dedupe = maybe\n", Default())
+	require.ErrorContains(t, err, "invalid bool for vocab.case_insensitive_dedupe")
+}
+
+func TestParseASRInterimMaxAgeJSONC(t *testing.T) {
+	require.Equal(t, 2, Default().ASR.InterimMaxAge)
+
+	cfg, _, err := Parse(`{"asr":{"interim_max_age":4}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 4, cfg.ASR.InterimMaxAge)
+}
+
+func TestParseASRInterimMaxAgeLegacy(t *testing.T) {
+	cfg, _, err := Parse("asr.interim_max_age = 3\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 3, cfg.ASR.InterimMaxAge)
+
+	_, _, err = Parse("asr.interim_max_age = long\n", Default())
+	require.ErrorContains(t, err, "invalid int for asr.interim_max_age")
+}
+
+func TestParseASRFinalDedupeWindowJSONC(t *testing.T) {
+	require.Zero(t, Default().ASR.FinalDedupeWindow)
+
+	cfg, _, err := Parse(`{"asr":{"final_dedupe_window":4}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 4, cfg.ASR.FinalDedupeWindow)
+}
+
+func TestParseASRFinalDedupeWindowLegacy(t *testing.T) {
+	cfg, _, err := Parse("asr.final_dedupe_window = 8\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 8, cfg.ASR.FinalDedupeWindow)
+
+	_, _, err = Parse("asr.final_dedupe_window = wide\n", Default())
+	require.ErrorContains(t, err, "invalid int for asr.final_dedupe_window")
+}
+
+func TestParseASRInterimFuzzyThresholdJSONC(t *testing.T) {
+	require.Zero(t, Default().ASR.InterimFuzzyThreshold)
+
+	cfg, _, err := Parse(`{"asr":{"interim_fuzzy_threshold":0.34}}`, Default())
+	require.NoError(t, err)
+	require.InDelta(t, 0.34, cfg.ASR.InterimFuzzyThreshold, 1e-9)
+}
+
+func TestParseASRInterimFuzzyThresholdLegacy(t *testing.T) {
+	cfg, _, err := Parse("asr.interim_fuzzy_threshold = 0.25\n", Default())
+	require.NoError(t, err)
+	require.InDelta(t, 0.25, cfg.ASR.InterimFuzzyThreshold, 1e-9)
+
+	_, _, err = Parse("asr.interim_fuzzy_threshold = loose\n", Default())
+	require.ErrorContains(t, err, "invalid float for asr.interim_fuzzy_threshold")
+}
+
+func TestParseTranscriptTrailingNewlineJSONC(t *testing.T) {
+	require.False(t, Default().Transcript.TrailingNewline)
+
+	cfg, _, err := Parse(`{"transcript":{"trailing_space":false,"trailing_newline":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.TrailingNewline)
+	require.False(t, cfg.Transcript.TrailingSpace)
+
+	_, _, err = Parse(`{"transcript":{"trailing_newline":true}}`, Default())
+	require.ErrorContains(t, err, "transcript.trailing_newline and transcript.trailing_space are mutually exclusive")
+}
+
+func TestParseTranscriptTrailingNewlineLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.trailing_space = false\ntranscript.trailing_newline = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.TrailingNewline)
+
+	_, _, err = Parse("transcript.trailing_newline = yes please\n", Default())
+	require.ErrorContains(t, err, "invalid bool for transcript.trailing_newline")
+}
+
+func TestParseTranscriptMinCommitCharsJSONC(t *testing.T) {
+	require.Zero(t, Default().Transcript.MinCommitChars)
+
+	cfg, _, err := Parse(`{"transcript":{"min_commit_chars":4}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 4, cfg.Transcript.MinCommitChars)
+}
+
+func TestParseTranscriptMinCommitCharsLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.min_commit_chars = 3\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 3, cfg.Transcript.MinCommitChars)
+
+	_, _, err = Parse("transcript.min_commit_chars = few\n", Default())
+	require.ErrorContains(t, err, "invalid int for transcript.min_commit_chars")
+}
+
+func TestParseTranscriptPostprocessCmdJSONC(t *testing.T) {
+	require.Empty(t, Default().Transcript.PostprocessCmd.Argv)
+
+	cfg, _, err := Parse(`{"transcript":{"postprocess_cmd":"llm-cleanup --style 'plain text'"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, []string{"llm-cleanup", "--style", "plain text"}, cfg.Transcript.PostprocessCmd.Argv)
+
+	_, _, err = Parse(`{"transcript":{"postprocess_cmd":"llm-cleanup 'unterminated"}}`, Default())
+	require.ErrorContains(t, err, "invalid transcript.postprocess_cmd")
+}
+
+func TestParseTranscriptPostprocessCmdLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.postprocess_cmd = \"tr a-z A-Z\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "tr a-z A-Z", cfg.Transcript.PostprocessCmd.Raw)
+	require.Equal(t, []string{"tr", "a-z", "A-Z"}, cfg.Transcript.PostprocessCmd.Argv)
+}
+
+func TestParseTranscriptSymbolWordsJSONC(t *testing.T) {
+	require.False(t, Default().Transcript.CodeMode)
+	require.Equal(t, ".", Default().Transcript.SymbolWords["dot"])
+
+	cfg, _, err := Parse(`{"transcript":{"code_mode":true,"symbol_words":{" Arrow ":"->","dot":"."}}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.CodeMode)
+	require.Equal(t, map[string]string{"arrow": "->", "dot": "."}, cfg.Transcript.SymbolWords)
+}
+
+func TestParseTranscriptSymbolWordsLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.code_mode = true\ntranscript.symbol_words.Arrow = \"->\"\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.CodeMode)
+	require.Equal(t, "->", cfg.Transcript.SymbolWords["arrow"])
+	require.Equal(t, ".", cfg.Transcript.SymbolWords["dot"])
+
+	_, _, err = Parse("transcript.code_mode = sometimes\n", Default())
+	require.ErrorContains(t, err, "invalid bool for transcript.code_mode")
+}
+
+func TestParseTranscriptInitialismsJSONC(t *testing.T) {
+	require.False(t, Default().Transcript.CollapseInitialisms)
+	require.Equal(t, 3, Default().Transcript.InitialismMinLetters)
+
+	cfg, _, err := Parse(`{"transcript":{"collapse_initialisms":true,"initialisms":["API", " ","CI"],"initialism_min_letters":0}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.CollapseInitialisms)
+	require.Equal(t, []string{"API", "CI"}, cfg.Transcript.Initialisms)
+	require.Zero(t, cfg.Transcript.InitialismMinLetters)
+}
+
+func TestParseTranscriptInitialismsLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.collapse_initialisms = true\ntranscript.initialisms = \"API, CI\"\ntranscript.initialism_min_letters = 4\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Transcript.CollapseInitialisms)
+	require.Equal(t, []string{"API", "CI"}, cfg.Transcript.Initialisms)
+	require.Equal(t, 4, cfg.Transcript.InitialismMinLetters)
+
+	_, _, err = Parse("transcript.initialism_min_letters = few\n", Default())
+	require.ErrorContains(t, err, "invalid int for transcript.initialism_min_letters")
+}
+
+func TestParseTranscriptTrimPolicyJSONC(t *testing.T) {
+	require.Equal(t, "both", Default().Transcript.TrimPolicy)
+
+	cfg, _, err := Parse(`{"transcript":{"trim_policy":" Trailing "}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "trailing", cfg.Transcript.TrimPolicy)
+
+	_, _, err = Parse(`{"transcript":{"trim_policy":"sides"}}`, Default())
+	require.ErrorContains(t, err, "transcript.trim_policy")
+}
+
+func TestParseTranscriptTrimPolicyLegacy(t *testing.T) {
+	cfg, _, err := Parse("transcript.trim_policy = none\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "none", cfg.Transcript.TrimPolicy)
+}
+
+func TestParseOutputRecoverOnFailureJSONC(t *testing.T) {
+	require.True(t, Default().Output.RecoverOnFailure)
+
+	cfg, _, err := Parse(`{"output":{"recover_on_failure":false}}`, Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Output.RecoverOnFailure)
+}
+
+func TestParseOutputClipboardEnableJSONC(t *testing.T) {
+	require.True(t, Default().Output.ClipboardEnable)
+
+	cfg, _, err := Parse(`{"output":{"clipboard_enable":false},"clipboard_cmd":[]}`, Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Output.ClipboardEnable)
+	require.Empty(t, cfg.Clipboard.Argv)
+}
+
+func TestParseOutputClipboardEnableLegacy(t *testing.T) {
+	cfg, _, err := Parse("output.clipboard_enable = false\n", Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Output.ClipboardEnable)
+
+	_, _, err = Parse("output.clipboard_enable = sometimes\n", Default())
+	require.ErrorContains(t, err, "invalid bool for output.clipboard_enable")
+}
+
+func TestParseOutputRecoverOnFailureLegacy(t *testing.T) {
+	cfg, _, err := Parse("output.recover_on_failure = false\n", Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Output.RecoverOnFailure)
+
+	_, _, err = Parse("output.recover_on_failure = maybe\n", Default())
+	require.Error(t, err)
+}
+
+func TestParseIndicatorTrackMonitorJSONC(t *testing.T) {
+	require.True(t, Default().Indicator.TrackMonitor)
+
+	cfg, _, err := Parse(`{"indicator":{"track_monitor":false}}`, Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Indicator.TrackMonitor)
+}
+
+func TestParseIndicatorTrackMonitorLegacy(t *testing.T) {
+	cfg, _, err := Parse("indicator.track_monitor = false\n", Default())
+	require.NoError(t, err)
+	require.False(t, cfg.Indicator.TrackMonitor)
+
+	_, _, err = Parse("indicator.track_monitor = sometimes\n", Default())
+	require.ErrorContains(t, err, "invalid bool for indicator.track_monitor")
+}
+
+func TestParseOutputMaxClipboardBytesJSONC(t *testing.T) {
+	require.Zero(t, Default().Output.MaxClipboardBytes)
+
+	cfg, _, err := Parse(`{"output":{"max_clipboard_bytes":65536}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 65536, cfg.Output.MaxClipboardBytes)
+}
+
+func TestParseOutputMaxClipboardBytesLegacy(t *testing.T) {
+	cfg, _, err := Parse("output.max_clipboard_bytes = 65536\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 65536, cfg.Output.MaxClipboardBytes)
+
+	_, _, err = Parse("output.max_clipboard_bytes = lots\n", Default())
+	require.ErrorContains(t, err, "invalid int for output.max_clipboard_bytes")
+}
+
+func TestParseOutputRestoreClipboardJSONC(t *testing.T) {
+	require.Zero(t, Default().Output.RestoreClipboardMS)
+	require.Equal(t, []string{"wl-paste", "--no-newline", "--type", "text"}, Default().ClipboardReadCmd.Argv)
+	require.Equal(t, []string{"wl-copy", "--type", "text/plain"}, Default().ClipboardRestoreCmd.Argv)
+
+	cfg, _, err := Parse(`{"output":{"restore_clipboard_ms":300},"clipboard_read_cmd":"xclip -o -selection clipboard","clipboard_restore_cmd":"xclip -selection clipboard"}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 300, cfg.Output.RestoreClipboardMS)
+	require.Equal(t, []string{"xclip", "-o", "-selection", "clipboard"}, cfg.ClipboardReadCmd.Argv)
+	require.Equal(t, []string{"xclip", "-selection", "clipboard"}, cfg.ClipboardRestoreCmd.Argv)
+
+	_, _, err = Parse(`{"clipboard_read_cmd":"unterminated ' quote"}`, Default())
+	require.ErrorContains(t, err, "invalid clipboard_read_cmd")
+	_, _, err = Parse(`{"clipboard_restore_cmd":"unterminated ' quote"}`, Default())
+	require.ErrorContains(t, err, "invalid clipboard_restore_cmd")
+}
+
+func TestParseOutputRestoreClipboardLegacy(t *testing.T) {
+	cfg, _, err := Parse("output.restore_clipboard_ms = 300\nclipboard_read_cmd = \"xclip -o -selection clipboard\"\nclipboard_restore_cmd = \"xclip -selection clipboard\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 300, cfg.Output.RestoreClipboardMS)
+	require.Equal(t, []string{"xclip", "-o", "-selection", "clipboard"}, cfg.ClipboardReadCmd.Argv)
+	require.Equal(t, []string{"xclip", "-selection", "clipboard"}, cfg.ClipboardRestoreCmd.Argv)
+
+	_, _, err = Parse("output.restore_clipboard_ms = soon\n", Default())
+	require.ErrorContains(t, err, "invalid int for output.restore_clipboard_ms")
+}
+
+func TestParseOutputTimeoutsJSONC(t *testing.T) {
+	require.Equal(t, 2000, Default().Output.ClipboardTimeoutMS)
+	require.Zero(t, Default().Output.PasteTimeoutMS)
+
+	cfg, _, err := Parse(`{"output":{"clipboard_timeout_ms":5000,"paste_timeout_ms":3000}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 5000, cfg.Output.ClipboardTimeoutMS)
+	require.Equal(t, 3000, cfg.Output.PasteTimeoutMS)
+}
+
+func TestParseOutputTimeoutsLegacy(t *testing.T) {
+	cfg, _, err := Parse("output.clipboard_timeout_ms = 5000\noutput.paste_timeout_ms = 3000\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 5000, cfg.Output.ClipboardTimeoutMS)
+	require.Equal(t, 3000, cfg.Output.PasteTimeoutMS)
+
+	_, _, err = Parse("output.paste_timeout_ms = slow\n", Default())
+	require.ErrorContains(t, err, "invalid int for output.paste_timeout_ms")
+}
+
+func TestParseOutputFIFOPathJSONC(t *testing.T) {
+	require.Empty(t, Default().Output.FIFOPath)
+
+	cfg, _, err := Parse(`{"output":{"fifo_path":" /run/user/1000/sotto.fifo "}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "/run/user/1000/sotto.fifo", cfg.Output.FIFOPath)
+}
+
+func TestParseOutputFIFOPathLegacy(t *testing.T) {
+	cfg, _, err := Parse("output.fifo_path = \"/run/user/1000/sotto.fifo\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "/run/user/1000/sotto.fifo", cfg.Output.FIFOPath)
+}
+
+func TestParseOutputHistoryJSONC(t *testing.T) {
+	require.False(t, Default().Output.History)
+
+	cfg, _, err := Parse(`{"output":{"history":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Output.History)
+}
+
+func TestParseOutputHistoryLegacy(t *testing.T) {
+	cfg, _, err := Parse("output.history = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Output.History)
+
+	_, _, err = Parse("output.history = maybe\n", Default())
+	require.ErrorContains(t, err, "invalid bool for output.history")
+}
+
+func TestParseIndicatorShowInterimJSONC(t *testing.T) {
+	require.False(t, Default().Indicator.ShowInterim)
+
+	cfg, _, err := Parse(`{"indicator":{"show_interim":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Indicator.ShowInterim)
+}
+
+func TestParseIndicatorShowInterimLegacy(t *testing.T) {
+	cfg, _, err := Parse("indicator.show_interim = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Indicator.ShowInterim)
+
+	_, _, err = Parse("indicator.show_interim = live\n", Default())
+	require.ErrorContains(t, err, "invalid bool for indicator.show_interim")
+}
+
+func TestParseIndicatorTranscribingDelayJSONC(t *testing.T) {
+	require.Zero(t, Default().Indicator.TranscribingDelayMS)
+
+	cfg, _, err := Parse(`{"indicator":{"transcribing_delay_ms":250}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 250, cfg.Indicator.TranscribingDelayMS)
+}
+
+func TestParseIndicatorTranscribingDelayLegacy(t *testing.T) {
+	cfg, _, err := Parse("indicator.transcribing_delay_ms = 250\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 250, cfg.Indicator.TranscribingDelayMS)
+
+	_, _, err = Parse("indicator.transcribing_delay_ms = brief\n", Default())
+	require.ErrorContains(t, err, "invalid int for indicator.transcribing_delay_ms")
+}
+
+func TestParseDebugWAVMetadataJSONC(t *testing.T) {
+	require.False(t, Default().Debug.WAVMetadata)
+
+	cfg, _, err := Parse(`{"debug":{"audio_dump":true,"wav_metadata":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Debug.WAVMetadata)
+}
+
+func TestParseDebugWAVMetadataLegacy(t *testing.T) {
+	cfg, _, err := Parse("debug.wav_metadata = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Debug.WAVMetadata)
+
+	_, _, err = Parse("debug.wav_metadata = on\n", Default())
+	require.ErrorContains(t, err, "invalid bool for debug.wav_metadata")
+}
+
+func TestParseSessionPTTTimeoutActionJSONC(t *testing.T) {
+	require.Equal(t, "cancel", Default().Session.PTTTimeoutAction)
+
+	cfg, _, err := Parse(`{"session":{"ptt_timeout_action":"Commit"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "commit", cfg.Session.PTTTimeoutAction)
+}
+
+func TestParseSessionPTTTimeoutActionLegacy(t *testing.T) {
+	cfg, _, err := Parse("session.ptt_timeout_action = commit\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "commit", cfg.Session.PTTTimeoutAction)
+
+	_, _, err = Parse("session.ptt_timeout_action = paste\n", Default())
+	require.ErrorContains(t, err, "session.ptt_timeout_action must be one of")
+}
+
+func TestParseSessionNoAudioActionJSONC(t *testing.T) {
+	require.Equal(t, "cancel", Default().Session.NoAudioAction)
+
+	cfg, _, err := Parse(`{"session":{"no_audio_action":"Error"}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "error", cfg.Session.NoAudioAction)
+}
+
+func TestParseSessionNoAudioActionLegacy(t *testing.T) {
+	cfg, _, err := Parse("session.no_audio_action = error\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "error", cfg.Session.NoAudioAction)
+
+	_, _, err = Parse("session.no_audio_action = shrug\n", Default())
+	require.ErrorContains(t, err, "session.no_audio_action must be one of")
+}
+
+func TestParseOwnerIdleTimeoutJSONC(t *testing.T) {
+	require.Zero(t, Default().Owner.IdleTimeoutMS)
+
+	cfg, _, err := Parse(`{"owner":{"idle_timeout_ms":600000}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 600000, cfg.Owner.IdleTimeoutMS)
+}
+
+func TestParseUpdateJSONC(t *testing.T) {
+	require.Equal(t, "https://api.github.com/repos/rbright/sotto/releases/latest", Default().Update.URL)
+	require.False(t, Default().Update.Offline)
+
+	cfg, _, err := Parse(`{"update":{"url":"http://mirror.local/latest","offline":true}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "http://mirror.local/latest", cfg.Update.URL)
+	require.True(t, cfg.Update.Offline)
+}
+
+func TestParseUpdateLegacy(t *testing.T) {
+	cfg, _, err := Parse("update.url = http://mirror.local/latest\nupdate.offline = true\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "http://mirror.local/latest", cfg.Update.URL)
+	require.True(t, cfg.Update.Offline)
+
+	_, _, err = Parse("update.offline = sometimes\n", Default())
+	require.ErrorContains(t, err, "invalid bool for update.offline")
+}
+
+func TestParseOwnerIdleTimeoutLegacy(t *testing.T) {
+	cfg, _, err := Parse("owner.idle_timeout_ms = 600000\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 600000, cfg.Owner.IdleTimeoutMS)
+
+	_, _, err = Parse("owner.idle_timeout_ms = soon\n", Default())
+	require.ErrorContains(t, err, "invalid int for owner.idle_timeout_ms")
+}
+
+func TestParseSessionConfirmCancelJSONC(t *testing.T) {
+	require.False(t, Default().Session.ConfirmCancel)
+
+	cfg, _, err := Parse(`{"session":{"confirm_cancel":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Session.ConfirmCancel)
+}
+
+func TestParseSessionConfirmCancelLegacy(t *testing.T) {
+	cfg, _, err := Parse("session.confirm_cancel = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Session.ConfirmCancel)
+
+	_, _, err = Parse("session.confirm_cancel = twice\n", Default())
+	require.Error(t, err)
+}
+
+func TestParseSessionIdempotentStopJSONC(t *testing.T) {
+	require.False(t, Default().Session.IdempotentStop)
+
+	cfg, _, err := Parse(`{"session":{"idempotent_stop":true}}`, Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Session.IdempotentStop)
+}
+
+func TestParseSessionIdempotentStopLegacy(t *testing.T) {
+	cfg, _, err := Parse("session.idempotent_stop = true\n", Default())
+	require.NoError(t, err)
+	require.True(t, cfg.Session.IdempotentStop)
+
+	_, _, err = Parse("session.idempotent_stop = sometimes\n", Default())
+	require.Error(t, err)
+}
+
+func TestParseSessionPTTTimeoutJSONC(t *testing.T) {
+	require.Equal(t, 120000, Default().Session.PTTTimeoutMS)
+
+	cfg, _, err := Parse(`{"session":{"ptt_timeout_ms":30000}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 30000, cfg.Session.PTTTimeoutMS)
+}
+
+func TestParseSessionPTTTimeoutLegacy(t *testing.T) {
+	cfg, _, err := Parse("session.ptt_timeout_ms = 30000\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 30000, cfg.Session.PTTTimeoutMS)
+
+	_, _, err = Parse("session.ptt_timeout_ms = long\n", Default())
+	require.Error(t, err)
+}
+
+func TestParseDebugMetricsFileJSONC(t *testing.T) {
+	require.Empty(t, Default().Debug.MetricsFile)
+
+	cfg, _, err := Parse(`{"debug":{"metrics_file":" /var/lib/node_exporter/sotto.prom "}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "/var/lib/node_exporter/sotto.prom", cfg.Debug.MetricsFile)
+}
+
+func TestParseDebugMetricsFileLegacy(t *testing.T) {
+	cfg, _, err := Parse("debug.metrics_file = \"/var/lib/node_exporter/sotto.prom\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "/var/lib/node_exporter/sotto.prom", cfg.Debug.MetricsFile)
 }
 
 func TestParseTranscriptCapitalizeSentencesLegacy(t *testing.T) {
 	cfg, _, err := Parse("transcript.capitalize_sentences = false\n", Default())
 	require.NoError(t, err)
-	require.False(t, cfg.Transcript.CapitalizeSentences)
+	require.Equal(t, "none", cfg.Transcript.Capitalize)
+}
+
+func TestParseRivaMessageLimitsJSONC(t *testing.T) {
+	cfg, _, err := Parse(`{"riva":{"max_recv_mb":64,"max_send_mb":32}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 64, cfg.RivaMaxRecvMB)
+	require.Equal(t, 32, cfg.RivaMaxSendMB)
+}
+
+func TestParseRivaMessageLimitsLegacy(t *testing.T) {
+	cfg, _, err := Parse("riva_max_recv_mb = 64\nriva_max_send_mb = 32\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 64, cfg.RivaMaxRecvMB)
+	require.Equal(t, 32, cfg.RivaMaxSendMB)
+}
+
+func TestParseRivaKeepalive(t *testing.T) {
+	cfg, _, err := Parse(`{"riva":{"keepalive_ms":0}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 0, cfg.RivaKeepaliveMS)
+
+	cfg, _, err = Parse("riva_keepalive_ms = 45000\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 45000, cfg.RivaKeepaliveMS)
+}
+
+func TestParseRivaFirstResponseTimeoutJSONC(t *testing.T) {
+	cfg, _, err := Parse(`{"riva":{"first_response_timeout_ms":4000}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 4000, cfg.RivaFirstResponseTimeoutMS)
+}
+
+func TestParseRivaFirstResponseTimeoutLegacy(t *testing.T) {
+	cfg, _, err := Parse("riva_first_response_timeout_ms = 2500\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 2500, cfg.RivaFirstResponseTimeoutMS)
+
+	_, _, err = Parse("riva_first_response_timeout_ms = soon\n", Default())
+	require.ErrorContains(t, err, "riva_first_response_timeout_ms")
 }
 
 func TestParseIndicatorBackend(t *testing.T) {
@@ -217,6 +1098,37 @@ func TestParseIndicatorSoundEnable(t *testing.T) {
 	}
 }
 
+func TestParseIndicatorSoundSinkJSONC(t *testing.T) {
+	require.Empty(t, Default().Indicator.SoundSink)
+
+	cfg, _, err := Parse(`{"indicator":{"sound_sink":" alsa_output.usb-headset "}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, "alsa_output.usb-headset", cfg.Indicator.SoundSink)
+}
+
+func TestParseIndicatorSoundSinkLegacy(t *testing.T) {
+	cfg, _, err := Parse("indicator.sound_sink = \"alsa_output.usb-headset\"\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, "alsa_output.usb-headset", cfg.Indicator.SoundSink)
+}
+
+func TestParseIndicatorDispatchTimeoutJSONC(t *testing.T) {
+	require.Equal(t, 400, Default().Indicator.DispatchTimeoutMS)
+
+	cfg, _, err := Parse(`{"indicator":{"dispatch_timeout_ms":1500}}`, Default())
+	require.NoError(t, err)
+	require.Equal(t, 1500, cfg.Indicator.DispatchTimeoutMS)
+}
+
+func TestParseIndicatorDispatchTimeoutLegacy(t *testing.T) {
+	cfg, _, err := Parse("indicator.dispatch_timeout_ms = 1500\n", Default())
+	require.NoError(t, err)
+	require.Equal(t, 1500, cfg.Indicator.DispatchTimeoutMS)
+
+	_, _, err = Parse("indicator.dispatch_timeout_ms = soon\n", Default())
+	require.Error(t, err)
+}
+
 func TestParseIndicatorTextKeysRejected(t *testing.T) {
 	_, _, err := Parse(`{"indicator":{"text_recording":"Recording"}}`, Default())
 	require.Error(t, err)
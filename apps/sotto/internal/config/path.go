@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,6 +26,36 @@ func ResolvePath(explicit string) (string, error) {
 	return filepath.Join(home, ".config", "sotto", "config.jsonc"), nil
 }
 
+// StateDir returns sotto's state directory, $XDG_STATE_HOME/sotto or
+// ~/.local/state/sotto. Logs, history, recovery files, and debug dumps live
+// under it.
+func StateDir() (string, error) {
+	if xdg := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); xdg != "" {
+		return filepath.Join(xdg, "sotto"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory for state: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "sotto"), nil
+}
+
+// shadowedLegacyWarning reports a legacy config.conf sitting next to the
+// config.jsonc that Load used, since edits to it have no effect.
+func shadowedLegacyWarning(path string) (Warning, bool) {
+	legacyPath := legacyPathFor(path)
+	if legacyPath == "" {
+		return Warning{}, false
+	}
+	if _, err := os.Stat(legacyPath); err != nil {
+		return Warning{}, false
+	}
+	return Warning{
+		Code:    WarningConfigShadowed,
+		Message: fmt.Sprintf("found multiple config files (%q, %q); using %q and ignoring %q", path, legacyPath, path, legacyPath),
+	}, true
+}
+
 func legacyPathFor(path string) string {
 	if strings.HasSuffix(path, "config.jsonc") {
 		return strings.TrimSuffix(path, "config.jsonc") + "config.conf"
@@ -28,6 +28,26 @@ func TestResolvePathPrecedence(t *testing.T) {
 	require.Equal(t, filepath.Join(home, ".config", "sotto", "config.jsonc"), resolved)
 }
 
+func TestStateDirUsesXDGStateHome(t *testing.T) {
+	xdgStateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", xdgStateHome)
+	t.Setenv("HOME", t.TempDir())
+
+	dir, err := StateDir()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(xdgStateHome, "sotto"), dir)
+}
+
+func TestStateDirFallsBackToHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", home)
+
+	dir, err := StateDir()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(home, ".local", "state", "sotto"), dir)
+}
+
 func TestLoadMissingConfigUsesDefaultsWithWarning(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "missing.jsonc")
 
@@ -37,9 +57,22 @@ func TestLoadMissingConfigUsesDefaultsWithWarning(t *testing.T) {
 	require.False(t, loaded.Exists)
 	require.Equal(t, Default(), loaded.Config)
 	require.NotEmpty(t, loaded.Warnings)
+	require.Equal(t, WarningConfigMissing, loaded.Warnings[0].Code)
 	require.Contains(t, loaded.Warnings[0].Message, "not found")
 }
 
+func TestLoadResolvesVocabFileRelativeToConfig(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "vocab"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vocab", "team.txt"), []byte("Kubernetes\n"), 0o600))
+	path := filepath.Join(dir, "config.jsonc")
+	require.NoError(t, os.WriteFile(path, []byte(`{"vocab":{"sets":{"team":{"file":"vocab/team.txt"}}}}`), 0o600))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Kubernetes"}, loaded.Config.Vocab.Sets["team"].Phrases)
+}
+
 func TestLoadExistingJSONCParsesAndValidates(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "config.jsonc")
 	contents := `
@@ -82,9 +115,35 @@ func TestLoadImplicitPathFallsBackToLegacyConfigConf(t *testing.T) {
 	require.Equal(t, legacyPath, loaded.Path)
 	require.False(t, loaded.Config.Paste.Enable)
 	require.NotEmpty(t, loaded.Warnings)
+	require.Equal(t, WarningLegacyPath, loaded.Warnings[0].Code)
 	require.Contains(t, loaded.Warnings[0].Message, "legacy config path")
 }
 
+func TestLoadImplicitPathWarnsWhenBothConfigFilesExist(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	jsoncPath := filepath.Join(xdg, "sotto", "config.jsonc")
+	legacyPath := filepath.Join(xdg, "sotto", "config.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(jsoncPath), 0o700))
+	require.NoError(t, os.WriteFile(jsoncPath, []byte(`{"paste":{"enable":false}}`), 0o600))
+	require.NoError(t, os.WriteFile(legacyPath, []byte("paste.enable = true\n"), 0o600))
+
+	loaded, err := Load("")
+	require.NoError(t, err)
+	require.Equal(t, jsoncPath, loaded.Path)
+	require.False(t, loaded.Config.Paste.Enable)
+	require.Len(t, loaded.Warnings, 1)
+	require.Equal(t, WarningConfigShadowed, loaded.Warnings[0].Code)
+	require.Contains(t, loaded.Warnings[0].Message, "found multiple config files")
+	require.Contains(t, loaded.Warnings[0].Message, jsoncPath)
+	require.Contains(t, loaded.Warnings[0].Message, legacyPath)
+
+	explicit, err := Load(jsoncPath)
+	require.NoError(t, err)
+	require.Empty(t, explicit.Warnings)
+}
+
 func TestLoadParseErrorIncludesPath(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "broken.jsonc")
 	require.NoError(t, os.WriteFile(path, []byte("{ not-json }"), 0o600))
@@ -0,0 +1,44 @@
+package config
+
+import "strings"
+
+// redactedValue replaces secret material when config values are logged or printed.
+const redactedValue = "***"
+
+// Redacted returns a copy of cfg that is safe to log or print.
+//
+// Credentials embedded in endpoint userinfo (user:secret@host) are masked.
+// New secret-bearing fields must be masked here as well.
+func Redacted(cfg Config) Config {
+	cfg.RivaGRPC = RedactEndpoint(cfg.RivaGRPC)
+	cfg.RivaHTTP = RedactEndpoint(cfg.RivaHTTP)
+	return cfg
+}
+
+// RedactEndpoint masks the secret part of endpoint userinfo.
+//
+// "user:secret@host" keeps the user name; a bare "token@host" masks the token.
+func RedactEndpoint(endpoint string) string {
+	scheme := ""
+	rest := endpoint
+	if idx := strings.Index(rest, "://"); idx >= 0 {
+		scheme, rest = rest[:idx+3], rest[idx+3:]
+	}
+
+	hostEnd := strings.IndexAny(rest, "/?#")
+	if hostEnd < 0 {
+		hostEnd = len(rest)
+	}
+	at := strings.LastIndex(rest[:hostEnd], "@")
+	if at < 0 {
+		return endpoint
+	}
+
+	userinfo := rest[:at]
+	if user, _, ok := strings.Cut(userinfo, ":"); ok {
+		userinfo = user + ":" + redactedValue
+	} else {
+		userinfo = redactedValue
+	}
+	return scheme + userinfo + rest[at:]
+}
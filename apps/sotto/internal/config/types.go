@@ -3,30 +3,68 @@ package config
 
 // Config is the fully materialized runtime configuration used by sotto.
 type Config struct {
-	RivaGRPC       string
-	RivaHTTP       string
-	RivaHealthPath string
-	Audio          AudioConfig
-	Paste          PasteConfig
-	ASR            ASRConfig
-	Transcript     TranscriptConfig
-	Indicator      IndicatorConfig
-	Clipboard      CommandConfig
-	PasteCmd       CommandConfig
-	Vocab          VocabConfig
-	Debug          DebugConfig
+	RivaGRPC        string
+	RivaHTTP        string
+	RivaHealthPath  string
+	RivaMaxRecvMB   int
+	RivaMaxSendMB   int
+	RivaKeepaliveMS int
+	// RivaFirstResponseTimeoutMS fails a recording when Riva sends no response
+	// this long after audio starts flowing; 0 disables the watchdog.
+	RivaFirstResponseTimeoutMS int
+	Audio                      AudioConfig
+	Paste                      PasteConfig
+	ASR                        ASRConfig
+	Transcript                 TranscriptConfig
+	Indicator                  IndicatorConfig
+	Clipboard                  CommandConfig
+	ClipboardFallbacks         []CommandConfig
+	PasteCmd                   CommandConfig
+	ClipboardReadCmd           CommandConfig
+	ClipboardRestoreCmd        CommandConfig
+	Output                     OutputConfig
+	Session                    SessionConfig
+	Owner                      OwnerConfig
+	Update                     UpdateConfig
+	Vocab                      VocabConfig
+	Debug                      DebugConfig
 }
 
 // AudioConfig controls preferred and fallback input-source selection.
 type AudioConfig struct {
-	Input    string
-	Fallback string
+	Input        string
+	Fallback     string
+	PulseAppName string
+	PulseIcon    string
+	Allow        []string
+	Deny         []string
+	// AllowMonitor makes sink monitor (loopback) sources selectable, for
+	// transcribing system audio such as a meeting.
+	AllowMonitor       bool
+	CommitOnDisconnect bool
+	// LatencyMS asks Pulse for this record latency; zero keeps the fixed
+	// 20ms fragment.
+	LatencyMS int
+	// Resampler picks who converts non-16kHz sources: "pulse" asks the
+	// server for 16kHz, "internal" records at the source rate and resamples
+	// in-process.
+	Resampler string
 }
 
 // PasteConfig controls post-commit paste behavior.
 type PasteConfig struct {
 	Enable   bool
 	Shortcut string
+	// ShortcutFormat renders the sendshortcut payload for the default paste
+	// from {shortcut}, {address}, and {class}.
+	ShortcutFormat string
+	WindowRetries  int
+	WindowRetryMS  int
+	// AllowedClasses, when non-empty, limits paste to active windows whose
+	// class matches one entry; DeniedClasses always blocks paste. Matching is
+	// case-insensitive against the class and initial class.
+	AllowedClasses []string
+	DeniedClasses  []string
 }
 
 // ASRConfig controls request-level hints passed to Riva.
@@ -34,12 +72,49 @@ type ASRConfig struct {
 	AutomaticPunctuation bool
 	LanguageCode         string
 	Model                string
+	SpokenDigits         bool
+	// Verbatim asks Riva for verbatim transcripts, skipping server-side
+	// inverse text normalization; it excludes SpokenDigits.
+	Verbatim     bool
+	Encoding     string
+	DefaultBoost float64
+	// InterimMaxAge is how many updates an interim chain needs before a
+	// divergent hypothesis commits it as a segment.
+	InterimMaxAge int
+	// FinalDedupeWindow drops a final result of at least three words that
+	// repeats one of the last N finals, even with interims in between; zero
+	// (the default) disables it.
+	FinalDedupeWindow int
+	// InterimFuzzyThreshold also treats a diverging interim as a rewrite of
+	// the previous one when their word edit distance ratio is at most this
+	// value; zero disables it.
+	InterimFuzzyThreshold float64
 }
 
 // TranscriptConfig controls transcript assembly formatting.
 type TranscriptConfig struct {
-	TrailingSpace       bool
-	CapitalizeSentences bool
+	TrailingSpace bool
+	// TrailingNewline ends the committed text with "\n"; it cannot be combined
+	// with TrailingSpace.
+	TrailingNewline bool
+	Capitalize      string
+	SingleLine      bool
+	TrimPolicy      string
+	// MinCommitChars drops final transcripts shorter than this many
+	// characters after trimming, like an empty one; zero disables it.
+	MinCommitChars int
+	// CollapseInitialisms joins spelled-out letter runs ("A P I" -> "API")
+	// that are listed in Initialisms or at least InitialismMinLetters long.
+	CollapseInitialisms  bool
+	Initialisms          []string
+	InitialismMinLetters int
+	// PostprocessCmd, when set, rewrites each assembled transcript: it gets
+	// the text on stdin and its stdout replaces it.
+	PostprocessCmd CommandConfig
+	// CodeMode replaces spoken SymbolWords ("dot") with their symbols,
+	// joined to the neighboring words; --code enables it for one session.
+	CodeMode    bool
+	SymbolWords map[string]string
 }
 
 // IndicatorConfig controls visual indicator and audio cue behavior.
@@ -48,8 +123,20 @@ type IndicatorConfig struct {
 	Backend        string
 	DesktopAppName string
 	SoundEnable    bool
-	Height         int
-	ErrorTimeoutMS int
+	// SoundSink is the Pulse/PipeWire sink cues play on; empty uses the
+	// system default.
+	SoundSink         string
+	Height            int
+	ErrorTimeoutMS    int
+	DispatchTimeoutMS int
+	// TranscribingDelayMS holds back the transcribing indicator after stop.
+	TranscribingDelayMS int
+	// TrackMonitor queries hyprctl for the focused monitor at recording start;
+	// the result is only reported in session results and logs.
+	TrackMonitor bool
+	// ShowInterim updates the recording indicator with the live interim
+	// transcript.
+	ShowInterim bool
 }
 
 // CommandConfig stores a raw command string and its parsed argv form.
@@ -63,6 +150,11 @@ type VocabConfig struct {
 	GlobalSets []string
 	Sets       map[string]VocabSet
 	MaxPhrases int
+	// CaseInsensitiveDedupe collapses phrases that differ only in case.
+	CaseInsensitiveDedupe bool
+	// SessionPhrases come from --phrase for one session and are never read
+	// from config files; they count toward MaxPhrases like set phrases.
+	SessionPhrases []SpeechPhrase
 }
 
 // VocabSet is one named phrase group with a shared boost value.
@@ -70,17 +162,96 @@ type VocabSet struct {
 	Name    string
 	Boost   float64
 	Phrases []string
+	// File optionally names a newline-delimited phrase file merged after
+	// Phrases; relative paths resolve against the config file's directory.
+	File string
+}
+
+// OutputConfig controls transcript handling when commit side effects fail.
+type OutputConfig struct {
+	RecoverOnFailure bool
+	// ClipboardEnable runs clipboard_cmd on commit; false leaves the clipboard untouched.
+	ClipboardEnable bool
+	// MaxClipboardBytes warns, in the log and on the owner's stderr, when a
+	// transcript written to the clipboard exceeds it; zero means unlimited.
+	MaxClipboardBytes int
+	// RestoreClipboardMS puts the previous clipboard back this long after a
+	// successful paste, read via clipboard_read_cmd and written back via
+	// clipboard_restore_cmd; zero disables restore.
+	RestoreClipboardMS int
+	// FIFOPath receives each committed transcript as one line, created as a
+	// FIFO when absent; empty disables it.
+	FIFOPath string
+	// History appends each committed transcript to the plaintext history log
+	// that `sotto last` falls back to; off by default.
+	History bool
+	// ClipboardTimeoutMS bounds each clipboard_cmd, clipboard_read_cmd, and
+	// clipboard_restore_cmd run.
+	ClipboardTimeoutMS int
+	// PasteTimeoutMS bounds paste_cmd, or the default paste before its
+	// window-retry wait; zero keeps the built-in 2s and 1200ms budgets.
+	PasteTimeoutMS int
+}
+
+// SessionConfig controls owner-session command handling.
+type SessionConfig struct {
+	// IdempotentStop makes stop/toggle while transcribing succeed as a no-op.
+	IdempotentStop bool
+	// PTTTimeoutMS ends a push-to-talk recording whose ptt-stop never arrives.
+	PTTTimeoutMS int
+	// PTTTimeoutAction is "cancel" or "commit": what happens to a recording
+	// ended by PTTTimeoutMS.
+	PTTTimeoutAction string
+	// NoAudioAction is "cancel" or "error": how a stop before any audio was
+	// captured ends the session.
+	NoAudioAction string
+	// ConfirmCancel makes a single cancel prompt for a second one, which must
+	// arrive within a short window to discard the session.
+	ConfirmCancel bool
+}
+
+// OwnerConfig controls the long-lived owner started by `sotto daemon`.
+type OwnerConfig struct {
+	// IdleTimeoutMS exits the daemon after this long without a recording; zero
+	// keeps it running until stopped.
+	IdleTimeoutMS int
+}
+
+// UpdateConfig controls the release lookup behind `sotto version --check`.
+type UpdateConfig struct {
+	// URL is a GitHub-style releases/latest endpoint returning {"tag_name": ...}.
+	URL string
+	// Offline skips the network lookup entirely.
+	Offline bool
 }
 
 // DebugConfig controls optional debug artifact output.
 type DebugConfig struct {
 	EnableAudioDump bool
 	EnableGRPCDump  bool
+	// WAVMetadata adds software, timestamp, and device INFO entries to WAV dumps.
+	WAVMetadata bool
+	MetricsFile string
 }
 
-// Warning is a non-fatal parse/validation message.
+// Warning codes identify a warning kind independently of its message text.
+const (
+	WarningLegacyFormat        = "legacy_format"
+	WarningLegacyPath          = "legacy_path"
+	WarningConfigMissing       = "config_missing"
+	WarningConfigShadowed      = "config_shadowed"
+	WarningVocabSetRedefined   = "vocabset_redefined"
+	WarningCapitalizeRedefined = "capitalize_redefined"
+	WarningSampleRateMismatch  = "sample_rate_mismatch"
+	WarningPhraseCaseDuplicate = "phrase_case_duplicate"
+	WarningPhraseDuplicate     = "phrase_duplicate"
+)
+
+// Warning is a non-fatal parse/validation message. Code is one of the
+// Warning* constants, for callers that act on a specific warning.
 type Warning struct {
 	Line    int
+	Code    string
 	Message string
 }
 
@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
+	"unicode"
 )
 
 // Validate enforces config invariants and returns non-fatal warnings.
@@ -22,9 +24,77 @@ func Validate(cfg Config) ([]Warning, error) {
 	if !strings.HasPrefix(strings.TrimSpace(cfg.RivaHealthPath), "/") {
 		return nil, fmt.Errorf("riva_health_path must start with '/'")
 	}
+	if cfg.RivaMaxRecvMB <= 0 || cfg.RivaMaxRecvMB > maxRivaMessageMB {
+		return nil, fmt.Errorf("riva.max_recv_mb must be between 1 and %d", maxRivaMessageMB)
+	}
+	if cfg.RivaMaxSendMB <= 0 || cfg.RivaMaxSendMB > maxRivaMessageMB {
+		return nil, fmt.Errorf("riva.max_send_mb must be between 1 and %d", maxRivaMessageMB)
+	}
+	if cfg.RivaKeepaliveMS < 0 || (cfg.RivaKeepaliveMS > 0 && cfg.RivaKeepaliveMS < 10000) {
+		return nil, fmt.Errorf("riva.keepalive_ms must be 0 (disabled) or >= 10000")
+	}
+	if cfg.RivaFirstResponseTimeoutMS < 0 {
+		return nil, fmt.Errorf("riva.first_response_timeout_ms must be >= 0")
+	}
 	if strings.TrimSpace(cfg.ASR.LanguageCode) == "" {
 		return nil, fmt.Errorf("asr.language_code must not be empty")
 	}
+	if cfg.ASR.Encoding != "linear_pcm" && cfg.ASR.Encoding != "flac" {
+		return nil, fmt.Errorf("asr.encoding must be one of: linear_pcm, flac")
+	}
+	if cfg.ASR.Verbatim && cfg.ASR.SpokenDigits {
+		return nil, fmt.Errorf("asr.verbatim and asr.spoken_digits are mutually exclusive")
+	}
+	if cfg.ASR.DefaultBoost < -100 || cfg.ASR.DefaultBoost > 100 {
+		return nil, fmt.Errorf("asr.default_boost must be between -100 and 100")
+	}
+	if cfg.ASR.InterimMaxAge < 1 {
+		return nil, fmt.Errorf("asr.interim_max_age must be >= 1")
+	}
+	if cfg.ASR.FinalDedupeWindow < 0 {
+		return nil, fmt.Errorf("asr.final_dedupe_window must be >= 0")
+	}
+	if cfg.ASR.InterimFuzzyThreshold < 0 || cfg.ASR.InterimFuzzyThreshold >= 1 {
+		return nil, fmt.Errorf("asr.interim_fuzzy_threshold must be >= 0 and < 1")
+	}
+	if w, ok := sampleRateWarning(cfg.ASR.Model, captureSampleRate); ok {
+		warnings = append(warnings, w)
+	}
+	switch cfg.Transcript.Capitalize {
+	case "first", "sentences", "none":
+	default:
+		return nil, fmt.Errorf("transcript.capitalize must be one of: first, sentences, none")
+	}
+	switch cfg.Transcript.TrimPolicy {
+	case "both", "leading", "trailing", "none":
+	default:
+		return nil, fmt.Errorf("transcript.trim_policy must be one of: both, leading, trailing, none")
+	}
+	if cfg.Transcript.TrailingNewline && cfg.Transcript.TrailingSpace {
+		return nil, fmt.Errorf("transcript.trailing_newline and transcript.trailing_space are mutually exclusive")
+	}
+	if cfg.Transcript.MinCommitChars < 0 {
+		return nil, fmt.Errorf("transcript.min_commit_chars must be >= 0")
+	}
+	if strings.TrimSpace(cfg.Transcript.PostprocessCmd.Raw) != "" && len(cfg.Transcript.PostprocessCmd.Argv) == 0 {
+		return nil, fmt.Errorf("transcript.postprocess_cmd is configured but empty")
+	}
+	if cfg.Transcript.InitialismMinLetters < 0 || cfg.Transcript.InitialismMinLetters == 1 {
+		return nil, fmt.Errorf("transcript.initialism_min_letters must be 0 (listed only) or >= 2")
+	}
+	for _, entry := range cfg.Transcript.Initialisms {
+		if strings.IndexFunc(entry, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+			return nil, fmt.Errorf("transcript.initialisms entry %q must contain only letters", entry)
+		}
+	}
+	for word, symbol := range cfg.Transcript.SymbolWords {
+		if word == "" || strings.ContainsFunc(word, unicode.IsSpace) {
+			return nil, fmt.Errorf("transcript.symbol_words word %q must be a single word", word)
+		}
+		if symbol == "" {
+			return nil, fmt.Errorf("transcript.symbol_words entry %q must not be empty", word)
+		}
+	}
 	backend := strings.ToLower(strings.TrimSpace(cfg.Indicator.Backend))
 	if backend == "" {
 		return nil, fmt.Errorf("indicator.backend must not be empty")
@@ -38,22 +108,113 @@ func Validate(cfg Config) ([]Warning, error) {
 	if cfg.Indicator.Height <= 0 {
 		return nil, fmt.Errorf("indicator.height must be > 0")
 	}
-	if cfg.Indicator.ErrorTimeoutMS < 0 {
-		return nil, fmt.Errorf("indicator.error_timeout_ms must be >= 0")
+	if cfg.Indicator.ErrorTimeoutMS < -1 {
+		return nil, fmt.Errorf("indicator.error_timeout_ms must be >= -1 (-1 keeps errors until dismissed)")
+	}
+	if cfg.Indicator.DispatchTimeoutMS <= 0 {
+		return nil, fmt.Errorf("indicator.dispatch_timeout_ms must be > 0")
+	}
+	if cfg.Indicator.TranscribingDelayMS < 0 {
+		return nil, fmt.Errorf("indicator.transcribing_delay_ms must be >= 0")
+	}
+	if cfg.Session.PTTTimeoutMS <= 0 {
+		return nil, fmt.Errorf("session.ptt_timeout_ms must be > 0")
+	}
+	if cfg.Session.PTTTimeoutAction != "cancel" && cfg.Session.PTTTimeoutAction != "commit" {
+		return nil, fmt.Errorf("session.ptt_timeout_action must be one of: cancel, commit")
+	}
+	if cfg.Session.NoAudioAction != "cancel" && cfg.Session.NoAudioAction != "error" {
+		return nil, fmt.Errorf("session.no_audio_action must be one of: cancel, error")
+	}
+	if cfg.Owner.IdleTimeoutMS < 0 {
+		return nil, fmt.Errorf("owner.idle_timeout_ms must be >= 0")
+	}
+	if !strings.HasPrefix(cfg.Update.URL, "http://") && !strings.HasPrefix(cfg.Update.URL, "https://") {
+		return nil, fmt.Errorf("update.url must be an http:// or https:// URL")
 	}
 	if cfg.Vocab.MaxPhrases <= 0 {
 		return nil, fmt.Errorf("vocab.max_phrases must be > 0")
 	}
-	if len(cfg.Clipboard.Argv) == 0 {
-		return nil, fmt.Errorf("clipboard_cmd must not be empty")
+	if cfg.Output.MaxClipboardBytes < 0 {
+		return nil, fmt.Errorf("output.max_clipboard_bytes must be >= 0")
+	}
+	if cfg.Output.RestoreClipboardMS < 0 {
+		return nil, fmt.Errorf("output.restore_clipboard_ms must be >= 0")
+	}
+	if cfg.Output.RestoreClipboardMS > 0 && len(cfg.ClipboardReadCmd.Argv) == 0 {
+		return nil, fmt.Errorf("clipboard_read_cmd must not be empty when output.restore_clipboard_ms > 0")
+	}
+	if cfg.Output.RestoreClipboardMS > 0 && len(cfg.ClipboardRestoreCmd.Argv) == 0 {
+		return nil, fmt.Errorf("clipboard_restore_cmd must not be empty when output.restore_clipboard_ms > 0")
+	}
+	if cfg.Output.FIFOPath != "" && !filepath.IsAbs(cfg.Output.FIFOPath) {
+		return nil, fmt.Errorf("output.fifo_path must be an absolute path")
+	}
+	if cfg.Output.ClipboardTimeoutMS <= 0 {
+		return nil, fmt.Errorf("output.clipboard_timeout_ms must be > 0")
+	}
+	if cfg.Output.PasteTimeoutMS < 0 {
+		return nil, fmt.Errorf("output.paste_timeout_ms must be >= 0")
+	}
+	if cfg.Output.ClipboardEnable {
+		if len(cfg.Clipboard.Argv) == 0 {
+			return nil, fmt.Errorf("clipboard_cmd must not be empty")
+		}
+		for _, fallback := range cfg.ClipboardFallbacks {
+			if len(fallback.Argv) == 0 {
+				return nil, fmt.Errorf("clipboard_cmd entries must not be empty")
+			}
+		}
 	}
 
+	if cfg.Audio.PulseAppName == "" {
+		return nil, fmt.Errorf("audio.pulse_app_name must not be empty")
+	}
+	if cfg.Audio.LatencyMS < 0 || cfg.Audio.LatencyMS > 1000 {
+		return nil, fmt.Errorf("audio.latency_ms must be between 0 and 1000")
+	}
+	switch cfg.Audio.Resampler {
+	case "pulse", "internal":
+	default:
+		return nil, fmt.Errorf("audio.resampler must be one of: pulse, internal")
+	}
+	if cfg.Audio.PulseIcon == "" {
+		return nil, fmt.Errorf("audio.pulse_icon must not be empty")
+	}
+	for _, allowed := range cfg.Audio.Allow {
+		for _, denied := range cfg.Audio.Deny {
+			if strings.EqualFold(allowed, denied) {
+				return nil, fmt.Errorf("audio.allow and audio.deny both contain %q", allowed)
+			}
+		}
+	}
+	for _, allowed := range cfg.Paste.AllowedClasses {
+		for _, denied := range cfg.Paste.DeniedClasses {
+			if strings.EqualFold(allowed, denied) {
+				return nil, fmt.Errorf("paste.allowed_classes and paste.denied_classes both contain %q", allowed)
+			}
+		}
+	}
+	if cfg.Paste.WindowRetries < 1 {
+		return nil, fmt.Errorf("paste.window_retries must be >= 1")
+	}
+	if cfg.Paste.WindowRetryMS < 0 {
+		return nil, fmt.Errorf("paste.window_retry_ms must be >= 0")
+	}
 	if cfg.Paste.Enable && cfg.PasteCmd.Raw != "" && len(cfg.PasteCmd.Argv) == 0 {
 		return nil, fmt.Errorf("paste_cmd is configured but empty")
 	}
 	if cfg.Paste.Enable && len(cfg.PasteCmd.Argv) == 0 && strings.TrimSpace(cfg.Paste.Shortcut) == "" {
 		return nil, fmt.Errorf("paste.shortcut must not be empty when paste.enable=true and paste_cmd is unset")
 	}
+	if cfg.Paste.Enable && len(cfg.PasteCmd.Argv) == 0 {
+		if err := validatePasteShortcutFormat(cfg.Paste.ShortcutFormat); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Debug.MetricsFile != "" && !filepath.IsAbs(cfg.Debug.MetricsFile) {
+		return nil, fmt.Errorf("debug.metrics_file must be an absolute path")
+	}
 
 	_, vocabWarnings, err := BuildSpeechPhrases(cfg)
 	if err != nil {
@@ -64,49 +225,124 @@ func Validate(cfg Config) ([]Warning, error) {
 	return warnings, nil
 }
 
-// BuildSpeechPhrases merges enabled vocab sets into deterministic ASR phrase payloads.
+// pasteShortcutPlaceholders are the fields paste.shortcut_format may reference.
+var pasteShortcutPlaceholders = strings.NewReplacer("{shortcut}", "", "{address}", "", "{class}", "")
+
+// validatePasteShortcutFormat rejects an empty format or one referencing an
+// unknown placeholder.
+func validatePasteShortcutFormat(format string) error {
+	if strings.TrimSpace(format) == "" {
+		return fmt.Errorf("paste.shortcut_format must not be empty when paste.enable=true and paste_cmd is unset")
+	}
+	if rest := pasteShortcutPlaceholders.Replace(format); strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("paste.shortcut_format %q has an unknown placeholder; use {shortcut}, {address}, or {class}", format)
+	}
+	return nil
+}
+
+// captureSampleRate is the fixed PCM rate sotto records and streams to Riva.
+const captureSampleRate = 16000
+
+// maxRivaMessageMB keeps the gRPC message limits, converted to bytes, within
+// an int32 as the gRPC transport expects.
+const maxRivaMessageMB = 2047
+
+// sampleRateWarning flags asr.model names whose expected input rate differs
+// from rate. Unknown model names are not checked.
+func sampleRateWarning(model string, rate int) (Warning, bool) {
+	expected, ok := modelSampleRate(model)
+	if !ok || expected == rate {
+		return Warning{}, false
+	}
+	return Warning{Code: WarningSampleRateMismatch, Message: fmt.Sprintf("asr.model %q expects %d Hz audio but sotto streams %d Hz; transcripts may be garbled", model, expected, rate)}, true
+}
+
+// modelSampleRate infers the input rate a Riva ASR model expects from its name.
+func modelSampleRate(model string) (int, bool) {
+	name := strings.ToLower(strings.TrimSpace(model))
+	switch {
+	case name == "":
+		return 0, false
+	case strings.Contains(name, "8khz") || strings.Contains(name, "telephony"):
+		return 8000, true
+	case strings.Contains(name, "parakeet") || strings.Contains(name, "conformer") ||
+		strings.Contains(name, "citrinet") || strings.Contains(name, "canary"):
+		return 16000, true
+	default:
+		return 0, false
+	}
+}
+
+// BuildSpeechPhrases merges enabled vocab sets and session phrases into
+// deterministic ASR phrase payloads.
 func BuildSpeechPhrases(cfg Config) ([]SpeechPhrase, []Warning, error) {
 	enabledSets := cfg.Vocab.GlobalSets
-	if len(enabledSets) == 0 {
+	if len(enabledSets) == 0 && len(cfg.Vocab.SessionPhrases) == 0 {
 		return nil, nil, nil
 	}
 
 	type candidate struct {
-		boost float64
-		from  string
+		phrase string
+		boost  float64
+		from   string
 	}
 
 	warnings := make([]Warning, 0)
 	selected := make(map[string]candidate)
 
+	add := func(phrase string, boost float64, from string) {
+		phrase = strings.TrimSpace(phrase)
+		if phrase == "" {
+			return
+		}
+		// Phrases without an explicit boost inherit asr.default_boost.
+		if boost == 0 {
+			boost = cfg.ASR.DefaultBoost
+		}
+		key := phrase
+		if cfg.Vocab.CaseInsensitiveDedupe {
+			key = strings.ToLower(phrase)
+		}
+		if existing, exists := selected[key]; exists {
+			if existing.phrase != phrase {
+				// Casing collision: the higher-boost casing survives, the first on ties.
+				survivor := existing
+				if boost > existing.boost {
+					survivor = candidate{phrase: phrase, boost: boost, from: from}
+				}
+				warnings = append(warnings, Warning{Code: WarningPhraseCaseDuplicate, Message: fmt.Sprintf("phrases %q and %q differ only in case; keeping %q with boost %.2f", existing.phrase, phrase, survivor.phrase, survivor.boost)})
+				selected[key] = survivor
+				return
+			}
+			if boost > existing.boost {
+				warnings = append(warnings, Warning{Code: WarningPhraseDuplicate, Message: fmt.Sprintf("phrase %q present in %q and %q; using higher boost %.2f", phrase, existing.from, from, boost)})
+				selected[key] = candidate{phrase: phrase, boost: boost, from: from}
+			}
+			return
+		}
+		selected[key] = candidate{phrase: phrase, boost: boost, from: from}
+	}
+
 	for _, name := range enabledSets {
 		set, ok := cfg.Vocab.Sets[name]
 		if !ok {
 			return nil, nil, fmt.Errorf("vocab.global references unknown set %q", name)
 		}
 		for _, phrase := range set.Phrases {
-			phrase = strings.TrimSpace(phrase)
-			if phrase == "" {
-				continue
-			}
-			if existing, exists := selected[phrase]; exists {
-				if set.Boost > existing.boost {
-					warnings = append(warnings, Warning{Message: fmt.Sprintf("phrase %q present in %q and %q; using higher boost %.2f", phrase, existing.from, name, set.Boost)})
-					selected[phrase] = candidate{boost: set.Boost, from: name}
-				}
-				continue
-			}
-			selected[phrase] = candidate{boost: set.Boost, from: name}
+			add(phrase, set.Boost, name)
 		}
 	}
+	for _, phrase := range cfg.Vocab.SessionPhrases {
+		add(phrase.Phrase, float64(phrase.Boost), "--phrase")
+	}
 
 	if len(selected) > cfg.Vocab.MaxPhrases {
 		return nil, nil, fmt.Errorf("vocabulary phrase count %d exceeds vocab.max_phrases=%d", len(selected), cfg.Vocab.MaxPhrases)
 	}
 
 	phrases := make([]SpeechPhrase, 0, len(selected))
-	for phrase, c := range selected {
-		phrases = append(phrases, SpeechPhrase{Phrase: phrase, Boost: float32(c.boost)})
+	for _, c := range selected {
+		phrases = append(phrases, SpeechPhrase{Phrase: c.phrase, Boost: float32(c.boost)})
 	}
 
 	sort.Slice(phrases, func(i, j int) bool {
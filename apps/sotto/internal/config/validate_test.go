@@ -1,6 +1,10 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,6 +26,175 @@ func TestBuildSpeechPhrasesSortedAndHighestBoostWins(t *testing.T) {
 	}, phrases)
 }
 
+func TestBuildSpeechPhrasesZeroBoostSetInheritsDefault(t *testing.T) {
+	cfg := Default()
+	cfg.ASR.DefaultBoost = -5
+	cfg.Vocab.GlobalSets = []string{"plain", "tuned"}
+	cfg.Vocab.Sets["plain"] = VocabSet{Name: "plain", Phrases: []string{"alpha"}}
+	cfg.Vocab.Sets["tuned"] = VocabSet{Name: "tuned", Boost: 12, Phrases: []string{"beta"}}
+
+	phrases, warnings, err := BuildSpeechPhrases(cfg)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	require.Equal(t, []SpeechPhrase{
+		{Phrase: "alpha", Boost: -5},
+		{Phrase: "beta", Boost: 12},
+	}, phrases)
+}
+
+func TestBuildSpeechPhrasesCaseInsensitiveDedupeKeepsHighestBoostCasing(t *testing.T) {
+	cfg := Default()
+	cfg.Vocab.GlobalSets = []string{"core", "team"}
+	cfg.Vocab.Sets["core"] = VocabSet{Name: "core", Boost: 10, Phrases: []string{"sotto", "Riva", "RIVA"}}
+	cfg.Vocab.Sets["team"] = VocabSet{Name: "team", Boost: 20, Phrases: []string{"Sotto"}}
+
+	phrases, warnings, err := BuildSpeechPhrases(cfg)
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	require.Equal(t, WarningPhraseCaseDuplicate, warnings[0].Code)
+	require.Contains(t, warnings[0].Message, `keeping "Riva"`)
+	require.Contains(t, warnings[1].Message, `keeping "Sotto" with boost 20.00`)
+	require.Equal(t, []SpeechPhrase{
+		{Phrase: "Riva", Boost: 10},
+		{Phrase: "Sotto", Boost: 20},
+	}, phrases)
+}
+
+func TestBuildSpeechPhrasesCaseSensitiveDedupeKeepsBothCasings(t *testing.T) {
+	cfg := Default()
+	cfg.Vocab.CaseInsensitiveDedupe = false
+	cfg.Vocab.GlobalSets = []string{"core"}
+	cfg.Vocab.Sets["core"] = VocabSet{Name: "core", Boost: 10, Phrases: []string{"sotto", "Sotto"}}
+
+	phrases, warnings, err := BuildSpeechPhrases(cfg)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	require.Equal(t, []SpeechPhrase{
+		{Phrase: "Sotto", Boost: 10},
+		{Phrase: "sotto", Boost: 10},
+	}, phrases)
+}
+
+func TestCheckVocabFileReportsPhrasesOverCap(t *testing.T) {
+	var lines []string
+	for i := range 12 {
+		lines = append(lines, fmt.Sprintf("phrase %d", i))
+	}
+	path := filepath.Join(t.TempDir(), "big.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# team names\n"+strings.Join(lines, "\n")+"\n"), 0o600))
+
+	cfg := Default()
+	cfg.Vocab.MaxPhrases = 10
+	report, err := CheckVocabFile(cfg, path)
+	require.NoError(t, err)
+	require.Equal(t, path, report.Source)
+	require.Equal(t, []VocabSetSummary{{Name: "big.txt", Phrases: 12, Boost: cfg.ASR.DefaultBoost, Enabled: true}}, report.Sets)
+	require.Equal(t, 12, report.Phrases)
+	require.True(t, report.ExceedsMax())
+	require.Equal(t, cfg.ASR.DefaultBoost, report.MinBoost)
+	require.Equal(t, cfg.ASR.DefaultBoost, report.MaxBoost)
+
+	_, err = CheckVocabFile(cfg, filepath.Join(t.TempDir(), "missing.txt"))
+	require.ErrorContains(t, err, "read phrase file")
+}
+
+func TestCheckVocabReportsCasingCollisionsAndBoostRange(t *testing.T) {
+	cfg := Default()
+	cfg.Vocab.GlobalSets = []string{"core", "team"}
+	cfg.Vocab.Sets["core"] = VocabSet{Name: "core", Boost: 10, Phrases: []string{"sotto", "Riva"}}
+	cfg.Vocab.Sets["team"] = VocabSet{Name: "team", Boost: 20, Phrases: []string{"Sotto", "Kubernetes"}}
+	cfg.Vocab.Sets["unused"] = VocabSet{Name: "unused", Phrases: []string{"ignored"}}
+
+	report, err := CheckVocab(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "config", report.Source)
+	require.Equal(t, []VocabSetSummary{
+		{Name: "core", Phrases: 2, Boost: 10, Enabled: true},
+		{Name: "team", Phrases: 2, Boost: 20, Enabled: true},
+		{Name: "unused", Phrases: 1, Boost: cfg.ASR.DefaultBoost},
+	}, report.Sets)
+	require.Equal(t, 3, report.Phrases)
+	require.False(t, report.ExceedsMax())
+	require.Equal(t, 10.0, report.MinBoost)
+	require.Equal(t, 20.0, report.MaxBoost)
+	require.Len(t, report.Collisions, 1)
+	require.Equal(t, WarningPhraseCaseDuplicate, report.Collisions[0].Code)
+	require.Contains(t, report.Collisions[0].Message, `keeping "Sotto"`)
+}
+
+func TestBuildSpeechPhrasesMergesSessionPhrases(t *testing.T) {
+	cfg := Default()
+	cfg.ASR.DefaultBoost = 7
+	cfg.Vocab.GlobalSets = []string{"core"}
+	cfg.Vocab.Sets["core"] = VocabSet{Name: "core", Boost: 10, Phrases: []string{"alpha", "beta"}}
+	cfg.Vocab.SessionPhrases = []SpeechPhrase{{Phrase: "beta", Boost: 25}, {Phrase: "Ada"}}
+
+	phrases, warnings, err := BuildSpeechPhrases(cfg)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Equal(t, WarningPhraseDuplicate, warnings[0].Code)
+	require.Contains(t, warnings[0].Message, `present in "core" and "--phrase"`)
+	require.Equal(t, []SpeechPhrase{
+		{Phrase: "Ada", Boost: 7},
+		{Phrase: "alpha", Boost: 10},
+		{Phrase: "beta", Boost: 25},
+	}, phrases)
+
+	cfg.Vocab.MaxPhrases = 2
+	_, _, err = BuildSpeechPhrases(cfg)
+	require.ErrorContains(t, err, "exceeds vocab.max_phrases=2")
+}
+
+func TestValidateAllowsPersistentErrorTimeoutSentinel(t *testing.T) {
+	cfg := Default()
+	cfg.Indicator.ErrorTimeoutMS = -1
+
+	_, err := Validate(cfg)
+	require.NoError(t, err)
+}
+
+func TestValidateAllowsEmptyClipboardCmdWhenClipboardDisabled(t *testing.T) {
+	cfg := Default()
+	cfg.Clipboard = CommandConfig{}
+
+	_, err := Validate(cfg)
+	require.ErrorContains(t, err, "clipboard_cmd must not be empty")
+
+	cfg.Output.ClipboardEnable = false
+	_, err = Validate(cfg)
+	require.NoError(t, err)
+}
+
+func TestValidateWarnsOnModelSampleRateMismatch(t *testing.T) {
+	cfg := Default()
+	cfg.ASR.Model = "conformer-en-US-asr-streaming-telephony-8khz"
+
+	warnings, err := Validate(cfg)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Equal(t, WarningSampleRateMismatch, warnings[0].Code)
+	require.Contains(t, warnings[0].Message, "expects 8000 Hz")
+
+	cfg.ASR.Model = "parakeet-1.1b-en-US-asr-streaming-silero-vad-sortformer"
+	warnings, err = Validate(cfg)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+}
+
+func TestSampleRateWarningKnownModels(t *testing.T) {
+	_, ok := sampleRateWarning("parakeet-ctc-1.1b", 16000)
+	require.False(t, ok)
+
+	w, ok := sampleRateWarning("parakeet-ctc-1.1b", 8000)
+	require.True(t, ok)
+	require.Contains(t, w.Message, "expects 16000 Hz")
+
+	_, ok = sampleRateWarning("custom-model", 8000)
+	require.False(t, ok)
+	_, ok = sampleRateWarning("", 16000)
+	require.False(t, ok)
+}
+
 func TestValidateRejectsInvalidCoreFields(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -31,6 +204,12 @@ func TestValidateRejectsInvalidCoreFields(t *testing.T) {
 		{name: "empty riva grpc", mutate: func(c *Config) { c.RivaGRPC = "" }, wantErr: "riva_grpc"},
 		{name: "empty riva http", mutate: func(c *Config) { c.RivaHTTP = "" }, wantErr: "riva_http"},
 		{name: "bad health path", mutate: func(c *Config) { c.RivaHealthPath = "v1/health" }, wantErr: "must start"},
+		{name: "invalid max recv", mutate: func(c *Config) { c.RivaMaxRecvMB = 0 }, wantErr: "riva.max_recv_mb"},
+		{name: "invalid max send", mutate: func(c *Config) { c.RivaMaxSendMB = -1 }, wantErr: "riva.max_send_mb"},
+		{name: "max recv too large", mutate: func(c *Config) { c.RivaMaxRecvMB = 2048 }, wantErr: "riva.max_recv_mb"},
+		{name: "max send too large", mutate: func(c *Config) { c.RivaMaxSendMB = 4096 }, wantErr: "riva.max_send_mb"},
+		{name: "keepalive too frequent", mutate: func(c *Config) { c.RivaKeepaliveMS = 500 }, wantErr: "riva.keepalive_ms"},
+		{name: "negative first response timeout", mutate: func(c *Config) { c.RivaFirstResponseTimeoutMS = -1 }, wantErr: "riva.first_response_timeout_ms"},
 		{name: "empty language", mutate: func(c *Config) { c.ASR.LanguageCode = "" }, wantErr: "language_code"},
 		{name: "invalid indicator backend", mutate: func(c *Config) { c.Indicator.Backend = "unknown" }, wantErr: "indicator.backend"},
 		{name: "missing desktop app name", mutate: func(c *Config) {
@@ -38,9 +217,59 @@ func TestValidateRejectsInvalidCoreFields(t *testing.T) {
 			c.Indicator.DesktopAppName = ""
 		}, wantErr: "indicator.desktop_app_name"},
 		{name: "invalid indicator height", mutate: func(c *Config) { c.Indicator.Height = 0 }, wantErr: "indicator.height"},
-		{name: "negative error timeout", mutate: func(c *Config) { c.Indicator.ErrorTimeoutMS = -1 }, wantErr: "error_timeout"},
+		{name: "negative error timeout", mutate: func(c *Config) { c.Indicator.ErrorTimeoutMS = -2 }, wantErr: "error_timeout"},
+		{name: "non-positive dispatch timeout", mutate: func(c *Config) { c.Indicator.DispatchTimeoutMS = 0 }, wantErr: "indicator.dispatch_timeout_ms"},
+		{name: "non-positive ptt timeout", mutate: func(c *Config) { c.Session.PTTTimeoutMS = 0 }, wantErr: "session.ptt_timeout_ms"},
+		{name: "negative transcribing delay", mutate: func(c *Config) { c.Indicator.TranscribingDelayMS = -1 }, wantErr: "indicator.transcribing_delay_ms"},
+		{name: "negative max clipboard bytes", mutate: func(c *Config) { c.Output.MaxClipboardBytes = -1 }, wantErr: "output.max_clipboard_bytes"},
+		{name: "negative restore clipboard ms", mutate: func(c *Config) { c.Output.RestoreClipboardMS = -1 }, wantErr: "output.restore_clipboard_ms"},
+		{name: "relative fifo path", mutate: func(c *Config) { c.Output.FIFOPath = "sotto.fifo" }, wantErr: "output.fifo_path"},
+		{name: "zero clipboard timeout", mutate: func(c *Config) { c.Output.ClipboardTimeoutMS = 0 }, wantErr: "output.clipboard_timeout_ms"},
+		{name: "negative paste timeout", mutate: func(c *Config) { c.Output.PasteTimeoutMS = -1 }, wantErr: "output.paste_timeout_ms"},
+		{name: "restore without clipboard read cmd", mutate: func(c *Config) {
+			c.Output.RestoreClipboardMS = 300
+			c.ClipboardReadCmd = CommandConfig{}
+		}, wantErr: "clipboard_read_cmd"},
+		{name: "restore without clipboard restore cmd", mutate: func(c *Config) {
+			c.Output.RestoreClipboardMS = 300
+			c.ClipboardRestoreCmd = CommandConfig{}
+		}, wantErr: "clipboard_restore_cmd"},
+		{name: "unknown ptt timeout action", mutate: func(c *Config) { c.Session.PTTTimeoutAction = "stop" }, wantErr: "session.ptt_timeout_action"},
+		{name: "unknown no audio action", mutate: func(c *Config) { c.Session.NoAudioAction = "ignore" }, wantErr: "session.no_audio_action"},
+		{name: "negative owner idle timeout", mutate: func(c *Config) { c.Owner.IdleTimeoutMS = -1 }, wantErr: "owner.idle_timeout_ms"},
+		{name: "update url without scheme", mutate: func(c *Config) { c.Update.URL = "example.com/latest" }, wantErr: "update.url"},
+		{name: "relative metrics file", mutate: func(c *Config) { c.Debug.MetricsFile = "sotto.prom" }, wantErr: "debug.metrics_file"},
 		{name: "invalid max phrases", mutate: func(c *Config) { c.Vocab.MaxPhrases = 0 }, wantErr: "vocab.max_phrases"},
+		{name: "negative audio latency", mutate: func(c *Config) { c.Audio.LatencyMS = -1 }, wantErr: "audio.latency_ms"},
+		{name: "audio latency too high", mutate: func(c *Config) { c.Audio.LatencyMS = 1001 }, wantErr: "audio.latency_ms"},
+		{name: "unknown audio resampler", mutate: func(c *Config) { c.Audio.Resampler = "sox" }, wantErr: "audio.resampler"},
+		{name: "empty pulse app name", mutate: func(c *Config) { c.Audio.PulseAppName = "" }, wantErr: "audio.pulse_app_name"},
+		{name: "unknown asr encoding", mutate: func(c *Config) { c.ASR.Encoding = "opus" }, wantErr: "asr.encoding"},
+		{name: "default boost out of range", mutate: func(c *Config) { c.ASR.DefaultBoost = 150 }, wantErr: "asr.default_boost"},
+		{name: "trailing newline with trailing space", mutate: func(c *Config) { c.Transcript.TrailingNewline = true }, wantErr: "mutually exclusive"},
+		{name: "negative min commit chars", mutate: func(c *Config) { c.Transcript.MinCommitChars = -1 }, wantErr: "transcript.min_commit_chars"},
+		{name: "initialism min letters one", mutate: func(c *Config) { c.Transcript.InitialismMinLetters = 1 }, wantErr: "transcript.initialism_min_letters"},
+		{name: "non-letter initialism", mutate: func(c *Config) { c.Transcript.Initialisms = []string{"API", "A.P.I"} }, wantErr: "transcript.initialisms"},
+		{name: "negative final dedupe window", mutate: func(c *Config) { c.ASR.FinalDedupeWindow = -1 }, wantErr: "asr.final_dedupe_window"},
+		{name: "verbatim with spoken digits", mutate: func(c *Config) { c.ASR.Verbatim = true; c.ASR.SpokenDigits = true }, wantErr: "asr.verbatim and asr.spoken_digits"},
+		{name: "interim max age zero", mutate: func(c *Config) { c.ASR.InterimMaxAge = 0 }, wantErr: "asr.interim_max_age"},
+		{name: "negative interim fuzzy threshold", mutate: func(c *Config) { c.ASR.InterimFuzzyThreshold = -0.1 }, wantErr: "asr.interim_fuzzy_threshold"},
+		{name: "interim fuzzy threshold one", mutate: func(c *Config) { c.ASR.InterimFuzzyThreshold = 1 }, wantErr: "asr.interim_fuzzy_threshold"},
+		{name: "unknown capitalize mode", mutate: func(c *Config) { c.Transcript.Capitalize = "words" }, wantErr: "transcript.capitalize"},
+		{name: "unknown trim policy", mutate: func(c *Config) { c.Transcript.TrimPolicy = "middle" }, wantErr: "transcript.trim_policy"},
+		{name: "empty pulse icon", mutate: func(c *Config) { c.Audio.PulseIcon = "" }, wantErr: "audio.pulse_icon"},
+		{name: "device allowed and denied", mutate: func(c *Config) {
+			c.Audio.Allow = []string{"Elgato"}
+			c.Audio.Deny = []string{"monitor", "elgato"}
+		}, wantErr: "audio.allow and audio.deny"},
+		{name: "paste class allowed and denied", mutate: func(c *Config) {
+			c.Paste.AllowedClasses = []string{"ghostty", "KeePassXC"}
+			c.Paste.DeniedClasses = []string{"keepassxc"}
+		}, wantErr: "paste.allowed_classes and paste.denied_classes"},
+		{name: "zero paste window retries", mutate: func(c *Config) { c.Paste.WindowRetries = 0 }, wantErr: "paste.window_retries"},
+		{name: "negative paste window retry delay", mutate: func(c *Config) { c.Paste.WindowRetryMS = -1 }, wantErr: "paste.window_retry_ms"},
 		{name: "empty clipboard argv", mutate: func(c *Config) { c.Clipboard.Argv = nil }, wantErr: "clipboard_cmd"},
+		{name: "empty clipboard fallback argv", mutate: func(c *Config) { c.ClipboardFallbacks = []CommandConfig{{}} }, wantErr: "clipboard_cmd entries"},
 		{name: "paste command raw but empty argv", mutate: func(c *Config) {
 			c.Paste.Enable = true
 			c.PasteCmd.Raw = "mycmd"
@@ -51,6 +280,23 @@ func TestValidateRejectsInvalidCoreFields(t *testing.T) {
 			c.PasteCmd = CommandConfig{}
 			c.Paste.Shortcut = ""
 		}, wantErr: "paste.shortcut"},
+		{name: "empty paste shortcut format", mutate: func(c *Config) {
+			c.PasteCmd = CommandConfig{}
+			c.Paste.ShortcutFormat = ""
+		}, wantErr: "paste.shortcut_format"},
+		{name: "unknown paste shortcut placeholder", mutate: func(c *Config) {
+			c.PasteCmd = CommandConfig{}
+			c.Paste.ShortcutFormat = "{shortcut},pid:{pid}"
+		}, wantErr: "unknown placeholder"},
+		{name: "postprocess command raw but empty argv", mutate: func(c *Config) {
+			c.Transcript.PostprocessCmd = CommandConfig{Raw: "cleanup", Argv: nil}
+		}, wantErr: "transcript.postprocess_cmd"},
+		{name: "multi-word symbol word", mutate: func(c *Config) {
+			c.Transcript.SymbolWords = map[string]string{"open paren": "("}
+		}, wantErr: "transcript.symbol_words word"},
+		{name: "empty symbol", mutate: func(c *Config) {
+			c.Transcript.SymbolWords = map[string]string{"dot": ""}
+		}, wantErr: "must not be empty"},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+)
+
+// VocabSetSummary is one vocab set's inline and file phrases before dedupe.
+type VocabSetSummary struct {
+	Name    string
+	Phrases int
+	// Boost is the set's effective boost: its own, or asr.default_boost.
+	Boost   float64
+	Enabled bool
+}
+
+// VocabReport describes the speech phrases a vocabulary would send, computed
+// without enforcing vocab.max_phrases so oversized vocabularies can be sized.
+type VocabReport struct {
+	// Source is "config" or the checked phrase file path.
+	Source string
+	Sets   []VocabSetSummary
+	// Phrases is the deduped phrase count across enabled sets.
+	Phrases    int
+	MaxPhrases int
+	// MinBoost and MaxBoost span the deduped phrases; both are zero when
+	// there are none.
+	MinBoost float64
+	MaxBoost float64
+	// Collisions are the dedupe warnings from BuildSpeechPhrases.
+	Collisions []Warning
+}
+
+// ExceedsMax reports whether the deduped phrases exceed vocab.max_phrases.
+func (r VocabReport) ExceedsMax() bool {
+	return r.Phrases > r.MaxPhrases
+}
+
+// CheckVocab reports on every vocab set in cfg and on the deduped phrases of
+// the sets enabled by vocab.global.
+func CheckVocab(cfg Config) (VocabReport, error) {
+	report := VocabReport{Source: "config", MaxPhrases: cfg.Vocab.MaxPhrases}
+
+	enabled := make(map[string]bool, len(cfg.Vocab.GlobalSets))
+	for _, name := range cfg.Vocab.GlobalSets {
+		enabled[name] = true
+	}
+	names := make([]string, 0, len(cfg.Vocab.Sets))
+	for name := range cfg.Vocab.Sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		set := cfg.Vocab.Sets[name]
+		boost := set.Boost
+		if boost == 0 {
+			boost = cfg.ASR.DefaultBoost
+		}
+		report.Sets = append(report.Sets, VocabSetSummary{Name: name, Phrases: len(set.Phrases), Boost: boost, Enabled: enabled[name]})
+	}
+
+	unbounded := cfg
+	unbounded.Vocab.MaxPhrases = math.MaxInt
+	unbounded.Vocab.SessionPhrases = nil
+	phrases, warnings, err := BuildSpeechPhrases(unbounded)
+	if err != nil {
+		return VocabReport{}, err
+	}
+	report.Phrases = len(phrases)
+	report.Collisions = warnings
+	for i, phrase := range phrases {
+		boost := float64(phrase.Boost)
+		if i == 0 || boost < report.MinBoost {
+			report.MinBoost = boost
+		}
+		if i == 0 || boost > report.MaxBoost {
+			report.MaxBoost = boost
+		}
+	}
+	return report, nil
+}
+
+// CheckVocabFile reports on a newline-delimited phrase file as if it were the
+// only enabled vocab set, using cfg's asr.default_boost and vocab limits.
+func CheckVocabFile(cfg Config, path string) (VocabReport, error) {
+	phrases, err := readPhraseFile(path)
+	if err != nil {
+		return VocabReport{}, fmt.Errorf("read phrase file %q: %w", path, err)
+	}
+
+	name := filepath.Base(path)
+	cfg.Vocab.Sets = map[string]VocabSet{name: {Name: name, Phrases: phrases}}
+	cfg.Vocab.GlobalSets = []string{name}
+	report, err := CheckVocab(cfg)
+	if err != nil {
+		return VocabReport{}, err
+	}
+	report.Source = path
+	return report, nil
+}
@@ -3,6 +3,7 @@ package doctor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/rbright/sotto/internal/audio"
 	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/riva"
 )
 
 // Check is one doctor assertion result.
@@ -37,13 +39,27 @@ func (r Report) OK() bool {
 	return true
 }
 
-// String renders the report as user-facing text output.
+const (
+	ansiGreen = "\x1b[32m"
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+// String renders the report as plain user-facing text output.
 func (r Report) String() string {
+	return r.StringColored(false)
+}
+
+// StringColored renders the report, coloring status labels green/red when color is true.
+func (r Report) StringColored(color bool) string {
 	var b strings.Builder
 	for _, check := range r.Checks {
-		status := "OK"
+		status, tint := "OK", ansiGreen
 		if !check.Pass {
-			status = "FAIL"
+			status, tint = "FAIL", ansiRed
+		}
+		if color {
+			status = tint + status + ansiReset
 		}
 		b.WriteString(fmt.Sprintf("[%s] %s: %s\n", status, check.Name, check.Message))
 	}
@@ -54,10 +70,14 @@ func (r Report) String() string {
 func Run(cfg config.Loaded) Report {
 	checks := []Check{}
 
+	configMessage := fmt.Sprintf("loaded %q", cfg.Path)
+	if !cfg.Exists {
+		configMessage = fmt.Sprintf("no config at %q; using built-in defaults", cfg.Path)
+	}
 	checks = append(checks, Check{
 		Name:    "config",
 		Pass:    true,
-		Message: fmt.Sprintf("loaded %q", cfg.Path),
+		Message: configMessage,
 	})
 
 	checks = append(checks, checkEnv("XDG_SESSION_TYPE", func(v string) bool {
@@ -68,7 +88,7 @@ func Run(cfg config.Loaded) Report {
 		return strings.TrimSpace(v) != ""
 	}, "Hyprland session detected", "HYPRLAND_INSTANCE_SIGNATURE is empty"))
 
-	checks = append(checks, checkCommand(cfg.Config.Clipboard.Argv, "clipboard_cmd"))
+	checks = append(checks, checkClipboardCommands(cfg.Config))
 
 	if cfg.Config.Paste.Enable {
 		if len(cfg.Config.PasteCmd.Argv) > 0 {
@@ -79,7 +99,16 @@ func Run(cfg config.Loaded) Report {
 	}
 
 	checks = append(checks, checkAudioSelection(cfg.Config))
-	checks = append(checks, checkRivaReady(cfg.Config))
+	if cfg.Config.Indicator.SoundEnable && cfg.Config.Indicator.SoundSink != "" {
+		checks = append(checks, checkSoundSink(cfg.Config, audio.ListSinks))
+	}
+	ready := checkRivaReady(cfg.Config)
+	checks = append(checks, ready)
+	// Model listing needs a live server; a failed readiness probe already
+	// explains why it would fail.
+	if ready.Pass {
+		checks = append(checks, checkRivaModel(cfg.Config))
+	}
 
 	return Report{Checks: checks}
 }
@@ -101,6 +130,23 @@ func checkCommand(argv []string, name string) Check {
 	return checkBinary(argv[0], fmt.Sprintf("%s command is available", name))
 }
 
+// checkClipboardCommands passes when any configured clipboard command is available.
+func checkClipboardCommands(cfg config.Config) Check {
+	if !cfg.Output.ClipboardEnable {
+		return Check{Name: "clipboard_cmd", Pass: true, Message: "clipboard disabled (output.clipboard_enable=false)"}
+	}
+	check := checkCommand(cfg.Clipboard.Argv, "clipboard_cmd")
+	if check.Pass {
+		return check
+	}
+	for _, fallback := range cfg.ClipboardFallbacks {
+		if fallbackCheck := checkCommand(fallback.Argv, "clipboard_cmd fallback"); fallbackCheck.Pass {
+			return fallbackCheck
+		}
+	}
+	return check
+}
+
 // checkBinary validates that a binary exists in PATH.
 func checkBinary(bin string, okMsg string) Check {
 	path, err := exec.LookPath(bin)
@@ -112,7 +158,9 @@ func checkBinary(bin string, okMsg string) Check {
 
 // checkAudioSelection runs live device selection to surface selection/fallback issues.
 func checkAudioSelection(cfg config.Config) Check {
-	selection, err := audio.SelectDevice(context.Background(), cfg.Audio.Input, cfg.Audio.Fallback)
+	pulseID := audio.IdentityFromConfig(cfg.Audio)
+	filter := audio.DeviceFilter{Allow: cfg.Audio.Allow, Deny: cfg.Audio.Deny, AllowMonitor: cfg.Audio.AllowMonitor}
+	selection, err := audio.SelectDevice(context.Background(), cfg.Audio.Input, cfg.Audio.Fallback, filter, pulseID)
 	if err != nil {
 		return Check{Name: "audio.device", Pass: false, Message: err.Error()}
 	}
@@ -123,6 +171,27 @@ func checkAudioSelection(cfg config.Config) Check {
 	return Check{Name: "audio.device", Pass: true, Message: message}
 }
 
+// checkSoundSink verifies indicator.sound_sink names an existing playback sink
+// reported by listSinks.
+func checkSoundSink(cfg config.Config, listSinks func(context.Context, audio.ClientIdentity) ([]string, error)) Check {
+	pulseID := audio.IdentityFromConfig(cfg.Audio)
+	sinks, err := listSinks(context.Background(), pulseID)
+	if err != nil {
+		return Check{Name: "indicator.sound_sink", Pass: false, Message: err.Error()}
+	}
+	sink := cfg.Indicator.SoundSink
+	for _, name := range sinks {
+		if name == sink {
+			return Check{Name: "indicator.sound_sink", Pass: true, Message: fmt.Sprintf("%q exists", sink)}
+		}
+	}
+	available := "none"
+	if len(sinks) > 0 {
+		available = strings.Join(sinks, ", ")
+	}
+	return Check{Name: "indicator.sound_sink", Pass: false, Message: fmt.Sprintf("sink %q not found; available: %s", sink, available)}
+}
+
 // checkRivaReady probes the configured Riva HTTP ready endpoint.
 func checkRivaReady(cfg config.Config) Check {
 	base := strings.TrimSpace(cfg.RivaHTTP)
@@ -134,6 +203,7 @@ func checkRivaReady(cfg config.Config) Check {
 	}
 
 	url := strings.TrimRight(base, "/") + cfg.RivaHealthPath
+	displayURL := config.RedactEndpoint(url)
 	client := http.Client{Timeout: 2 * time.Second}
 	resp, err := client.Get(url)
 	if err != nil {
@@ -143,13 +213,43 @@ func checkRivaReady(cfg config.Config) Check {
 
 	body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return Check{Name: "riva.ready", Pass: false, Message: fmt.Sprintf("HTTP %d from %s", resp.StatusCode, url)}
+		return Check{Name: "riva.ready", Pass: false, Message: fmt.Sprintf("HTTP %d from %s", resp.StatusCode, displayURL)}
 	}
 
 	bodyText := strings.ToLower(strings.TrimSpace(string(body)))
 	if bodyText != "" && !strings.Contains(bodyText, "ready") {
-		return Check{Name: "riva.ready", Pass: true, Message: fmt.Sprintf("HTTP %d from %s", resp.StatusCode, url)}
+		return Check{Name: "riva.ready", Pass: true, Message: fmt.Sprintf("HTTP %d from %s", resp.StatusCode, displayURL)}
+	}
+
+	return Check{Name: "riva.ready", Pass: true, Message: fmt.Sprintf("ready at %s", displayURL)}
+}
+
+// checkRivaModel verifies asr.model is loaded on the Riva server. Servers
+// without model listing pass with a note rather than failing.
+func checkRivaModel(cfg config.Config) Check {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	models, err := riva.ListModels(ctx, riva.StreamConfig{Endpoint: cfg.RivaGRPC, DialTimeout: 2 * time.Second})
+	if errors.Is(err, riva.ErrModelListingUnsupported) {
+		return Check{Name: "riva.model", Pass: true, Message: "server does not expose model listing; skipped"}
+	}
+	if err != nil {
+		return Check{Name: "riva.model", Pass: false, Message: fmt.Sprintf("list models: %v", err)}
 	}
 
-	return Check{Name: "riva.ready", Pass: true, Message: fmt.Sprintf("ready at %s", url)}
+	available := "none"
+	if len(models) > 0 {
+		available = strings.Join(models, ", ")
+	}
+	model := strings.TrimSpace(cfg.ASR.Model)
+	if model == "" {
+		return Check{Name: "riva.model", Pass: true, Message: fmt.Sprintf("asr.model unset; server default used (loaded: %s)", available)}
+	}
+	for _, loaded := range models {
+		if loaded == model {
+			return Check{Name: "riva.model", Pass: true, Message: fmt.Sprintf("%q is loaded", model)}
+		}
+	}
+	return Check{Name: "riva.model", Pass: false, Message: fmt.Sprintf("asr.model %q is not loaded; available: %s", model, available)}
 }
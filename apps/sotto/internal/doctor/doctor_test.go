@@ -1,6 +1,9 @@
 package doctor
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -8,8 +11,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/rbright/sotto/internal/audio"
 	"github.com/rbright/sotto/internal/config"
+	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func TestReportOKAndString(t *testing.T) {
@@ -24,6 +30,21 @@ func TestReportOKAndString(t *testing.T) {
 	require.Contains(t, text, "[FAIL] two: bad")
 }
 
+func TestReportStringColoredOnlyWhenEnabled(t *testing.T) {
+	report := Report{Checks: []Check{
+		{Name: "one", Pass: true, Message: "good"},
+		{Name: "two", Pass: false, Message: "bad"},
+	}}
+
+	colored := report.StringColored(true)
+	require.Contains(t, colored, "[\x1b[32mOK\x1b[0m] one: good")
+	require.Contains(t, colored, "[\x1b[31mFAIL\x1b[0m] two: bad")
+
+	require.NotContains(t, report.StringColored(false), "\x1b[")
+	require.NotContains(t, report.String(), "\x1b[")
+	require.Equal(t, report.String(), report.StringColored(false))
+}
+
 func TestCheckEnv(t *testing.T) {
 	t.Setenv("TEST_DOCTOR_ENV", "wayland")
 
@@ -67,6 +88,31 @@ func TestCheckCommandUsesBinaryFromPath(t *testing.T) {
 	require.Contains(t, check.Message, "clipboard_cmd command is available")
 }
 
+func TestCheckClipboardCommandsPassesWhenAnyFallbackAvailable(t *testing.T) {
+	cfg := config.Default()
+	cfg.Clipboard = config.CommandConfig{Argv: []string{"definitely-not-a-real-binary"}}
+	cfg.ClipboardFallbacks = []config.CommandConfig{{Argv: []string{"sh"}}}
+
+	check := checkClipboardCommands(cfg)
+	require.True(t, check.Pass)
+	require.Contains(t, check.Message, "clipboard_cmd fallback command is available")
+
+	cfg.ClipboardFallbacks = []config.CommandConfig{{Argv: []string{"also-not-a-real-binary"}}}
+	check = checkClipboardCommands(cfg)
+	require.False(t, check.Pass)
+	require.Contains(t, check.Message, "definitely-not-a-real-binary")
+}
+
+func TestCheckClipboardCommandsPassesWhenClipboardDisabled(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.ClipboardEnable = false
+	cfg.Clipboard = config.CommandConfig{}
+
+	check := checkClipboardCommands(cfg)
+	require.True(t, check.Pass)
+	require.Contains(t, check.Message, "clipboard disabled")
+}
+
 func TestCheckRivaReadySuccess(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		require.Equal(t, "/v1/health/ready", r.URL.Path)
@@ -84,6 +130,21 @@ func TestCheckRivaReadySuccess(t *testing.T) {
 	require.Contains(t, check.Message, "ready at")
 }
 
+func TestCheckRivaReadyRedactsEndpointCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := config.Default()
+	cfg.RivaHTTP = strings.Replace(server.URL, "http://", "http://svc:s3cret@", 1)
+
+	check := checkRivaReady(cfg)
+	require.True(t, check.Pass)
+	require.Contains(t, check.Message, "svc:***@")
+	require.NotContains(t, check.Message, "s3cret")
+}
+
 func TestCheckRivaReadyFailureStatusCode(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -124,6 +185,70 @@ func TestCheckRivaReadyEmptyBaseURL(t *testing.T) {
 	require.Contains(t, check.Message, "riva_http is empty")
 }
 
+func TestCheckRivaModel(t *testing.T) {
+	endpoint := startModelListServer(t, &modelListServer{models: []string{"parakeet-1.1b-en-US-asr-streaming", "conformer-en-US-asr-streaming"}})
+
+	cfg := config.Default()
+	cfg.RivaGRPC = endpoint
+
+	cfg.ASR.Model = "parakeet-1.1b-en-US-asr-streaming"
+	check := checkRivaModel(cfg)
+	require.True(t, check.Pass, check.Message)
+	require.Contains(t, check.Message, "is loaded")
+
+	cfg.ASR.Model = "parakeet-1.1b-en-US-asr-stream"
+	check = checkRivaModel(cfg)
+	require.False(t, check.Pass)
+	require.Contains(t, check.Message, "not loaded")
+	require.Contains(t, check.Message, "available: conformer-en-US-asr-streaming, parakeet-1.1b-en-US-asr-streaming")
+
+	cfg.ASR.Model = ""
+	check = checkRivaModel(cfg)
+	require.True(t, check.Pass)
+	require.Contains(t, check.Message, "server default")
+}
+
+func TestCheckRivaModelSkipsWhenListingUnsupported(t *testing.T) {
+	endpoint := startModelListServer(t, &asrpb.UnimplementedRivaSpeechRecognitionServer{})
+
+	cfg := config.Default()
+	cfg.RivaGRPC = endpoint
+	cfg.ASR.Model = "anything"
+
+	check := checkRivaModel(cfg)
+	require.True(t, check.Pass)
+	require.Contains(t, check.Message, "skipped")
+}
+
+type modelListServer struct {
+	asrpb.UnimplementedRivaSpeechRecognitionServer
+	models []string
+}
+
+func (s *modelListServer) GetRivaSpeechRecognitionConfig(context.Context, *asrpb.RivaSpeechRecognitionConfigRequest) (*asrpb.RivaSpeechRecognitionConfigResponse, error) {
+	resp := &asrpb.RivaSpeechRecognitionConfigResponse{}
+	for _, name := range s.models {
+		resp.ModelConfig = append(resp.ModelConfig, &asrpb.RivaSpeechRecognitionConfigResponse_Config{ModelName: name})
+	}
+	return resp, nil
+}
+
+func startModelListServer(t *testing.T, srv asrpb.RivaSpeechRecognitionServer) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	asrpb.RegisterRivaSpeechRecognitionServer(grpcServer, srv)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
 func TestCheckAudioSelectionFailureWithInvalidPulseServer(t *testing.T) {
 	t.Setenv("PULSE_SERVER", "unix:/tmp/definitely-missing-pulse-server")
 
@@ -132,6 +257,33 @@ func TestCheckAudioSelectionFailureWithInvalidPulseServer(t *testing.T) {
 	require.Contains(t, check.Name, "audio.device")
 }
 
+func TestCheckSoundSink(t *testing.T) {
+	var gotID audio.ClientIdentity
+	listSinks := func(_ context.Context, id audio.ClientIdentity) ([]string, error) {
+		gotID = id
+		return []string{"alsa_output.pci-speakers", "alsa_output.usb-headset"}, nil
+	}
+
+	cfg := config.Default()
+	cfg.Indicator.SoundSink = "alsa_output.usb-headset"
+	check := checkSoundSink(cfg, listSinks)
+	require.True(t, check.Pass, check.Message)
+	require.Equal(t, "indicator.sound_sink", check.Name)
+	require.Equal(t, cfg.Audio.PulseAppName, gotID.AppName)
+
+	cfg.Indicator.SoundSink = "alsa_output.usb-missing"
+	check = checkSoundSink(cfg, listSinks)
+	require.False(t, check.Pass)
+	require.Contains(t, check.Message, "available: alsa_output.pci-speakers, alsa_output.usb-headset")
+
+	failing := func(context.Context, audio.ClientIdentity) ([]string, error) {
+		return nil, errors.New("connect pulse server: refused")
+	}
+	check = checkSoundSink(cfg, failing)
+	require.False(t, check.Pass)
+	require.Contains(t, check.Message, "refused")
+}
+
 func TestReportOKAllPassing(t *testing.T) {
 	report := Report{Checks: []Check{{Name: "one", Pass: true}, {Name: "two", Pass: true}}}
 	require.True(t, report.OK())
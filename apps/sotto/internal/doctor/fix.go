@@ -0,0 +1,115 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rbright/sotto/internal/ipc"
+)
+
+// starterConfig is written by --fix when no config exists. It sets nothing, so
+// every key keeps its built-in default until the user edits it.
+const starterConfig = `{
+  // sotto configuration (JSONC). Unset keys use built-in defaults; see
+  // docs/configuration.md for every key.
+}
+`
+
+// socketProbeTimeout bounds the liveness probe before a socket is removed.
+const socketProbeTimeout = 300 * time.Millisecond
+
+// FixPaths are the locations Fix may create or clean up. Empty fields are
+// skipped.
+type FixPaths struct {
+	// Config is written with a starter config when ConfigExists is false.
+	Config       string
+	ConfigExists bool
+	// Dirs are created when missing (state and debug dirs).
+	Dirs []string
+	// Socket is removed only when no owner answers on it.
+	Socket string
+}
+
+// Fix applies safe remediations for doctor --fix and reports each one that
+// acted or failed. It never overwrites an existing file and never removes a
+// socket a live owner still answers on.
+func Fix(ctx context.Context, paths FixPaths) []Check {
+	checks := []Check{}
+
+	if paths.Config != "" && !paths.ConfigExists {
+		checks = append(checks, fixConfig(paths.Config))
+	}
+	for _, dir := range paths.Dirs {
+		if check, ok := fixDir(dir); ok {
+			checks = append(checks, check)
+		}
+	}
+	if paths.Socket != "" {
+		if check, ok := fixSocket(ctx, paths.Socket); ok {
+			checks = append(checks, check)
+		}
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, Check{Name: "fix", Pass: true, Message: "nothing to fix"})
+	}
+	return checks
+}
+
+// fixConfig writes starterConfig to path, refusing to replace an existing file.
+func fixConfig(path string) Check {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return Check{Name: "fix config", Pass: false, Message: fmt.Sprintf("create config dir: %v", err)}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return Check{Name: "fix config", Pass: false, Message: fmt.Sprintf("create %q: %v", path, err)}
+	}
+	_, err = file.WriteString(starterConfig)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Check{Name: "fix config", Pass: false, Message: fmt.Sprintf("write %q: %v", path, err)}
+	}
+	return Check{Name: "fix config", Pass: true, Message: fmt.Sprintf("created starter config %q", path)}
+}
+
+// fixDir creates dir when it is missing; ok is false when it already exists.
+func fixDir(dir string) (Check, bool) {
+	if dir == "" {
+		return Check{}, false
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return Check{}, false
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return Check{Name: "fix dir", Pass: false, Message: fmt.Sprintf("stat %q: %v", dir, err)}, true
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Check{Name: "fix dir", Pass: false, Message: fmt.Sprintf("create %q: %v", dir, err)}, true
+	}
+	return Check{Name: "fix dir", Pass: true, Message: fmt.Sprintf("created %q", dir)}, true
+}
+
+// fixSocket removes a stale runtime socket; ok is false when there is no
+// socket to consider.
+func fixSocket(ctx context.Context, path string) (Check, bool) {
+	if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+		return Check{}, false
+	}
+	removed, ownerPID, err := ipc.RemoveStaleSocket(ctx, path, socketProbeTimeout)
+	switch {
+	case err != nil:
+		return Check{Name: "fix socket", Pass: false, Message: fmt.Sprintf("left %q in place: %v", path, err)}, true
+	case removed:
+		return Check{Name: "fix socket", Pass: true, Message: fmt.Sprintf("removed stale socket %q", path)}, true
+	case ownerPID > 0:
+		return Check{Name: "fix socket", Pass: true, Message: fmt.Sprintf("socket %q is in use by a running owner (pid %d); left in place", path, ownerPID)}, true
+	default:
+		return Check{Name: "fix socket", Pass: true, Message: fmt.Sprintf("socket %q is in use by a running owner; left in place", path)}, true
+	}
+}
@@ -0,0 +1,94 @@
+package doctor
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/ipc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixCreatesMissingConfigAndDirs(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "sotto", "config.jsonc")
+	stateDir := filepath.Join(dir, "state", "sotto")
+	debugDir := filepath.Join(stateDir, "debug")
+
+	checks := Fix(context.Background(), FixPaths{Config: configPath, Dirs: []string{stateDir, debugDir}})
+	require.Len(t, checks, 3)
+	for _, check := range checks {
+		require.True(t, check.Pass, check.Message)
+	}
+	require.Contains(t, checks[0].Message, "created starter config")
+	require.DirExists(t, debugDir)
+
+	loaded, err := config.Load(configPath)
+	require.NoError(t, err)
+	require.True(t, loaded.Exists)
+	require.Equal(t, config.Default(), loaded.Config)
+
+	// A second run finds nothing to do and leaves the config alone.
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"paste":{"enable":false}}`), 0o600))
+	checks = Fix(context.Background(), FixPaths{Config: configPath, ConfigExists: true, Dirs: []string{stateDir, debugDir}})
+	require.Equal(t, []Check{{Name: "fix", Pass: true, Message: "nothing to fix"}}, checks)
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, `{"paste":{"enable":false}}`, string(data))
+}
+
+func TestFixNeverOverwritesConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.jsonc")
+	require.NoError(t, os.WriteFile(configPath, []byte("keep"), 0o600))
+
+	checks := Fix(context.Background(), FixPaths{Config: configPath})
+	require.Len(t, checks, 1)
+	require.False(t, checks[0].Pass)
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "keep", string(data))
+}
+
+func TestFixRemovesStaleSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "sotto.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	// Closing without unlinking leaves the socket file of a crashed owner.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+	require.FileExists(t, socketPath)
+
+	checks := Fix(context.Background(), FixPaths{Socket: socketPath})
+	require.Len(t, checks, 1)
+	require.True(t, checks[0].Pass)
+	require.Contains(t, checks[0].Message, "removed stale socket")
+	require.NoFileExists(t, socketPath)
+}
+
+func TestFixLeavesLiveSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "sotto.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- ipc.Serve(ctx, listener, ipc.HandlerFunc(func(context.Context, ipc.Request) ipc.Response {
+			return ipc.Response{OK: true, State: "recording"}
+		}))
+	}()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	checks := Fix(context.Background(), FixPaths{Socket: socketPath})
+	require.Len(t, checks, 1)
+	require.True(t, checks[0].Pass)
+	require.Contains(t, checks[0].Message, "left in place")
+	require.FileExists(t, socketPath)
+}
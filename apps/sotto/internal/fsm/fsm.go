@@ -23,6 +23,7 @@ const (
 	EventTranscribed Event = "transcribed"
 	EventFail        Event = "fail"
 	EventReset       Event = "reset"
+	EventRetry       Event = "retry"
 )
 
 // Transition validates and applies one state transition.
@@ -52,6 +53,8 @@ func Transition(current State, event Event) (State, error) {
 		switch event {
 		case EventTranscribed:
 			return StateIdle, nil
+		case EventCancel:
+			return StateIdle, nil
 		default:
 			return current, invalidTransition(current, event)
 		}
@@ -59,6 +62,8 @@ func Transition(current State, event Event) (State, error) {
 		switch event {
 		case EventReset:
 			return StateIdle, nil
+		case EventRetry:
+			return StateRecording, nil
 		default:
 			return current, invalidTransition(current, event)
 		}
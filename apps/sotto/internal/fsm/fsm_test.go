@@ -44,10 +44,14 @@ func TestTransitionMatrixInvalidTransitions(t *testing.T) {
 		{name: "recording start invalid", state: StateRecording, event: EventStart, want: StateRecording, wantErr: true},
 		{name: "recording transcribed invalid", state: StateRecording, event: EventTranscribed, want: StateRecording, wantErr: true},
 		{name: "transcribing stop invalid", state: StateTranscribing, event: EventStop, want: StateTranscribing, wantErr: true},
-		{name: "transcribing cancel invalid", state: StateTranscribing, event: EventCancel, want: StateTranscribing, wantErr: true},
+		{name: "transcribing cancel valid", state: StateTranscribing, event: EventCancel, want: StateIdle, wantErr: false},
 		{name: "error start invalid", state: StateError, event: EventStart, want: StateError, wantErr: true},
 		{name: "error stop invalid", state: StateError, event: EventStop, want: StateError, wantErr: true},
 		{name: "error reset valid", state: StateError, event: EventReset, want: StateIdle, wantErr: false},
+		{name: "error retry valid", state: StateError, event: EventRetry, want: StateRecording, wantErr: false},
+		{name: "idle retry invalid", state: StateIdle, event: EventRetry, want: StateIdle, wantErr: true},
+		{name: "recording retry invalid", state: StateRecording, event: EventRetry, want: StateRecording, wantErr: true},
+		{name: "transcribing retry invalid", state: StateTranscribing, event: EventRetry, want: StateTranscribing, wantErr: true},
 	}
 
 	for _, tc := range tests {
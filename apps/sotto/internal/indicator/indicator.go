@@ -3,23 +3,36 @@ package indicator
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/rbright/sotto/internal/audio"
 	"github.com/rbright/sotto/internal/config"
 	"github.com/rbright/sotto/internal/hypr"
 )
 
+const (
+	// persistentTimeoutMS keeps a notification visible until Hide dismisses it.
+	persistentTimeoutMS = -1
+	// hyprPersistentTimeoutMS approximates "never expire"; hyprctl notify has no sentinel for it.
+	hyprPersistentTimeoutMS = 24 * 60 * 60 * 1000
+	// interimDisplayRunes caps live interim text to its most recent tail.
+	interimDisplayRunes = 80
+)
+
 // Controller is the session-facing indicator contract.
 type Controller interface {
 	ShowRecording(context.Context)
 	ShowTranscribing(context.Context)
+	ShowInterim(context.Context, string)
 	ShowError(context.Context, string)
 	CueStop(context.Context)
 	CueComplete(context.Context)
 	CueCancel(context.Context)
+	CueFallback(context.Context)
 	Hide(context.Context)
 	FocusedMonitor() string
 }
@@ -28,19 +41,29 @@ type Controller interface {
 // It can route notifications via Hyprland or desktop DBus based on config backend.
 type HyprNotify struct {
 	cfg      config.IndicatorConfig
+	pulseID  audio.ClientIdentity
 	logger   *slog.Logger
 	messages messages
 
 	mu                    sync.Mutex
 	focusedMonitor        string
+	interimLive           bool // ShowInterim may update the recording indicator
 	desktopNotificationID uint32
-	soundMu               sync.Mutex
+	// desktopPromptID is the confirm-cancel prompt, kept apart so it does not
+	// replace the persistent recording notification.
+	desktopPromptID uint32
+	// dispatchMu serializes notification dispatches so they land in call
+	// order; ShowInterim checks interimLive while holding it.
+	dispatchMu sync.Mutex
+	soundMu    sync.Mutex
 }
 
-// NewHyprNotify creates an indicator controller from config.
-func NewHyprNotify(cfg config.IndicatorConfig, logger *slog.Logger) *HyprNotify {
+// NewHyprNotify creates an indicator controller from config. pulseID names
+// the Pulse client used for synthesized cues.
+func NewHyprNotify(cfg config.IndicatorConfig, pulseID audio.ClientIdentity, logger *slog.Logger) *HyprNotify {
 	return &HyprNotify{
 		cfg:      cfg,
+		pulseID:  pulseID,
 		logger:   logger,
 		messages: indicatorMessagesFromEnv(),
 	}
@@ -52,17 +75,51 @@ func (h *HyprNotify) ShowRecording(ctx context.Context) {
 	if !h.cfg.Enable {
 		return
 	}
-	h.ensureFocusedMonitor(ctx)
+	if h.cfg.TrackMonitor {
+		h.ensureFocusedMonitor(ctx)
+	}
+	h.setInterimLive(true)
 	h.run(ctx, func(ctx context.Context) error {
 		return h.notify(ctx, 1, 300000, "rgb(89b4fa)", h.messages.recording)
 	})
 }
 
+// ShowInterim replaces the recording indicator text with the live transcript.
+// Updates arriving after any other state change (transcribing, error, Hide)
+// are dropped, so a late poll cannot resurrect the recording indicator. The
+// check runs inside the serialized dispatch: a state change that lands while
+// an update is in flight is dispatched after it.
+func (h *HyprNotify) ShowInterim(ctx context.Context, text string) {
+	if !h.cfg.Enable {
+		return
+	}
+	text = interimTail(strings.TrimSpace(text))
+	if text == "" {
+		return
+	}
+	h.run(ctx, func(ctx context.Context) error {
+		h.mu.Lock()
+		live := h.interimLive
+		h.mu.Unlock()
+		if !live {
+			return nil
+		}
+		if !h.desktopBackend() {
+			// Hyprland stacks notifications; drop the previous text first.
+			if err := hypr.DismissNotify(ctx); err != nil {
+				return err
+			}
+		}
+		return h.notify(ctx, 1, 300000, "rgb(89b4fa)", h.messages.recording+" "+text)
+	})
+}
+
 // ShowTranscribing signals the post-capture transcription state.
 func (h *HyprNotify) ShowTranscribing(ctx context.Context) {
 	if !h.cfg.Enable {
 		return
 	}
+	h.setInterimLive(false)
 	h.run(ctx, func(ctx context.Context) error {
 		return h.notify(ctx, 1, 300000, "rgb(cba6f7)", h.messages.processing)
 	})
@@ -76,8 +133,12 @@ func (h *HyprNotify) ShowError(ctx context.Context, text string) {
 	if text == "" {
 		text = h.messages.errorText
 	}
+	h.setInterimLive(false)
 	timeout := h.cfg.ErrorTimeoutMS
-	if timeout <= 0 {
+	switch {
+	case timeout < 0:
+		timeout = persistentTimeoutMS
+	case timeout == 0:
 		timeout = 1200
 	}
 	h.run(ctx, func(ctx context.Context) error {
@@ -85,6 +146,22 @@ func (h *HyprNotify) ShowError(ctx context.Context, text string) {
 	})
 }
 
+// ShowConfirmCancel asks for a second cancel within window before the session
+// is discarded. The prompt expires with the window and is shown next to the
+// recording indicator rather than in place of it.
+func (h *HyprNotify) ShowConfirmCancel(ctx context.Context, window time.Duration) {
+	if !h.cfg.Enable {
+		return
+	}
+	timeoutMS := int(window.Milliseconds())
+	h.run(ctx, func(ctx context.Context) error {
+		if h.desktopBackend() {
+			return h.notifyDesktop(ctx, &h.desktopPromptID, timeoutMS, h.messages.confirmCancel)
+		}
+		return h.notify(ctx, 0, timeoutMS, "rgb(f9e2af)", h.messages.confirmCancel)
+	})
+}
+
 // CueStop emits the stop cue.
 func (h *HyprNotify) CueStop(ctx context.Context) {
 	h.playCue(ctx, cueStop)
@@ -100,23 +177,65 @@ func (h *HyprNotify) CueCancel(ctx context.Context) {
 	h.playCue(ctx, cueCancel)
 }
 
+// CueFallback emits the cue signalling capture fell back to a non-primary device.
+func (h *HyprNotify) CueFallback(ctx context.Context) {
+	h.playCue(ctx, cueFallback)
+}
+
 // Hide dismisses the active indicator surface.
 func (h *HyprNotify) Hide(ctx context.Context) {
 	if !h.cfg.Enable {
 		return
 	}
+	h.setInterimLive(false)
 	h.run(ctx, h.dismiss)
 }
 
-// FocusedMonitor returns the monitor captured when recording began.
+// PreviewCue plays one named cue (start, stop, complete, or cancel) and waits
+// for playback to finish. It ignores indicator.sound_enable so users can check
+// output before turning cues on.
+func (h *HyprNotify) PreviewCue(ctx context.Context, name string) error {
+	kind, ok := cueKindByName[name]
+	if !ok {
+		return fmt.Errorf("unknown cue kind %q", name)
+	}
+	h.soundMu.Lock()
+	defer h.soundMu.Unlock()
+	return emitCue(ctx, kind, h.pulseID, h.cfg.SoundSink)
+}
+
+// FocusedMonitor returns the monitor captured when recording began. It is
+// reported in session results only: hyprctl notify cannot target a monitor.
 func (h *HyprNotify) FocusedMonitor() string {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	return h.focusedMonitor
 }
 
+// setInterimLive toggles whether ShowInterim may update the indicator.
+func (h *HyprNotify) setInterimLive(live bool) {
+	h.mu.Lock()
+	h.interimLive = live
+	h.mu.Unlock()
+}
+
+// interimTail keeps the last interimDisplayRunes runes of text, marking the
+// cut with an ellipsis.
+func interimTail(text string) string {
+	runes := []rune(text)
+	if len(runes) <= interimDisplayRunes {
+		return text
+	}
+	return "…" + strings.TrimSpace(string(runes[len(runes)-interimDisplayRunes:]))
+}
+
 // ensureFocusedMonitor resolves and caches the focused monitor once per session.
+// The desktop backend never targets a monitor, so it skips the hyprctl query.
 func (h *HyprNotify) ensureFocusedMonitor(ctx context.Context) {
+	if h.desktopBackend() {
+		return
+	}
+
 	h.mu.Lock()
 	alreadySet := h.focusedMonitor != ""
 	h.mu.Unlock()
@@ -136,25 +255,39 @@ func (h *HyprNotify) ensureFocusedMonitor(ctx context.Context) {
 }
 
 // notify dispatches indicator output through the configured backend.
+//
+// persistentTimeoutMS is translated to each backend's "until dismissed" form.
 func (h *HyprNotify) notify(ctx context.Context, icon int, timeoutMS int, color string, text string) error {
-	if strings.EqualFold(strings.TrimSpace(h.cfg.Backend), "desktop") {
-		return h.notifyDesktop(ctx, timeoutMS, text)
+	if h.desktopBackend() {
+		if timeoutMS == persistentTimeoutMS {
+			timeoutMS = 0 // freedesktop: never expire
+		}
+		return h.notifyDesktop(ctx, &h.desktopNotificationID, timeoutMS, text)
+	}
+	if timeoutMS == persistentTimeoutMS {
+		timeoutMS = hyprPersistentTimeoutMS
 	}
 	return hypr.Notify(ctx, icon, timeoutMS, color, text)
 }
 
 // dismiss removes indicator output from the configured backend.
 func (h *HyprNotify) dismiss(ctx context.Context) error {
-	if strings.EqualFold(strings.TrimSpace(h.cfg.Backend), "desktop") {
+	if h.desktopBackend() {
 		return h.dismissDesktop(ctx)
 	}
 	return hypr.DismissNotify(ctx)
 }
 
-// notifyDesktop sends a replaceable desktop notification and stores its ID.
-func (h *HyprNotify) notifyDesktop(ctx context.Context, timeoutMS int, text string) error {
+// desktopBackend reports whether indicator output goes through desktop DBus.
+func (h *HyprNotify) desktopBackend() bool {
+	return strings.EqualFold(strings.TrimSpace(h.cfg.Backend), "desktop")
+}
+
+// notifyDesktop sends a desktop notification that replaces the one in slot,
+// then stores the new ID there. slot is guarded by h.mu.
+func (h *HyprNotify) notifyDesktop(ctx context.Context, slot *uint32, timeoutMS int, text string) error {
 	h.mu.Lock()
-	replaceID := h.desktopNotificationID
+	replaceID := *slot
 	h.mu.Unlock()
 
 	appName := strings.TrimSpace(h.cfg.DesktopAppName)
@@ -168,27 +301,40 @@ func (h *HyprNotify) notifyDesktop(ctx context.Context, timeoutMS int, text stri
 	}
 
 	h.mu.Lock()
-	h.desktopNotificationID = id
+	*slot = id
 	h.mu.Unlock()
 	return nil
 }
 
-// dismissDesktop closes the current desktop notification ID when present.
+// dismissDesktop closes the current desktop notification and any open
+// confirm-cancel prompt.
 func (h *HyprNotify) dismissDesktop(ctx context.Context) error {
 	h.mu.Lock()
-	id := h.desktopNotificationID
-	h.desktopNotificationID = 0
+	ids := []uint32{h.desktopNotificationID, h.desktopPromptID}
+	h.desktopNotificationID, h.desktopPromptID = 0, 0
 	h.mu.Unlock()
 
-	if id == 0 {
-		return nil
+	var firstErr error
+	for _, id := range ids {
+		if id == 0 {
+			continue
+		}
+		if err := desktopDismiss(ctx, id); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	return desktopDismiss(ctx, id)
+	return firstErr
 }
 
-// run executes an indicator operation with a bounded timeout.
+// run executes an indicator operation with a bounded timeout, one at a time.
 func (h *HyprNotify) run(ctx context.Context, fn func(context.Context) error) {
-	runCtx, cancel := context.WithTimeout(ctx, 400*time.Millisecond)
+	h.dispatchMu.Lock()
+	defer h.dispatchMu.Unlock()
+	timeout := time.Duration(h.cfg.DispatchTimeoutMS) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 400 * time.Millisecond
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	if err := fn(runCtx); err != nil {
 		h.log("indicator dispatch failed", err)
@@ -206,7 +352,7 @@ func (h *HyprNotify) playCue(ctx context.Context, kind cueKind) {
 	go func() {
 		h.soundMu.Lock()
 		defer h.soundMu.Unlock()
-		if err := emitCue(ctx, kind); err != nil {
+		if err := emitCue(ctx, kind, h.pulseID, h.cfg.SoundSink); err != nil {
 			h.log("indicator audio cue failed", err)
 		}
 	}()
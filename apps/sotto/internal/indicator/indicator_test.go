@@ -1,12 +1,16 @@
 package indicator
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/rbright/sotto/internal/audio"
 	"github.com/rbright/sotto/internal/config"
 	"github.com/stretchr/testify/require"
 )
@@ -26,7 +30,7 @@ printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
 	cfg.SoundEnable = false
 	cfg.Enable = true
 
-	notify := NewHyprNotify(cfg, nil)
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
 	notify.ShowRecording(context.Background())
 	notify.ShowTranscribing(context.Background())
 	notify.ShowError(context.Background(), "")
@@ -55,7 +59,7 @@ printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
 	cfg.SoundEnable = false
 	cfg.ErrorTimeoutMS = 0 // exercises fallback to 1200ms
 
-	notify := NewHyprNotify(cfg, nil)
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
 	notify.ShowError(context.Background(), "custom error")
 
 	data, err := os.ReadFile(argsFile)
@@ -63,6 +67,50 @@ printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
 	require.Equal(t, "--quiet dispatch notify 3 1200 rgb(f38ba8) custom error\n", string(data))
 }
 
+func TestHyprNotifyShowErrorPersistentTimeout(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "hypr-args.log")
+	t.Setenv("HYPR_ARGS_FILE", argsFile)
+	installHyprctlStub(t, `
+printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
+`)
+
+	cfg := config.Default().Indicator
+	cfg.SoundEnable = false
+	cfg.ErrorTimeoutMS = -1
+
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	notify.ShowError(context.Background(), "sticky error")
+
+	data, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Equal(t, "--quiet dispatch notify 3 86400000 rgb(f38ba8) sticky error\n", string(data))
+}
+
+func TestDesktopIndicatorShowErrorTimedAndPersistent(t *testing.T) {
+	busctlArgs := filepath.Join(t.TempDir(), "busctl-args.log")
+	t.Setenv("BUSCTL_ARGS_FILE", busctlArgs)
+	installBusctlStub(t)
+
+	cfg := config.Default().Indicator
+	cfg.SoundEnable = false
+	cfg.Backend = "desktop"
+	cfg.ErrorTimeoutMS = 2500
+
+	timed := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	timed.ShowError(context.Background(), "timed error")
+
+	cfg.ErrorTimeoutMS = -1
+	persistent := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	persistent.ShowError(context.Background(), "sticky error")
+
+	data, err := os.ReadFile(busctlArgs)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasSuffix(lines[0], "timed error  0 0 2500"), lines[0])
+	require.True(t, strings.HasSuffix(lines[1], "sticky error  0 0 0"), lines[1])
+}
+
 func TestDesktopIndicatorUsesBusctlNotifyAndDismiss(t *testing.T) {
 	busctlArgs := filepath.Join(t.TempDir(), "busctl-args.log")
 	t.Setenv("BUSCTL_ARGS_FILE", busctlArgs)
@@ -84,7 +132,7 @@ printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
 	cfg.Backend = "desktop"
 	cfg.DesktopAppName = "sotto-indicator"
 
-	notify := NewHyprNotify(cfg, nil)
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
 	notify.ShowRecording(context.Background())
 	notify.ShowTranscribing(context.Background())
 	notify.Hide(context.Background())
@@ -98,6 +146,160 @@ printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
 	require.Contains(t, lines[2], "CloseNotification u 42")
 }
 
+func TestDesktopIndicatorConfirmCancelKeepsRecordingNotification(t *testing.T) {
+	busctlArgs := filepath.Join(t.TempDir(), "busctl-args.log")
+	t.Setenv("BUSCTL_ARGS_FILE", busctlArgs)
+	installBusctlStub(t)
+
+	cfg := config.Default().Indicator
+	cfg.Enable = true
+	cfg.SoundEnable = false
+	cfg.TrackMonitor = false
+	cfg.Backend = "desktop"
+	cfg.DesktopAppName = "sotto-indicator"
+
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	notify.ShowRecording(context.Background())
+	notify.ShowConfirmCancel(context.Background(), 2*time.Second)
+	notify.ShowConfirmCancel(context.Background(), 2*time.Second)
+	notify.Hide(context.Background())
+
+	data, err := os.ReadFile(busctlArgs)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 5)
+	require.Contains(t, lines[0], "Notify susssasa{sv}i sotto-indicator 0")
+	require.Contains(t, lines[1], "Notify susssasa{sv}i sotto-indicator 0")
+	require.Contains(t, lines[2], "Notify susssasa{sv}i sotto-indicator 43")
+	require.True(t, strings.HasSuffix(lines[2], " 2000"), lines[2])
+	require.Contains(t, lines[3], "CloseNotification u 42")
+	require.Contains(t, lines[4], "CloseNotification u 43")
+}
+
+func TestDesktopIndicatorSkipsFocusedMonitorQuery(t *testing.T) {
+	t.Setenv("BUSCTL_ARGS_FILE", filepath.Join(t.TempDir(), "busctl-args.log"))
+	installBusctlStub(t)
+
+	hyprArgs := filepath.Join(t.TempDir(), "hypr-args.log")
+	t.Setenv("HYPR_ARGS_FILE", hyprArgs)
+	installHyprctlStub(t, `
+printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
+echo '[{"name":"DP-1","focused":true}]'
+`)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cfg := config.Default().Indicator
+	cfg.Enable = true
+	cfg.SoundEnable = false
+	cfg.Backend = "desktop"
+
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, logger)
+	notify.ShowRecording(context.Background())
+
+	require.Empty(t, notify.FocusedMonitor())
+	require.NotContains(t, logs.String(), "focused monitor query failed")
+	_, err := os.Stat(hyprArgs)
+	require.True(t, os.IsNotExist(err), "hyprctl should not run on the desktop backend")
+}
+
+func TestHyprNotifyTrackMonitorDisabledSkipsMonitorQuery(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "hypr-args.log")
+	t.Setenv("HYPR_ARGS_FILE", argsFile)
+	installHyprctlStub(t, `
+printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
+if [[ "${1:-}" == "-j" && "${2:-}" == "monitors" ]]; then
+  echo '[{"name":"DP-1","focused":true}]'
+fi
+`)
+
+	cfg := config.Default().Indicator
+	cfg.Enable = true
+	cfg.SoundEnable = false
+	cfg.TrackMonitor = false
+
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	notify.ShowRecording(context.Background())
+
+	require.Empty(t, notify.FocusedMonitor())
+	data, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Equal(t, "--quiet dispatch notify 1 300000 rgb(89b4fa) Recording…\n", string(data))
+}
+
+func TestHyprNotifyShowInterimUpdatesUntilHide(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "hypr-args.log")
+	t.Setenv("HYPR_ARGS_FILE", argsFile)
+	installHyprctlStub(t, `
+printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
+`)
+
+	cfg := config.Default().Indicator
+	cfg.Enable = true
+	cfg.SoundEnable = false
+	cfg.TrackMonitor = false
+
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	notify.ShowInterim(context.Background(), "before recording")
+	notify.ShowRecording(context.Background())
+	notify.ShowInterim(context.Background(), "hello world")
+	notify.Hide(context.Background())
+	notify.ShowInterim(context.Background(), "late update")
+
+	data, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"--quiet dispatch notify 1 300000 rgb(89b4fa) Recording…",
+		"--quiet dispatch dismissnotify",
+		"--quiet dispatch notify 1 300000 rgb(89b4fa) Recording… hello world",
+		"--quiet dispatch dismissnotify",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+}
+
+func TestHyprNotifyInFlightInterimCannotOverrideLaterState(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "hypr-args.log")
+	t.Setenv("HYPR_ARGS_FILE", argsFile)
+	// A slow dismiss holds the interim update mid-dispatch.
+	installHyprctlStub(t, `
+if [[ "$*" == *dismissnotify* ]]; then
+  sleep 0.1
+fi
+printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
+`)
+
+	cfg := config.Default().Indicator
+	cfg.SoundEnable = false
+	cfg.TrackMonitor = false
+
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	notify.ShowRecording(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		notify.ShowInterim(context.Background(), "hello world")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	notify.ShowTranscribing(context.Background())
+	<-done
+
+	data, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Equal(t, "--quiet dispatch notify 1 300000 rgb(cba6f7) Transcribing…", lines[len(lines)-1])
+}
+
+func TestInterimTailKeepsMostRecentText(t *testing.T) {
+	require.Equal(t, "short", interimTail("short"))
+
+	long := strings.Repeat("a", interimDisplayRunes) + " tail"
+	got := interimTail(long)
+	require.True(t, strings.HasPrefix(got, "…"))
+	require.True(t, strings.HasSuffix(got, " tail"))
+	require.Equal(t, interimDisplayRunes+1, len([]rune(got)))
+}
+
 func TestHyprNotifyDisabledSkipsHyprctlDispatch(t *testing.T) {
 	argsFile := filepath.Join(t.TempDir(), "hypr-args.log")
 	t.Setenv("HYPR_ARGS_FILE", argsFile)
@@ -109,7 +311,7 @@ printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
 	cfg.Enable = false
 	cfg.SoundEnable = false
 
-	notify := NewHyprNotify(cfg, nil)
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
 	notify.ShowRecording(context.Background())
 	notify.ShowTranscribing(context.Background())
 	notify.ShowError(context.Background(), "ignored")
@@ -129,11 +331,59 @@ exit 1
 	cfg.Enable = true
 	cfg.SoundEnable = false
 
-	notify := NewHyprNotify(cfg, nil)
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
 	notify.ShowRecording(context.Background())
 	require.Empty(t, notify.FocusedMonitor())
 }
 
+func TestHyprNotifyDispatchTimeoutIsConfigurable(t *testing.T) {
+	installHyprctlStub(t, `
+sleep 0.3
+`)
+
+	for _, tc := range []struct {
+		timeoutMS  int
+		wantFailed bool
+	}{
+		{timeoutMS: 2000, wantFailed: false},
+		{timeoutMS: 50, wantFailed: true},
+	} {
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		cfg := config.Default().Indicator
+		cfg.SoundEnable = false
+		cfg.DispatchTimeoutMS = tc.timeoutMS
+
+		notify := NewHyprNotify(cfg, audio.ClientIdentity{}, logger)
+		notify.Hide(context.Background())
+
+		require.Equal(t, tc.wantFailed, strings.Contains(logs.String(), "indicator dispatch failed"), "timeout %dms: %s", tc.timeoutMS, logs.String())
+	}
+}
+
+func TestPreviewCuePlaysNamedCue(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "pw-play-args.log")
+	t.Setenv("PW_PLAY_ARGS_FILE", argsFile)
+	dir := t.TempDir()
+	script := "#!/usr/bin/env bash\nset -euo pipefail\ncat > /dev/null\nprintf '%s\\n' \"$*\" >> \"${PW_PLAY_ARGS_FILE}\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pw-play"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+
+	cfg := config.Default().Indicator
+	cfg.SoundEnable = false
+
+	notify := NewHyprNotify(cfg, audio.ClientIdentity{}, nil)
+	require.ErrorContains(t, notify.PreviewCue(context.Background(), "fallback"), `unknown cue kind "fallback"`)
+	_, err := os.Stat(argsFile)
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, notify.PreviewCue(context.Background(), "complete"))
+	data, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Equal(t, "--media-role Notification -\n", string(data))
+}
+
 func installHyprctlStub(t *testing.T, body string) {
 	t.Helper()
 
@@ -153,7 +403,12 @@ func installBusctlStub(t *testing.T) {
 set -euo pipefail
 printf '%s\n' "$*" >> "${BUSCTL_ARGS_FILE}"
 if [[ "$*" == *" Notify "* ]]; then
-  echo "u 42"
+  # Replacements keep their ID; new notifications count up from 42.
+  if [[ "${9}" != "0" ]]; then
+    echo "u ${9}"
+  else
+    echo "u $((41 + $(grep -c ' Notify susssasa{sv}i [^ ]* 0 ' "${BUSCTL_ARGS_FILE}")))"
+  fi
 fi
 `
 	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
@@ -12,9 +12,10 @@ const (
 )
 
 type messages struct {
-	recording  string
-	processing string
-	errorText  string
+	recording     string
+	processing    string
+	errorText     string
+	confirmCancel string
 }
 
 func indicatorMessagesFromEnv() messages {
@@ -35,9 +36,10 @@ func indicatorMessages(tag locale) messages {
 		fallthrough
 	default:
 		return messages{
-			recording:  "Recording…",
-			processing: "Transcribing…",
-			errorText:  "Speech recognition error",
+			recording:     "Recording…",
+			processing:    "Transcribing…",
+			errorText:     "Speech recognition error",
+			confirmCancel: "Press cancel again to discard",
 		}
 	}
 }
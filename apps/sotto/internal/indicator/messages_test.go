@@ -16,4 +16,5 @@ func TestIndicatorMessagesEnglish(t *testing.T) {
 	require.Equal(t, "Recording…", msg.recording)
 	require.Equal(t, "Transcribing…", msg.processing)
 	require.Equal(t, "Speech recognition error", msg.errorText)
+	require.Equal(t, "Press cancel again to discard", msg.confirmCancel)
 }
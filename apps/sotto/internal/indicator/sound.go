@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/jfreymuth/pulse"
+	"github.com/rbright/sotto/internal/audio"
 )
 
 // cueKind identifies each cue event used by the session lifecycle.
@@ -20,8 +21,17 @@ const (
 	cueStop
 	cueComplete
 	cueCancel
+	cueFallback
 )
 
+// cueKindByName maps user-facing cue names to cue kinds.
+var cueKindByName = map[string]cueKind{
+	"start":    cueStart,
+	"stop":     cueStop,
+	"complete": cueComplete,
+	"cancel":   cueCancel,
+}
+
 const cueSampleRate = 16000
 
 // toneSpec describes one synthesized cue tone segment.
@@ -55,16 +65,24 @@ var (
 		{frequencyHz: 480, duration: 75 * time.Millisecond, volume: 0.18},
 		{frequencyHz: 360, duration: 90 * time.Millisecond, volume: 0.18},
 	})
+	// fallbackCuePCM is a repeated mid tone so it cannot be mistaken for start/stop.
+	fallbackCuePCM = synthesizeCue([]toneSpec{
+		{frequencyHz: 660, duration: 55 * time.Millisecond, volume: 0.18},
+		{frequencyHz: 660, duration: 55 * time.Millisecond, volume: 0.18},
+		{frequencyHz: 660, duration: 55 * time.Millisecond, volume: 0.18},
+	})
 )
 
 // emitCue plays an embedded WAV cue when available, then falls back to synthesis.
-func emitCue(ctx context.Context, kind cueKind) error {
+// cueFallback has no embedded asset and always uses synthesis.
+// A non-empty sink targets that playback sink instead of the system default.
+func emitCue(ctx context.Context, kind cueKind, pulseID audio.ClientIdentity, sink string) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
 	if data := cueWAV(kind); len(data) > 0 {
-		if err := playCueData(ctx, data); err == nil {
+		if err := playCueData(ctx, data, sink); err == nil {
 			return nil
 		}
 	}
@@ -78,7 +96,7 @@ func emitCue(ctx context.Context, kind cueKind) error {
 		return nil
 	}
 
-	return playSynthCue(samples)
+	return playSynthCue(samples, pulseID, sink)
 }
 
 func cueWAV(kind cueKind) []byte {
@@ -104,8 +122,18 @@ func mustCueWAV(path string) []byte {
 	return data
 }
 
+// pwPlayArgs returns the pw-play arguments for reading a cue from stdin,
+// targeting sink when it is set.
+func pwPlayArgs(sink string) []string {
+	args := []string{"--media-role", "Notification"}
+	if sink != "" {
+		args = append(args, "--target", sink)
+	}
+	return append(args, "-")
+}
+
 // playCueData plays an embedded WAV payload through pw-play.
-func playCueData(ctx context.Context, data []byte) error {
+func playCueData(ctx context.Context, data []byte, sink string) error {
 	if len(data) == 0 {
 		return fmt.Errorf("embedded cue payload is empty")
 	}
@@ -116,7 +144,7 @@ func playCueData(ctx context.Context, data []byte) error {
 	runCtx, cancel := context.WithTimeout(ctx, 4*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(runCtx, "pw-play", "--media-role", "Notification", "-")
+	cmd := exec.CommandContext(runCtx, "pw-play", pwPlayArgs(sink)...)
 	cmd.Stdin = bytes.NewReader(data)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("play embedded cue: %w", err)
@@ -124,17 +152,29 @@ func playCueData(ctx context.Context, data []byte) error {
 	return nil
 }
 
-// playSynthCue streams synthesized PCM samples through Pulse playback.
-func playSynthCue(samples []int16) error {
-	client, err := pulse.NewClient(
-		pulse.ClientApplicationName("sotto"),
-		pulse.ClientApplicationIconName("audio-input-microphone"),
-	)
+// playSynthCue streams synthesized PCM samples through Pulse playback, on
+// sink when it is set.
+func playSynthCue(samples []int16, pulseID audio.ClientIdentity, sink string) error {
+	client, err := audio.NewClient(pulseID)
 	if err != nil {
 		return fmt.Errorf("connect pulse server: %w", err)
 	}
 	defer client.Close()
 
+	options := []pulse.PlaybackOption{
+		pulse.PlaybackMono,
+		pulse.PlaybackSampleRate(cueSampleRate),
+		pulse.PlaybackLatency(0.02),
+		pulse.PlaybackMediaName("sotto indicator cue"),
+	}
+	if sink != "" {
+		target, err := client.SinkByID(sink)
+		if err != nil {
+			return fmt.Errorf("resolve sink %q: %w", sink, err)
+		}
+		options = append(options, pulse.PlaybackSink(target))
+	}
+
 	cursor := 0
 	reader := pulse.Int16Reader(func(buf []int16) (int, error) {
 		if cursor >= len(samples) {
@@ -149,13 +189,7 @@ func playSynthCue(samples []int16) error {
 		return n, nil
 	})
 
-	stream, err := client.NewPlayback(
-		reader,
-		pulse.PlaybackMono,
-		pulse.PlaybackSampleRate(cueSampleRate),
-		pulse.PlaybackLatency(0.02),
-		pulse.PlaybackMediaName("sotto indicator cue"),
-	)
+	stream, err := client.NewPlayback(reader, options...)
 	if err != nil {
 		return fmt.Errorf("create pulse playback stream: %w", err)
 	}
@@ -181,6 +215,8 @@ func cueSamples(kind cueKind) []int16 {
 		return completeCuePCM
 	case cueCancel:
 		return cancelCuePCM
+	case cueFallback:
+		return fallbackCuePCM
 	default:
 		return nil
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rbright/sotto/internal/audio"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,6 +15,8 @@ func TestCueSamplesPresent(t *testing.T) {
 	require.NotEmpty(t, cueSamples(cueStop))
 	require.NotEmpty(t, cueSamples(cueComplete))
 	require.NotEmpty(t, cueSamples(cueCancel))
+	require.NotEmpty(t, cueSamples(cueFallback))
+	require.NotEqual(t, cueSamples(cueStart), cueSamples(cueFallback))
 }
 
 func TestCueEmbeddedWAVPresent(t *testing.T) {
@@ -40,11 +43,16 @@ func TestSamplesForDuration(t *testing.T) {
 	require.Greater(t, samplesForDuration(25*time.Millisecond), 0)
 }
 
+func TestPWPlayArgsTargetsConfiguredSink(t *testing.T) {
+	require.Equal(t, []string{"--media-role", "Notification", "-"}, pwPlayArgs(""))
+	require.Equal(t, []string{"--media-role", "Notification", "--target", "alsa_output.usb-headset", "-"}, pwPlayArgs("alsa_output.usb-headset"))
+}
+
 func TestEmitCueRespectsCancelledContext(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	err := emitCue(ctx, cueStart)
+	err := emitCue(ctx, cueStart, audio.ClientIdentity{}, "")
 	require.Error(t, err)
 	require.True(t, errors.Is(err, context.Canceled))
 }
@@ -47,14 +47,21 @@ func Send(ctx context.Context, path string, req Request, timeout time.Duration)
 
 // Probe checks whether a responsive owner is currently listening on path.
 func Probe(ctx context.Context, path string, timeout time.Duration) (bool, error) {
-	_, err := Send(ctx, path, Request{Command: "status"}, timeout)
+	_, alive, err := ProbeOwner(ctx, path, timeout)
+	return alive, err
+}
+
+// ProbeOwner is Probe that also reports the owner's PID from the status
+// handshake. The PID is zero for owners that predate the owner_pid field.
+func ProbeOwner(ctx context.Context, path string, timeout time.Duration) (int, bool, error) {
+	resp, err := Send(ctx, path, Request{Command: "status"}, timeout)
 	if err == nil {
-		return true, nil
+		return resp.OwnerPID, true, nil
 	}
 	if isSocketMissing(err) || isConnectionRefused(err) {
-		return false, nil
+		return 0, false, nil
 	}
-	return false, fmt.Errorf("probe socket: %w", err)
+	return 0, false, fmt.Errorf("probe socket: %w", err)
 }
 
 // isSocketMissing reports absent-socket failures.
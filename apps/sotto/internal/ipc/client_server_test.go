@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"net"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -147,6 +148,11 @@ func TestProbe(t *testing.T) {
 	require.NoError(t, probeErr)
 	require.True(t, alive)
 
+	ownerPID, alive, probeErr := ProbeOwner(context.Background(), socketPath, 200*time.Millisecond)
+	require.NoError(t, probeErr)
+	require.True(t, alive)
+	require.Equal(t, os.Getpid(), ownerPID)
+
 	cancel()
 	require.NoError(t, <-serveDone)
 
@@ -1,15 +1,42 @@
 // Package ipc provides single-instance unix-socket protocol and server/client helpers.
 package ipc
 
-// Request is one command sent over the local unix-domain socket.
+// Request is one command sent over the local unix-domain socket. Overrides
+// apply to the session a toggle or ptt-start begins and are ignored otherwise.
 type Request struct {
-	Command string `json:"command"`
+	Command   string            `json:"command"`
+	NoPaste   bool              `json:"no_paste,omitempty"`
+	Overrides *SessionOverrides `json:"overrides,omitempty"`
+}
+
+// SessionOverrides carries the per-session --punctuation, --code, --model,
+// --vocab, and --phrase flags to the owner. Zero fields keep the configured
+// value.
+type SessionOverrides struct {
+	// Punctuation is "on" or "off".
+	Punctuation string          `json:"punctuation,omitempty"`
+	Code        bool            `json:"code,omitempty"`
+	Model       string          `json:"model,omitempty"`
+	Vocab       []string        `json:"vocab,omitempty"`
+	Phrases     []SessionPhrase `json:"phrases,omitempty"`
+}
+
+// SessionPhrase is one --phrase speech context; a zero Boost means
+// asr.default_boost.
+type SessionPhrase struct {
+	Phrase string  `json:"phrase"`
+	Boost  float32 `json:"boost,omitempty"`
 }
 
 // Response is the normalized command outcome returned by the owner session.
+// OwnerPID identifies the process holding the socket; Serve fills it in.
+// ElapsedMS and Bytes report live session progress for the partial command.
 type Response struct {
-	OK      bool   `json:"ok"`
-	State   string `json:"state,omitempty"`
-	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
+	OK        bool   `json:"ok"`
+	State     string `json:"state,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Error     string `json:"error,omitempty"`
+	OwnerPID  int    `json:"owner_pid,omitempty"`
+	ElapsedMS int64  `json:"elapsed_ms,omitempty"`
+	Bytes     int64  `json:"bytes,omitempty"`
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"sync"
 )
 
@@ -60,6 +61,7 @@ func Serve(ctx context.Context, listener net.Listener, handler Handler) error {
 			}
 
 			resp := handler.Handle(ctx, req)
+			resp.OwnerPID = os.Getpid()
 			_ = json.NewEncoder(c).Encode(resp)
 		}(conn)
 	}
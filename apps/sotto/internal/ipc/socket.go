@@ -8,12 +8,30 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 )
 
 // ErrAlreadyRunning indicates a responsive owner already holds the runtime socket.
 var ErrAlreadyRunning = errors.New("sotto session already running")
 
+// AlreadyRunningError is returned by Acquire when a responsive owner answered
+// the probe. It matches ErrAlreadyRunning under errors.Is.
+type AlreadyRunningError struct {
+	OwnerPID int
+}
+
+func (e *AlreadyRunningError) Error() string {
+	if e.OwnerPID > 0 {
+		return fmt.Sprintf("%s (pid %d)", ErrAlreadyRunning, e.OwnerPID)
+	}
+	return ErrAlreadyRunning.Error()
+}
+
+func (e *AlreadyRunningError) Unwrap() error {
+	return ErrAlreadyRunning
+}
+
 // RuntimeSocketPath returns the owner socket path derived from XDG_RUNTIME_DIR.
 func RuntimeSocketPath() (string, error) {
 	runtimeDir := strings.TrimSpace(os.Getenv("XDG_RUNTIME_DIR"))
@@ -24,6 +42,8 @@ func RuntimeSocketPath() (string, error) {
 }
 
 // Acquire attempts to become the owner listener, cleaning stale sockets when safe.
+// Concurrent callers are serialized on a lock file next to path, so a stale
+// socket is never removed after another caller has already replaced it.
 func Acquire(
 	ctx context.Context,
 	path string,
@@ -35,6 +55,12 @@ func Acquire(
 		return nil, fmt.Errorf("ensure runtime socket dir: %w", err)
 	}
 
+	unlock, err := lockAcquire(ctx, path+".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	for attempt := 0; attempt <= retries; attempt++ {
 		listener, err := net.Listen("unix", path)
 		if err == nil {
@@ -46,9 +72,9 @@ func Acquire(
 			return nil, fmt.Errorf("listen unix %s: %w", path, err)
 		}
 
-		alive, probeErr := Probe(ctx, path, probeTimeout)
+		ownerPID, alive, probeErr := ProbeOwner(ctx, path, probeTimeout)
 		if alive {
-			return nil, ErrAlreadyRunning
+			return nil, &AlreadyRunningError{OwnerPID: ownerPID}
 		}
 		if probeErr != nil {
 			return nil, fmt.Errorf("probe existing socket %s: %w", path, probeErr)
@@ -74,6 +100,65 @@ func Acquire(
 	return nil, fmt.Errorf("failed to acquire socket %s after %d retries", path, retries)
 }
 
+// RemoveStaleSocket removes the socket at path when no owner answers on it,
+// holding the same lock as Acquire so a starting owner's socket is never
+// removed. It reports whether the socket was removed and, when an owner is
+// alive, its PID. A missing socket is not an error.
+func RemoveStaleSocket(ctx context.Context, path string, probeTimeout time.Duration) (bool, int, error) {
+	if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+		return false, 0, nil
+	}
+
+	unlock, err := lockAcquire(ctx, path+".lock")
+	if err != nil {
+		return false, 0, err
+	}
+	defer unlock()
+
+	ownerPID, alive, probeErr := ProbeOwner(ctx, path, probeTimeout)
+	if alive {
+		return false, ownerPID, nil
+	}
+	if probeErr != nil {
+		return false, 0, fmt.Errorf("probe existing socket %s: %w", path, probeErr)
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, 0, nil
+		}
+		return false, 0, fmt.Errorf("remove stale socket %s: %w", path, err)
+	}
+	return true, 0, nil
+}
+
+// lockAcquire takes an exclusive flock on lockPath, polling until ctx is done.
+// The lock file is left in place; unlinking it would let two callers lock
+// different inodes.
+func lockAcquire(ctx context.Context, lockPath string) (func(), error) {
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open socket lock %s: %w", lockPath, err)
+	}
+
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return func() { _ = file.Close() }, nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			_ = file.Close()
+			return nil, fmt.Errorf("lock %s: %w", lockPath, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = file.Close()
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
 // isAddrInUse identifies listener errors caused by an existing socket path.
 func isAddrInUse(err error) bool {
 	if err == nil {
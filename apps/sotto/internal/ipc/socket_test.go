@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -61,6 +62,10 @@ func TestAcquireReturnsAlreadyRunningWhenSocketResponsive(t *testing.T) {
 	if !errors.Is(err, ErrAlreadyRunning) {
 		t.Fatalf("Acquire() error = %v, want ErrAlreadyRunning", err)
 	}
+	var running *AlreadyRunningError
+	if !errors.As(err, &running) || running.OwnerPID != os.Getpid() {
+		t.Fatalf("Acquire() error = %v, want owner pid %d", err, os.Getpid())
+	}
 
 	cancel()
 	if serveErr := <-serverDone; serveErr != nil {
@@ -103,6 +108,55 @@ func TestAcquireDoesNotUnlinkWhenProbeInconclusive(t *testing.T) {
 	<-acceptDone
 }
 
+func TestAcquireConcurrentCallersElectSingleOwner(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "sotto.sock")
+	require.NoError(t, os.WriteFile(socketPath, []byte("stale"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const callers = 8
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		owners    int
+		forwarded int
+		serveDone = make(chan error, callers)
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listener, err := Acquire(ctx, socketPath, 500*time.Millisecond, 2, nil)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				owners++
+				go func() {
+					serveDone <- Serve(ctx, listener, HandlerFunc(func(_ context.Context, _ Request) Response {
+						return Response{OK: true, State: "recording"}
+					}))
+				}()
+			case errors.Is(err, ErrAlreadyRunning):
+				forwarded++
+			default:
+				t.Errorf("Acquire() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, owners)
+	require.Equal(t, callers-1, forwarded)
+
+	cancel()
+	require.NoError(t, <-serveDone)
+}
+
 func TestRuntimeSocketPathRequiresXDG(t *testing.T) {
 	t.Setenv("XDG_RUNTIME_DIR", "")
 	_, err := RuntimeSocketPath()
@@ -6,7 +6,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"strings"
+
+	"github.com/rbright/sotto/internal/config"
 )
 
 // Runtime bundles the configured logger and its open file handle lifecycle.
@@ -44,14 +45,16 @@ func New() (Runtime, error) {
 	return Runtime{Logger: logger, Path: path, closer: f}, nil
 }
 
-// resolveLogPath selects XDG_STATE_HOME when available, otherwise ~/.local/state.
+// Path returns the JSONL log file location used by New.
+func Path() (string, error) {
+	return resolveLogPath()
+}
+
+// resolveLogPath places log.jsonl in the state dir (config.StateDir).
 func resolveLogPath() (string, error) {
-	if xdg := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); xdg != "" {
-		return filepath.Join(xdg, "sotto", "log.jsonl"), nil
-	}
-	home, err := os.UserHomeDir()
+	stateDir, err := config.StateDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".local", "state", "sotto", "log.jsonl"), nil
+	return filepath.Join(stateDir, "log.jsonl"), nil
 }
@@ -1,20 +1,30 @@
-// Package output applies transcript commit side effects (clipboard and paste).
+// Package output applies transcript commit side effects (clipboard, paste, and FIFO).
 package output
 
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os/exec"
+	"sync"
 	"time"
 
 	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/hypr"
 )
 
 // Committer applies transcript output side effects (clipboard + optional paste).
+// The clipboard step is skipped when output.clipboard_enable is false; paste
+// then only runs through paste_cmd, which gets the transcript on stdin.
+// output.fifo_path additionally streams each transcript to a FIFO, and
+// SetHistoryPath records each one in the history log.
 type Committer struct {
-	config config.Config
-	logger *slog.Logger
+	config      config.Config
+	logger      *slog.Logger
+	historyPath string
+	warnings    io.Writer
+	restores    sync.WaitGroup
 }
 
 // NewCommitter constructs a transcript committer from runtime config.
@@ -24,35 +34,198 @@ func NewCommitter(cfg config.Config, logger *slog.Logger) *Committer {
 
 // Commit writes transcript text to clipboard and optionally dispatches paste.
 func (c *Committer) Commit(ctx context.Context, transcript string) error {
+	return c.commit(ctx, transcript, c.config.Paste.Enable)
+}
+
+// CommitClipboardOnly writes transcript text to clipboard and never pastes,
+// regardless of paste.enable.
+func (c *Committer) CommitClipboardOnly(ctx context.Context, transcript string) error {
+	return c.commit(ctx, transcript, false)
+}
+
+func (c *Committer) commit(ctx context.Context, transcript string, paste bool) error {
 	if transcript == "" {
 		return nil
 	}
 
-	clipboardCtx, clipboardCancel := context.WithTimeout(ctx, 2*time.Second)
-	defer clipboardCancel()
-	if err := runCommandWithInput(clipboardCtx, c.config.Clipboard.Argv, transcript); err != nil {
-		return fmt.Errorf("set clipboard: %w", err)
+	// The previous clipboard must be read before it is overwritten. Restore
+	// only applies to pasted commits; a clipboard-only commit exists to leave
+	// the transcript on the clipboard.
+	var previous string
+	restore := false
+	if c.config.Output.ClipboardEnable {
+		c.warnIfOversized(transcript)
+		if paste && c.config.Output.RestoreClipboardMS > 0 {
+			previous, restore = c.readClipboard(ctx)
+		}
+		if err := c.setClipboard(ctx, transcript); err != nil {
+			return fmt.Errorf("set clipboard: %w", err)
+		}
 	}
+	c.writeFIFO(transcript)
+	c.appendHistory(transcript)
 
-	if !c.config.Paste.Enable {
+	if !paste {
+		return nil
+	}
+
+	if !c.config.Output.ClipboardEnable && len(c.config.PasteCmd.Argv) == 0 {
+		// The shortcut paste would insert whatever was on the clipboard before.
+		if c.logger != nil {
+			c.logger.Info("paste skipped; output.clipboard_enable is false and no paste_cmd is configured")
+		}
+		return nil
+	}
+	window, windowErr := c.pasteWindow(ctx)
+	if reason := c.pasteBlockReason(window, windowErr); reason != "" {
+		if c.logger != nil {
+			c.logger.Info("paste skipped for active window; clipboard remains set", "reason", reason)
+		}
+		return nil
+	}
+	if err := c.dispatchPaste(ctx, transcript, window, windowErr); err != nil {
+		c.logPasteFailure(err)
 		return nil
 	}
+	if restore {
+		// The restore delay must not hold up the commit; the owner waits for
+		// pending restores through Wait before it exits.
+		restoreCtx := context.WithoutCancel(ctx)
+		c.restores.Add(1)
+		go func() {
+			defer c.restores.Done()
+			c.restoreClipboard(restoreCtx, previous)
+		}()
+	}
+	return nil
+}
+
+// SetWarningOutput also prints commit warnings, such as an oversized
+// transcript, to w as "warning: ..." lines. Nil, the default, only logs them.
+func (c *Committer) SetWarningOutput(w io.Writer) {
+	c.warnings = w
+}
+
+// Wait blocks until pending clipboard restores finish.
+func (c *Committer) Wait() {
+	c.restores.Wait()
+}
 
+// dispatchPaste runs paste_cmd when configured, otherwise the default
+// Hyprland shortcut paste into window, the one resolved by pasteWindow.
+// Without the clipboard step, paste_cmd receives the transcript on stdin
+// since the clipboard does not hold it.
+func (c *Committer) dispatchPaste(ctx context.Context, transcript string, window hypr.ActiveWindow, windowErr error) error {
 	if len(c.config.PasteCmd.Argv) > 0 {
-		pasteCtx, pasteCancel := context.WithTimeout(ctx, 2*time.Second)
+		pasteCtx, pasteCancel := context.WithTimeout(ctx, c.pasteTimeout(2*time.Second))
 		defer pasteCancel()
-		if err := runCommandWithInput(pasteCtx, c.config.PasteCmd.Argv, ""); err != nil {
-			c.logPasteFailure(err)
+		input := ""
+		if !c.config.Output.ClipboardEnable {
+			input = transcript
 		}
-		return nil
+		return runCommandWithInput(pasteCtx, c.config.PasteCmd.Argv, input)
 	}
 
-	pasteCtx, pasteCancel := context.WithTimeout(ctx, 1200*time.Millisecond)
+	if windowErr != nil {
+		return windowErr
+	}
+	pasteCtx, pasteCancel := context.WithTimeout(ctx, c.pasteTimeout(1200*time.Millisecond))
 	defer pasteCancel()
-	if err := defaultPaste(pasteCtx, c.config.Paste.Shortcut); err != nil {
-		c.logPasteFailure(err)
+	return defaultPaste(pasteCtx, c.config.Paste.Shortcut, c.config.Paste.ShortcutFormat, window)
+}
+
+// pasteTimeout returns output.paste_timeout_ms, or builtin when it is unset.
+func (c *Committer) pasteTimeout(builtin time.Duration) time.Duration {
+	if c.config.Output.PasteTimeoutMS > 0 {
+		return time.Duration(c.config.Output.PasteTimeoutMS) * time.Millisecond
+	}
+	return builtin
+}
+
+// clipboardTimeout returns output.clipboard_timeout_ms as a duration.
+func (c *Committer) clipboardTimeout() time.Duration {
+	return time.Duration(c.config.Output.ClipboardTimeoutMS) * time.Millisecond
+}
+
+// readClipboard captures the current clipboard via clipboard_read_cmd. It
+// reports false when the read fails or the clipboard is empty, in which case
+// there is nothing to restore.
+func (c *Committer) readClipboard(ctx context.Context) (string, bool) {
+	argv := c.config.ClipboardReadCmd.Argv
+	if len(argv) == 0 {
+		return "", false
+	}
+
+	readCtx, readCancel := context.WithTimeout(ctx, c.clipboardTimeout())
+	defer readCancel()
+	out, err := exec.CommandContext(readCtx, argv[0], argv[1:]...).Output()
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn("clipboard read failed; previous clipboard will not be restored", "error", err.Error())
+		}
+		return "", false
+	}
+	if len(out) == 0 {
+		return "", false
+	}
+	return string(out), true
+}
+
+// restoreClipboard waits output.restore_clipboard_ms for the paste to land,
+// then puts previous back through clipboard_restore_cmd. clipboard_cmd is not
+// reused because it may trim the text it copies.
+func (c *Committer) restoreClipboard(ctx context.Context, previous string) {
+	time.Sleep(time.Duration(c.config.Output.RestoreClipboardMS) * time.Millisecond)
+
+	restoreCtx, restoreCancel := context.WithTimeout(ctx, c.clipboardTimeout())
+	defer restoreCancel()
+	if err := runCommandWithInput(restoreCtx, c.config.ClipboardRestoreCmd.Argv, previous); err != nil {
+		if c.logger != nil {
+			c.logger.Warn("clipboard restore failed; transcript remains on clipboard", "error", err.Error())
+		}
+		return
+	}
+	if c.logger != nil {
+		c.logger.Debug("previous clipboard restored", "bytes", len(previous))
+	}
+}
+
+// setClipboard tries clipboard commands in priority order until one succeeds.
+// Only the last failure is returned; earlier ones are logged.
+func (c *Committer) setClipboard(ctx context.Context, transcript string) error {
+	commands := append([]config.CommandConfig{c.config.Clipboard}, c.config.ClipboardFallbacks...)
+
+	var err error
+	for i, command := range commands {
+		clipboardCtx, clipboardCancel := context.WithTimeout(ctx, c.clipboardTimeout())
+		err = runCommandWithInput(clipboardCtx, command.Argv, transcript)
+		clipboardCancel()
+		if err == nil {
+			return nil
+		}
+		if i < len(commands)-1 && c.logger != nil {
+			c.logger.Warn("clipboard command failed; trying next", "error", err.Error())
+		}
+	}
+	return err
+}
+
+// warnIfOversized reports when transcript exceeds output.max_clipboard_bytes
+// so downstream truncation by a clipboard manager is not silent.
+func (c *Committer) warnIfOversized(transcript string) {
+	limit := c.config.Output.MaxClipboardBytes
+	if limit <= 0 || len(transcript) <= limit {
+		return
+	}
+	if c.logger != nil {
+		c.logger.Warn("transcript exceeds output.max_clipboard_bytes; clipboard managers may truncate it",
+			"bytes", len(transcript),
+			"limit", limit,
+		)
+	}
+	if c.warnings != nil {
+		fmt.Fprintf(c.warnings, "warning: transcript is %d bytes, over output.max_clipboard_bytes (%d); clipboard managers may truncate it\n", len(transcript), limit)
 	}
-	return nil
 }
 
 // runCommandWithInput executes argv and optionally writes input to stdin.
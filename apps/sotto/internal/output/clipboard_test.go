@@ -1,11 +1,14 @@
 package output
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rbright/sotto/internal/config"
 	"github.com/stretchr/testify/require"
@@ -46,6 +49,165 @@ func TestCommitterCommitWritesClipboardWhenPasteDisabled(t *testing.T) {
 	require.Equal(t, "captured transcript", string(data))
 }
 
+func TestCommitterCommitWarnsPastMaxClipboardBytes(t *testing.T) {
+	scriptPath := writeStdinCaptureScript(t)
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+
+	cfg := config.Default()
+	cfg.Paste.Enable = false
+	cfg.Clipboard = config.CommandConfig{Argv: []string{scriptPath, clipboardPath}}
+	cfg.Output.MaxClipboardBytes = 10
+
+	var logs, warnings bytes.Buffer
+	committer := NewCommitter(cfg, slog.New(slog.NewTextHandler(&logs, nil)))
+	committer.SetWarningOutput(&warnings)
+
+	require.NoError(t, committer.Commit(context.Background(), "short"))
+	require.NotContains(t, logs.String(), "max_clipboard_bytes")
+	require.Empty(t, warnings.String())
+
+	require.NoError(t, committer.Commit(context.Background(), "a much longer transcript"))
+	require.Contains(t, logs.String(), "exceeds output.max_clipboard_bytes")
+	require.Contains(t, logs.String(), "bytes=24 limit=10")
+	require.Equal(t, "warning: transcript is 24 bytes, over output.max_clipboard_bytes (10); clipboard managers may truncate it\n", warnings.String())
+
+	data, err := os.ReadFile(clipboardPath)
+	require.NoError(t, err)
+	require.Equal(t, "a much longer transcript", string(data))
+}
+
+func TestCommitterCommitRestoresPreviousClipboardAfterPaste(t *testing.T) {
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+	require.NoError(t, os.WriteFile(clipboardPath, []byte("previous contents\n\n"), 0o600))
+	pasteMarker := filepath.Join(t.TempDir(), "pasted.txt")
+
+	cfg := config.Default()
+	cfg.Clipboard = config.CommandConfig{Argv: []string{writeStdinCaptureScript(t), clipboardPath}}
+	cfg.ClipboardReadCmd = config.CommandConfig{Argv: []string{"cat", clipboardPath}}
+	cfg.ClipboardRestoreCmd = config.CommandConfig{Argv: []string{writeStdinCaptureScript(t), clipboardPath}}
+	cfg.PasteCmd = config.CommandConfig{Argv: []string{writeClipboardSnapshotScript(t, clipboardPath), pasteMarker}}
+	cfg.Output.RestoreClipboardMS = 1
+
+	committer := NewCommitter(cfg, nil)
+	require.NoError(t, committer.Commit(context.Background(), "captured transcript"))
+	committer.Wait()
+
+	pasted, err := os.ReadFile(pasteMarker)
+	require.NoError(t, err)
+	require.Equal(t, "captured transcript", string(pasted))
+
+	data, err := os.ReadFile(clipboardPath)
+	require.NoError(t, err)
+	require.Equal(t, "previous contents\n\n", string(data))
+}
+
+func TestCommitterCommitDoesNotWaitForClipboardRestore(t *testing.T) {
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+	require.NoError(t, os.WriteFile(clipboardPath, []byte("previous contents"), 0o600))
+
+	cfg := config.Default()
+	cfg.Clipboard = config.CommandConfig{Argv: []string{writeStdinCaptureScript(t), clipboardPath}}
+	cfg.ClipboardReadCmd = config.CommandConfig{Argv: []string{"cat", clipboardPath}}
+	cfg.ClipboardRestoreCmd = config.CommandConfig{Argv: []string{writeStdinCaptureScript(t), clipboardPath}}
+	cfg.PasteCmd = config.CommandConfig{Argv: []string{"true"}}
+	cfg.Output.RestoreClipboardMS = 60_000
+
+	committer := NewCommitter(cfg, nil)
+	started := time.Now()
+	require.NoError(t, committer.Commit(context.Background(), "captured transcript"))
+	require.Less(t, time.Since(started), 30*time.Second)
+
+	data, err := os.ReadFile(clipboardPath)
+	require.NoError(t, err)
+	require.Equal(t, "captured transcript", string(data))
+}
+
+func TestCommitterCommitKeepsTranscriptWhenRestoreNotApplicable(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*config.Config)
+		commit func(*Committer) error
+	}{
+		{
+			name:   "clipboard only",
+			commit: func(c *Committer) error { return c.CommitClipboardOnly(context.Background(), "captured transcript") },
+		},
+		{
+			name: "paste failure",
+			mutate: func(cfg *config.Config) {
+				cfg.PasteCmd = config.CommandConfig{Argv: []string{writeFailScript(t, "paste failed")}}
+			},
+			commit: func(c *Committer) error { return c.Commit(context.Background(), "captured transcript") },
+		},
+		{
+			name: "clipboard read failure",
+			mutate: func(cfg *config.Config) {
+				cfg.ClipboardReadCmd = config.CommandConfig{Argv: []string{writeFailScript(t, "nothing is copied")}}
+			},
+			commit: func(c *Committer) error { return c.Commit(context.Background(), "captured transcript") },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+			require.NoError(t, os.WriteFile(clipboardPath, []byte("previous contents"), 0o600))
+
+			cfg := config.Default()
+			cfg.Clipboard = config.CommandConfig{Argv: []string{writeStdinCaptureScript(t), clipboardPath}}
+			cfg.ClipboardReadCmd = config.CommandConfig{Argv: []string{"cat", clipboardPath}}
+			cfg.ClipboardRestoreCmd = config.CommandConfig{Argv: []string{writeStdinCaptureScript(t), clipboardPath}}
+			cfg.PasteCmd = config.CommandConfig{Argv: []string{"true"}}
+			cfg.Output.RestoreClipboardMS = 1
+			if tc.mutate != nil {
+				tc.mutate(&cfg)
+			}
+
+			committer := NewCommitter(cfg, nil)
+			require.NoError(t, tc.commit(committer))
+			committer.Wait()
+
+			data, err := os.ReadFile(clipboardPath)
+			require.NoError(t, err)
+			require.Equal(t, "captured transcript", string(data))
+		})
+	}
+}
+
+func TestCommitterCommitSkipsClipboardWhenDisabled(t *testing.T) {
+	clipboardScript := writeStdinCaptureScript(t)
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+	pasteScript := writeStdinCaptureScript(t)
+	pasteMarker := filepath.Join(t.TempDir(), "pasted.txt")
+
+	cfg := config.Default()
+	cfg.Output.ClipboardEnable = false
+	cfg.Clipboard = config.CommandConfig{Argv: []string{clipboardScript, clipboardPath}}
+	cfg.Paste.Enable = true
+	cfg.PasteCmd = config.CommandConfig{Argv: []string{pasteScript, pasteMarker}}
+
+	committer := NewCommitter(cfg, nil)
+	require.NoError(t, committer.Commit(context.Background(), "captured transcript"))
+
+	_, statErr := os.Stat(clipboardPath)
+	require.True(t, os.IsNotExist(statErr))
+	pasted, err := os.ReadFile(pasteMarker)
+	require.NoError(t, err)
+	require.Equal(t, "captured transcript", string(pasted))
+}
+
+func TestCommitterCommitSkipsShortcutPasteWhenClipboardDisabled(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := config.Default()
+	cfg.Output.ClipboardEnable = false
+	cfg.Paste.Enable = true
+	cfg.PasteCmd = config.CommandConfig{}
+
+	committer := NewCommitter(cfg, slog.New(slog.NewTextHandler(&logs, nil)))
+	require.NoError(t, committer.Commit(context.Background(), "captured transcript"))
+	require.Contains(t, logs.String(), "paste skipped; output.clipboard_enable is false")
+}
+
 func TestCommitterCommitSkipsEmptyTranscript(t *testing.T) {
 	scriptPath := writeStdinCaptureScript(t)
 	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
@@ -76,6 +238,65 @@ func TestCommitterCommitReturnsErrorWhenClipboardCommandFails(t *testing.T) {
 	require.Contains(t, err.Error(), "set clipboard")
 }
 
+func TestCommitterCommitClipboardOnlySkipsPaste(t *testing.T) {
+	clipboardScript := writeStdinCaptureScript(t)
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+	pasteMarker := filepath.Join(t.TempDir(), "pasted.txt")
+	pasteScript := writeStdinCaptureScript(t)
+
+	cfg := config.Default()
+	cfg.Clipboard = config.CommandConfig{Argv: []string{clipboardScript, clipboardPath}}
+	cfg.Paste.Enable = true
+	cfg.PasteCmd = config.CommandConfig{Argv: []string{pasteScript, pasteMarker}}
+
+	committer := NewCommitter(cfg, nil)
+	require.NoError(t, committer.CommitClipboardOnly(context.Background(), "captured transcript"))
+
+	data, err := os.ReadFile(clipboardPath)
+	require.NoError(t, err)
+	require.Equal(t, "captured transcript", string(data))
+	_, statErr := os.Stat(pasteMarker)
+	require.True(t, os.IsNotExist(statErr))
+
+	require.NoError(t, committer.Commit(context.Background(), "captured transcript"))
+	_, statErr = os.Stat(pasteMarker)
+	require.NoError(t, statErr)
+}
+
+func TestCommitterCommitFallsBackToNextClipboardCommand(t *testing.T) {
+	failScript := writeFailScript(t, "wl-copy failed")
+	captureScript := writeStdinCaptureScript(t)
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+
+	cfg := config.Default()
+	cfg.Paste.Enable = false
+	cfg.Clipboard = config.CommandConfig{Argv: []string{failScript}}
+	cfg.ClipboardFallbacks = []config.CommandConfig{{Argv: []string{captureScript, clipboardPath}}}
+
+	committer := NewCommitter(cfg, nil)
+	require.NoError(t, committer.Commit(context.Background(), "captured transcript"))
+
+	data, err := os.ReadFile(clipboardPath)
+	require.NoError(t, err)
+	require.Equal(t, "captured transcript", string(data))
+}
+
+func TestCommitterCommitFailsWhenAllClipboardCommandsFail(t *testing.T) {
+	firstFail := writeFailScript(t, "wl-copy failed")
+	lastFail := writeFailScript(t, "xclip failed")
+
+	cfg := config.Default()
+	cfg.Paste.Enable = false
+	cfg.Clipboard = config.CommandConfig{Argv: []string{firstFail}}
+	cfg.ClipboardFallbacks = []config.CommandConfig{{Argv: []string{lastFail}}}
+
+	committer := NewCommitter(cfg, nil)
+	err := committer.Commit(context.Background(), "captured transcript")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "set clipboard")
+	require.Contains(t, err.Error(), lastFail)
+}
+
 func TestCommitterCommitPasteCmdFailureDoesNotFailCommit(t *testing.T) {
 	clipboardScript := writeStdinCaptureScript(t)
 	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
@@ -117,6 +338,69 @@ func TestCommitterCommitDefaultPasteFailureDoesNotFailCommit(t *testing.T) {
 	require.Equal(t, "captured transcript", string(data))
 }
 
+func TestCommitterCommitDefaultPasteHonorsWindowRetries(t *testing.T) {
+	clipboardScript := writeStdinCaptureScript(t)
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+
+	callsFile := filepath.Join(t.TempDir(), "activewindow-calls.log")
+	t.Setenv("HYPR_CALLS_FILE", callsFile)
+	installHyprctlActiveWindowFailStub(t)
+
+	cfg := config.Default()
+	cfg.Clipboard = config.CommandConfig{Argv: []string{clipboardScript, clipboardPath}}
+	cfg.Paste.Enable = true
+	cfg.PasteCmd = config.CommandConfig{}
+	cfg.Paste.WindowRetries = 3
+	cfg.Paste.WindowRetryMS = 1
+
+	committer := NewCommitter(cfg, nil)
+	require.NoError(t, committer.Commit(context.Background(), "captured transcript"))
+
+	data, err := os.ReadFile(callsFile)
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(string(data), "activewindow"))
+}
+
+func TestCommitterCommitAppliesClipboardTimeout(t *testing.T) {
+	clipboardScript := writeSlowStdinCaptureScript(t, "0.5")
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+
+	cfg := config.Default()
+	cfg.Clipboard = config.CommandConfig{Argv: []string{clipboardScript, clipboardPath}}
+	cfg.Paste.Enable = false
+
+	cfg.Output.ClipboardTimeoutMS = 100
+	err := NewCommitter(cfg, nil).Commit(context.Background(), "slow clipboard")
+	require.Error(t, err)
+	require.NoFileExists(t, clipboardPath)
+
+	cfg.Output.ClipboardTimeoutMS = 5000
+	require.NoError(t, NewCommitter(cfg, nil).Commit(context.Background(), "slow clipboard"))
+	data, readErr := os.ReadFile(clipboardPath)
+	require.NoError(t, readErr)
+	require.Equal(t, "slow clipboard", string(data))
+}
+
+func TestCommitterCommitAppliesPasteTimeout(t *testing.T) {
+	clipboardScript := writeStdinCaptureScript(t)
+	clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+	pasteScript := writeSlowStdinCaptureScript(t, "0.5")
+	pastePath := filepath.Join(t.TempDir(), "pasted.txt")
+
+	cfg := config.Default()
+	cfg.Clipboard = config.CommandConfig{Argv: []string{clipboardScript, clipboardPath}}
+	cfg.Paste.Enable = true
+	cfg.PasteCmd = config.CommandConfig{Argv: []string{pasteScript, pastePath}}
+
+	cfg.Output.PasteTimeoutMS = 100
+	require.NoError(t, NewCommitter(cfg, nil).Commit(context.Background(), "slow paste"))
+	require.NoFileExists(t, pastePath)
+
+	cfg.Output.PasteTimeoutMS = 5000
+	require.NoError(t, NewCommitter(cfg, nil).Commit(context.Background(), "slow paste"))
+	require.FileExists(t, pastePath)
+}
+
 func writeStdinCaptureScript(t *testing.T) string {
 	t.Helper()
 
@@ -130,6 +414,30 @@ cat > "$1"
 	return path
 }
 
+// writeSlowStdinCaptureScript sleeps for delay seconds before copying stdin
+// to $1, standing in for a slow clipboard manager.
+func writeSlowStdinCaptureScript(t *testing.T, delay string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "slow-capture-stdin.sh")
+	script := "#!/usr/bin/env bash\nset -euo pipefail\nsleep " + delay + "\ncat > \"$1\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	return path
+}
+
+// writeClipboardSnapshotScript copies clipboardPath to $1, recording what the
+// clipboard held at paste time.
+func writeClipboardSnapshotScript(t *testing.T, clipboardPath string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "snapshot.sh")
+	script := "#!/usr/bin/env bash\nset -euo pipefail\ncat " + "\"" + clipboardPath + "\"" + " > \"$1\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	return path
+}
+
 func writeFailScript(t *testing.T, message string) string {
 	t.Helper()
 
@@ -160,3 +468,18 @@ printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
 	require.NoError(t, os.WriteFile(path, []byte(strings.TrimSpace(script)+"\n"), 0o755))
 	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
 }
+
+func installHyprctlActiveWindowFailStub(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hyprctl")
+	script := `#!/usr/bin/env bash
+set -euo pipefail
+printf '%s\n' "$*" >> "${HYPR_CALLS_FILE}"
+echo "no active window" >&2
+exit 1
+`
+	require.NoError(t, os.WriteFile(path, []byte(strings.TrimSpace(script)+"\n"), 0o755))
+	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+}
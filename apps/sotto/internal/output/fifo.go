@@ -0,0 +1,94 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// fifoWriteTimeout bounds a FIFO write so a reader that stops draining the
+// pipe cannot hang the commit.
+const fifoWriteTimeout = 500 * time.Millisecond
+
+// errNoFIFOReader reports that nothing has the FIFO open for reading.
+var errNoFIFOReader = errors.New("no reader on fifo")
+
+// fifoLineBreaks flattens embedded line breaks so a transcript never spans
+// more than one FIFO line.
+var fifoLineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// writeFIFO sends transcript as one newline-terminated line to
+// output.fifo_path, creating the FIFO when absent. Trailing line breaks are
+// dropped and inner ones become spaces, so readers can frame on newlines. It
+// never blocks waiting for a reader: the open is non-blocking and the write has
+// a deadline.
+func (c *Committer) writeFIFO(transcript string) {
+	path := c.config.Output.FIFOPath
+	if path == "" {
+		return
+	}
+
+	err := writeFIFOLine(path, fifoLine(transcript))
+	if c.logger == nil {
+		return
+	}
+	switch {
+	case err == nil:
+		c.logger.Debug("transcript written to fifo", "path", path, "bytes", len(transcript))
+	case errors.Is(err, errNoFIFOReader):
+		c.logger.Debug("fifo has no reader; transcript not written", "path", path)
+	default:
+		c.logger.Warn("fifo write failed", "path", path, "error", err.Error())
+	}
+}
+
+// fifoLine flattens transcript onto a single line.
+func fifoLine(transcript string) string {
+	return fifoLineBreaks.Replace(strings.TrimRight(transcript, "\r\n"))
+}
+
+func writeFIFOLine(path string, line string) error {
+	if err := ensureFIFO(path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		if errors.Is(err, syscall.ENXIO) {
+			return errNoFIFOReader
+		}
+		return fmt.Errorf("open fifo %q: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := f.SetWriteDeadline(time.Now().Add(fifoWriteTimeout)); err != nil {
+		return fmt.Errorf("set fifo write deadline: %w", err)
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("write fifo %q: %w", path, err)
+	}
+	return nil
+}
+
+// ensureFIFO creates path as a FIFO when it does not exist and rejects an
+// existing path that is not one.
+func ensureFIFO(path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		if info.Mode()&os.ModeNamedPipe == 0 {
+			return fmt.Errorf("%q exists and is not a fifo", path)
+		}
+		return nil
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("stat fifo %q: %w", path, err)
+	}
+
+	if err := syscall.Mkfifo(path, 0o600); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("create fifo %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbright/sotto/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func fifoOnlyConfig(path string) config.Config {
+	cfg := config.Default()
+	cfg.Output.ClipboardEnable = false
+	cfg.Paste.Enable = false
+	cfg.Output.FIFOPath = path
+	return cfg
+}
+
+func TestCommitterCommitWritesTranscriptToFIFOReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sotto.fifo")
+	require.NoError(t, ensureFIFO(path))
+
+	// O_RDWR keeps the pipe open between commits, like a long-running reader.
+	reader, err := os.OpenFile(path, os.O_RDWR, 0)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	lines := make(chan string, 3)
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	committer := NewCommitter(fifoOnlyConfig(path), nil)
+	require.NoError(t, committer.Commit(context.Background(), "first transcript"))
+	require.NoError(t, committer.CommitClipboardOnly(context.Background(), "second transcript"))
+	require.NoError(t, committer.Commit(context.Background(), "third\ntranscript\r\nspans lines\n"))
+
+	for _, want := range []string{"first transcript", "second transcript", "third transcript spans lines"} {
+		select {
+		case got := <-lines:
+			require.Equal(t, want, got)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+}
+
+func TestFIFOLineFlattensLineBreaks(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		want       string
+	}{
+		{name: "single line", transcript: "hello world", want: "hello world"},
+		{name: "trailing newline", transcript: "hello world\n", want: "hello world"},
+		{name: "inner newlines", transcript: "hello\nworld\r\nagain\rdone", want: "hello world again done"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, fifoLine(tc.transcript))
+		})
+	}
+}
+
+func TestCommitterCommitCreatesFIFOAndSkipsWithoutReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sotto.fifo")
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	started := time.Now()
+	require.NoError(t, NewCommitter(fifoOnlyConfig(path), logger).Commit(context.Background(), "nobody listening"))
+	require.Less(t, time.Since(started), time.Second)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NotZero(t, info.Mode()&os.ModeNamedPipe)
+	require.Contains(t, logs.String(), "fifo has no reader")
+}
+
+func TestCommitterCommitRejectsNonFIFOPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regular.txt")
+	require.NoError(t, os.WriteFile(path, []byte("keep"), 0o600))
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	require.NoError(t, NewCommitter(fifoOnlyConfig(path), logger).Commit(context.Background(), "transcript"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "keep", string(data))
+	require.Contains(t, logs.String(), "is not a fifo")
+}
+
+func TestWriteFIFOLineTimesOutWhenReaderStalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sotto.fifo")
+	require.NoError(t, ensureFIFO(path))
+
+	// The reader holds the pipe open but never drains it, so a payload larger
+	// than the pipe buffer cannot complete.
+	reader, err := os.OpenFile(path, os.O_RDWR, 0)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	started := time.Now()
+	err = writeFIFOLine(path, strings.Repeat("x", 1<<20))
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	require.Less(t, time.Since(started), 2*time.Second)
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// historyMaxBytes bounds the history log: an append that finds it this large
+// first rotates it to <path>.1, replacing any older rotation.
+const historyMaxBytes = 1 << 20
+
+// HistoryEntry is one JSON line of the transcript history log.
+type HistoryEntry struct {
+	Time       time.Time `json:"time"`
+	Transcript string    `json:"transcript"`
+}
+
+// SetHistoryPath appends each committed transcript to path as one JSON line,
+// which `sotto last` reads when no owner is running. Empty, the default,
+// disables it; the owner sets it only when output.history is on.
+func (c *Committer) SetHistoryPath(path string) {
+	c.historyPath = path
+}
+
+// appendHistory records transcript in the history log. Failures are logged and
+// never fail the commit.
+func (c *Committer) appendHistory(transcript string) {
+	if c.historyPath == "" {
+		return
+	}
+	err := appendHistoryEntry(c.historyPath, HistoryEntry{Time: time.Now(), Transcript: transcript})
+	if err != nil && c.logger != nil {
+		c.logger.Warn("transcript history write failed", "path", c.historyPath, "error", err.Error())
+	}
+}
+
+func appendHistoryEntry(path string, entry HistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode history entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create history dir: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= historyMaxBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("rotate history log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open history log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write history log: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rbright/sotto/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitterCommitAppendsHistory(t *testing.T) {
+	cfg := config.Default()
+	cfg.Paste.Enable = false
+	cfg.Clipboard = config.CommandConfig{Argv: []string{writeStdinCaptureScript(t), filepath.Join(t.TempDir(), "clipboard.txt")}}
+	historyPath := filepath.Join(t.TempDir(), "sotto", "history.jsonl")
+
+	committer := NewCommitter(cfg, nil)
+	committer.SetHistoryPath(historyPath)
+	require.NoError(t, committer.Commit(context.Background(), "first"))
+	require.NoError(t, committer.CommitClipboardOnly(context.Background(), "second\nline"))
+
+	data, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var entry HistoryEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	require.Equal(t, "second\nline", entry.Transcript)
+	require.False(t, entry.Time.IsZero())
+
+	stat, err := os.Stat(historyPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+}
+
+func TestCommitterCommitSkipsHistoryOnClipboardFailure(t *testing.T) {
+	cfg := config.Default()
+	cfg.Paste.Enable = false
+	cfg.Clipboard = config.CommandConfig{Argv: []string{"false"}}
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	committer := NewCommitter(cfg, nil)
+	committer.SetHistoryPath(historyPath)
+	require.Error(t, committer.Commit(context.Background(), "lost"))
+	require.NoFileExists(t, historyPath)
+}
+
+func TestAppendHistoryEntryRotatesPastLimit(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, os.WriteFile(historyPath, make([]byte, historyMaxBytes), 0o600))
+
+	require.NoError(t, appendHistoryEntry(historyPath, HistoryEntry{Transcript: "fresh"}))
+
+	rotated, err := os.Stat(historyPath + ".1")
+	require.NoError(t, err)
+	require.EqualValues(t, historyMaxBytes, rotated.Size())
+	data, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"transcript":"fresh"`)
+}
@@ -6,36 +6,105 @@ import (
 	"strings"
 	"time"
 
+	"github.com/rbright/sotto/internal/config"
 	"github.com/rbright/sotto/internal/hypr"
 )
 
-// defaultPaste dispatches a sendshortcut payload to the current active window.
-func defaultPaste(ctx context.Context, shortcut string) error {
-	window, err := activeWindowWithRetry(ctx, 5, 10*time.Millisecond)
+// defaultPaste dispatches a sendshortcut payload, rendered from format, to
+// window.
+func defaultPaste(ctx context.Context, shortcut string, format string, window hypr.ActiveWindow) error {
+	payload, err := buildPasteShortcut(format, shortcut, window)
 	if err != nil {
 		return err
 	}
+	return hypr.SendShortcut(ctx, payload)
+}
 
-	payload, err := buildPasteShortcut(shortcut, strings.TrimSpace(window.Address))
-	if err != nil {
-		return err
+// pasteWindow resolves the active window once per commit when the class lists
+// or the shortcut paste need it, so both act on the same window. It returns
+// the zero window when neither does.
+func (c *Committer) pasteWindow(ctx context.Context) (hypr.ActiveWindow, error) {
+	if !c.hasPasteClassLists() && len(c.config.PasteCmd.Argv) > 0 {
+		return hypr.ActiveWindow{}, nil
 	}
-	return hypr.SendShortcut(ctx, payload)
+
+	// Extend the lookup budget by the configured window-retry wait so slow
+	// compositors are not cut off by the timeout.
+	retries := c.config.Paste.WindowRetries
+	retryDelay := time.Duration(c.config.Paste.WindowRetryMS) * time.Millisecond
+	lookupCtx, lookupCancel := context.WithTimeout(ctx, c.pasteTimeout(1200*time.Millisecond)+time.Duration(retries)*retryDelay)
+	defer lookupCancel()
+	return activeWindowWithRetry(lookupCtx, retries, retryDelay)
+}
+
+// hasPasteClassLists reports whether paste.allowed_classes or
+// paste.denied_classes is set.
+func (c *Committer) hasPasteClassLists() bool {
+	return len(c.config.Paste.AllowedClasses) > 0 || len(c.config.Paste.DeniedClasses) > 0
+}
+
+// pasteBlockReason explains why paste.allowed_classes or paste.denied_classes
+// rule out window, or returns "" when paste may proceed. With either list
+// set, a window that could not be resolved (windowErr) also blocks paste.
+func (c *Committer) pasteBlockReason(window hypr.ActiveWindow, windowErr error) string {
+	if !c.hasPasteClassLists() {
+		return ""
+	}
+	if windowErr != nil {
+		return windowErr.Error()
+	}
+	return windowClassBlockReason(window, c.config.Paste.AllowedClasses, c.config.Paste.DeniedClasses)
 }
 
-// buildPasteShortcut renders `<shortcut>,address:<window>` payload format.
-func buildPasteShortcut(shortcut string, windowAddress string) (string, error) {
+// windowClassBlockReason applies the class lists to window; denied entries win
+// over allowed ones.
+func windowClassBlockReason(window hypr.ActiveWindow, allowed []string, denied []string) string {
+	if matchesWindowClass(window, denied) {
+		return fmt.Sprintf("window class %q is in paste.denied_classes", window.Class)
+	}
+	if len(allowed) > 0 && !matchesWindowClass(window, allowed) {
+		return fmt.Sprintf("window class %q is not in paste.allowed_classes", window.Class)
+	}
+	return ""
+}
+
+// matchesWindowClass reports whether the window's class or initial class
+// equals one of classes, ignoring case.
+func matchesWindowClass(window hypr.ActiveWindow, classes []string) bool {
+	for _, class := range classes {
+		if window.Class != "" && strings.EqualFold(window.Class, class) {
+			return true
+		}
+		if window.InitialClass != "" && strings.EqualFold(window.InitialClass, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPasteShortcut renders paste.shortcut_format, substituting {shortcut},
+// {address}, and {class}. An empty format uses the Hyprland
+// `<shortcut>,address:<window>` payload. Window fields are required only when
+// the format references them.
+func buildPasteShortcut(format string, shortcut string, window hypr.ActiveWindow) (string, error) {
 	shortcut = strings.TrimSpace(shortcut)
 	if shortcut == "" {
 		return "", fmt.Errorf("paste shortcut cannot be empty")
 	}
+	if strings.TrimSpace(format) == "" {
+		format = config.DefaultPasteShortcutFormat
+	}
 
-	address := strings.TrimSpace(windowAddress)
-	if address == "" {
+	address := strings.TrimSpace(window.Address)
+	if address == "" && strings.Contains(format, "{address}") {
 		return "", fmt.Errorf("active window address is required")
 	}
+	class := strings.TrimSpace(window.Class)
+	if class == "" && strings.Contains(format, "{class}") {
+		return "", fmt.Errorf("active window class is required")
+	}
 
-	return fmt.Sprintf("%s,address:%s", shortcut, address), nil
+	return strings.NewReplacer("{shortcut}", shortcut, "{address}", address, "{class}", class).Replace(format), nil
 }
 
 // activeWindowWithRetry retries active-window lookup within short bounded delays.
@@ -1,50 +1,83 @@
 package output
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/hypr"
 	"github.com/stretchr/testify/require"
 )
 
 func TestBuildPasteShortcut(t *testing.T) {
 	t.Parallel()
 
-	t.Run("builds payload", func(t *testing.T) {
-		got, err := buildPasteShortcut("SUPER,V", "0xabc")
+	window := hypr.ActiveWindow{Address: "0xabc", Class: "ghostty"}
+
+	t.Run("builds hyprland default payload", func(t *testing.T) {
+		got, err := buildPasteShortcut(config.DefaultPasteShortcutFormat, "SUPER,V", window)
+		require.NoError(t, err)
+		require.Equal(t, "SUPER,V,address:0xabc", got)
+
+		got, err = buildPasteShortcut("", "SUPER,V", window)
 		require.NoError(t, err)
 		require.Equal(t, "SUPER,V,address:0xabc", got)
 	})
 
+	t.Run("builds custom format payload", func(t *testing.T) {
+		got, err := buildPasteShortcut("{shortcut},class:{class}", "CTRL SHIFT,V", window)
+		require.NoError(t, err)
+		require.Equal(t, "CTRL SHIFT,V,class:ghostty", got)
+
+		got, err = buildPasteShortcut("{shortcut},class:{class}", "CTRL,V", hypr.ActiveWindow{Class: "ghostty"})
+		require.NoError(t, err)
+		require.Equal(t, "CTRL,V,class:ghostty", got)
+	})
+
 	t.Run("rejects empty shortcut", func(t *testing.T) {
-		_, err := buildPasteShortcut("", "0xabc")
+		_, err := buildPasteShortcut(config.DefaultPasteShortcutFormat, "", window)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "shortcut")
 	})
 
 	t.Run("rejects empty address", func(t *testing.T) {
-		_, err := buildPasteShortcut("CTRL,V", "")
+		_, err := buildPasteShortcut(config.DefaultPasteShortcutFormat, "CTRL,V", hypr.ActiveWindow{Class: "ghostty"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "address")
 	})
+
+	t.Run("rejects empty class", func(t *testing.T) {
+		_, err := buildPasteShortcut("{shortcut},class:{class}", "CTRL,V", hypr.ActiveWindow{Address: "0xabc"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "class")
+	})
 }
 
 func TestDefaultPasteDispatchesShortcut(t *testing.T) {
 	argsFile := filepath.Join(t.TempDir(), "hypr-args.log")
 	t.Setenv("HYPR_ARGS_FILE", argsFile)
-	t.Setenv("HYPR_ACTIVEWINDOW_JSON", `{"address":"0xabc","class":"ghostty","initialClass":"ghostty"}`)
 	installHyprctlPasteStub(t)
+	window := hypr.ActiveWindow{Address: "0xabc", Class: "ghostty", InitialClass: "ghostty"}
 
-	err := defaultPaste(context.Background(), "SUPER,V")
+	err := defaultPaste(context.Background(), "SUPER,V", config.DefaultPasteShortcutFormat, window)
 	require.NoError(t, err)
 
 	data, err := os.ReadFile(argsFile)
 	require.NoError(t, err)
 	require.Contains(t, string(data), "--quiet dispatch sendshortcut SUPER,V,address:0xabc")
+
+	err = defaultPaste(context.Background(), "SUPER,V", "{shortcut},class:{class}", window)
+	require.NoError(t, err)
+
+	data, err = os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "--quiet dispatch sendshortcut SUPER,V,class:ghostty")
 }
 
 func TestActiveWindowWithRetryHonorsContextCancel(t *testing.T) {
@@ -58,15 +91,90 @@ func TestActiveWindowWithRetryHonorsContextCancel(t *testing.T) {
 	require.ErrorIs(t, err, context.Canceled)
 }
 
-func TestDefaultPasteFailsWhenActiveWindowAddressMissing(t *testing.T) {
+func TestPasteWindowFailsWhenActiveWindowAddressMissing(t *testing.T) {
 	t.Setenv("HYPR_ACTIVEWINDOW_JSON", `{"address":"","class":"brave-browser"}`)
 	installHyprctlPasteStub(t)
 
-	err := defaultPaste(context.Background(), "CTRL,V")
+	_, err := NewCommitter(config.Default(), nil).pasteWindow(context.Background())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "empty address")
 }
 
+func TestWindowClassBlockReason(t *testing.T) {
+	t.Parallel()
+
+	window := hypr.ActiveWindow{Class: "org.keepassxc.KeePassXC", InitialClass: "KeePassXC"}
+	tests := []struct {
+		name    string
+		allowed []string
+		denied  []string
+		want    string
+	}{
+		{name: "no lists", want: ""},
+		{name: "denied by initial class", denied: []string{"keepassxc"}, want: "is in paste.denied_classes"},
+		{name: "allowed by class", allowed: []string{"ghostty", "ORG.KEEPASSXC.KEEPASSXC"}, want: ""},
+		{name: "missing from allowlist", allowed: []string{"ghostty"}, want: "is not in paste.allowed_classes"},
+		{name: "deny wins over allow", allowed: []string{"KeePassXC"}, denied: []string{"org.keepassxc.KeePassXC"}, want: "is in paste.denied_classes"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := windowClassBlockReason(window, tc.allowed, tc.denied)
+			if tc.want == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Contains(t, got, tc.want)
+		})
+	}
+}
+
+func TestCommitterCommitHonorsPasteClassLists(t *testing.T) {
+	tests := []struct {
+		name       string
+		windowJSON string
+		allowed    []string
+		denied     []string
+		wantPaste  bool
+	}{
+		{name: "allowed class pastes", windowJSON: `{"address":"0xabc","class":"ghostty","initialClass":"ghostty"}`, allowed: []string{"ghostty"}, wantPaste: true},
+		{name: "denied class skips paste", windowJSON: `{"address":"0xabc","class":"KeePassXC","initialClass":"KeePassXC"}`, denied: []string{"keepassxc"}},
+		{name: "class outside allowlist skips paste", windowJSON: `{"address":"0xabc","class":"brave-browser","initialClass":"brave-browser"}`, allowed: []string{"ghostty"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			argsFile := filepath.Join(t.TempDir(), "hypr-args.log")
+			t.Setenv("HYPR_ARGS_FILE", argsFile)
+			t.Setenv("HYPR_ACTIVEWINDOW_JSON", tc.windowJSON)
+			installHyprctlPasteStub(t)
+
+			clipboardScript := writeStdinCaptureScript(t)
+			clipboardPath := filepath.Join(t.TempDir(), "clipboard.txt")
+			cfg := config.Default()
+			cfg.Clipboard = config.CommandConfig{Argv: []string{clipboardScript, clipboardPath}}
+			cfg.Paste.AllowedClasses = tc.allowed
+			cfg.Paste.DeniedClasses = tc.denied
+
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+			require.NoError(t, NewCommitter(cfg, logger).Commit(context.Background(), "secret transcript"))
+
+			data, err := os.ReadFile(clipboardPath)
+			require.NoError(t, err)
+			require.Equal(t, "secret transcript", string(data))
+
+			args, _ := os.ReadFile(argsFile)
+			require.Equal(t, 1, strings.Count(string(args), "-j activewindow"), "class check and paste must share one lookup")
+			if tc.wantPaste {
+				require.Contains(t, string(args), "sendshortcut CTRL,V,address:0xabc")
+				return
+			}
+			require.NotContains(t, string(args), "sendshortcut")
+			require.Contains(t, logs.String(), "paste skipped for active window")
+		})
+	}
+}
+
 func installHyprctlPasteStub(t *testing.T) {
 	t.Helper()
 
@@ -75,6 +183,9 @@ func installHyprctlPasteStub(t *testing.T) {
 	script := `#!/usr/bin/env bash
 set -euo pipefail
 if [[ "${1:-}" == "-j" && "${2:-}" == "activewindow" ]]; then
+  if [[ -n "${HYPR_ARGS_FILE:-}" ]]; then
+    printf '%s\n' "$*" >> "${HYPR_ARGS_FILE}"
+  fi
   if [[ -n "${HYPR_ACTIVEWINDOW_JSON:-}" ]]; then
     echo "${HYPR_ACTIVEWINDOW_JSON}"
   else
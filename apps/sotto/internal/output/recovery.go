@@ -0,0 +1,36 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rbright/sotto/internal/config"
+)
+
+// Recover saves transcript text under state/sotto/recovery after a failed
+// commit so the user can retrieve it. It returns an empty path when
+// output.recover_on_failure is disabled or the transcript is empty.
+func (c *Committer) Recover(_ context.Context, transcript string) (string, error) {
+	if !c.config.Output.RecoverOnFailure || transcript == "" {
+		return "", nil
+	}
+
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	recoveryDir := filepath.Join(stateDir, "recovery")
+	if err := os.MkdirAll(recoveryDir, 0o700); err != nil {
+		return "", fmt.Errorf("create recovery dir: %w", err)
+	}
+
+	timestamp := time.Now().Format("20060102-150405.000")
+	path := filepath.Join(recoveryDir, fmt.Sprintf("transcript-%s.txt", timestamp))
+	if err := os.WriteFile(path, []byte(transcript), 0o600); err != nil {
+		return "", fmt.Errorf("write recovery file %q: %w", path, err)
+	}
+	return path, nil
+}
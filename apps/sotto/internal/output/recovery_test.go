@@ -0,0 +1,39 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rbright/sotto/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitterRecoverWritesTranscriptUnderStateDir(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	committer := NewCommitter(config.Default(), nil)
+	path, err := committer.Recover(context.Background(), "lost words")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(stateDir, "sotto", "recovery"), filepath.Dir(path))
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "lost words", string(saved))
+}
+
+func TestCommitterRecoverSkipsWhenDisabled(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	cfg := config.Default()
+	cfg.Output.RecoverOnFailure = false
+	path, err := NewCommitter(cfg, nil).Recover(context.Background(), "lost words")
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	_, statErr := os.Stat(filepath.Join(stateDir, "sotto", "recovery"))
+	require.True(t, os.IsNotExist(statErr))
+}
@@ -0,0 +1,176 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rbright/sotto/internal/audio"
+	"github.com/rbright/sotto/internal/riva"
+)
+
+// benchChunkBytes is one 100ms slice of 16 kHz mono s16 audio.
+const benchChunkBytes = 3200
+
+// BenchOptions selects the audio a benchmark sends: PCM from a WAV file
+// (FileName labels it) or Duration of live capture from the configured device.
+type BenchOptions struct {
+	PCM      []byte
+	FileName string
+	Duration time.Duration
+}
+
+// BenchResult is the latency breakdown of one benchmark run. Dial,
+// FirstPartial, Final, and Total are offsets from the start of the run, so
+// they are ordered; FirstPartial is zero when Riva returned no hypothesis.
+type BenchResult struct {
+	Source       string
+	Audio        time.Duration
+	Dial         time.Duration
+	FirstPartial time.Duration
+	Final        time.Duration
+	Total        time.Duration
+	Transcript   string
+}
+
+// firstResultReporter is implemented by streams that timestamp their first
+// hypothesis (riva.Stream).
+type firstResultReporter interface {
+	FirstResultAt() time.Time
+}
+
+// Bench runs one capture -> stream -> assemble pass like a dictation session
+// and reports where the time went. File audio is sent at real-time pace so
+// Riva sees the same request shape as live capture. Nothing is committed.
+func (t *Transcriber) Bench(ctx context.Context, opts BenchOptions) (BenchResult, error) {
+	var result BenchResult
+	began := time.Now()
+
+	src := t.sources()
+	var stream streamClient
+	dialStream := src.dialStream
+	src.dialStream = func(ctx context.Context, cfg riva.StreamConfig) (streamClient, error) {
+		s, err := dialStream(ctx, cfg)
+		result.Dial = time.Since(began)
+		stream = s
+		return s, err
+	}
+
+	var file *fileCapture
+	if opts.PCM != nil {
+		src.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+			return audio.Selection{Device: audio.Device{ID: "file", Description: filepath.Base(opts.FileName)}}, nil
+		}
+		src.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+			file = newFileCapture(opts.PCM, benchChunkBytes, 100*time.Millisecond)
+			return file, nil
+		}
+	}
+
+	if err := t.start(ctx, src); err != nil {
+		return result, err
+	}
+
+	var fileDone <-chan struct{}
+	var liveDone <-chan time.Time
+	if file != nil {
+		fileDone = file.done
+	} else {
+		timer := time.NewTimer(opts.Duration)
+		defer timer.Stop()
+		liveDone = timer.C
+	}
+	select {
+	case <-fileDone:
+	case <-liveDone:
+	case <-ctx.Done():
+		_ = t.Cancel(context.Background())
+		return result, ctx.Err()
+	}
+
+	audioEnd := time.Since(began)
+	stop, err := t.StopAndTranscribe(ctx)
+	result.Total = time.Since(began)
+	result.Source = stop.AudioDevice
+	result.Audio = time.Duration(stop.BytesCaptured) * time.Second / 32000
+	if err != nil {
+		return result, err
+	}
+	result.Final = audioEnd + stop.GRPCLatency
+	result.Transcript = stop.Transcript
+	if reporter, ok := stream.(firstResultReporter); ok {
+		if at := reporter.FirstResultAt(); !at.IsZero() {
+			result.FirstPartial = at.Sub(began)
+		}
+	}
+	return result, nil
+}
+
+// fileCapture replays PCM as a captureClient, one chunk per interval.
+type fileCapture struct {
+	pcm    []byte
+	chunks chan []byte
+	done   chan struct{}
+	stop   chan struct{}
+
+	mu       sync.Mutex
+	sent     int
+	stopOnce sync.Once
+}
+
+func newFileCapture(pcm []byte, chunkBytes int, interval time.Duration) *fileCapture {
+	c := &fileCapture{
+		pcm:    pcm,
+		chunks: make(chan []byte, 1),
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+	go c.run(chunkBytes, interval)
+	return c
+}
+
+func (c *fileCapture) run(chunkBytes int, interval time.Duration) {
+	defer close(c.chunks)
+	defer close(c.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for offset := 0; offset < len(c.pcm); offset += chunkBytes {
+		end := min(offset+chunkBytes, len(c.pcm))
+		select {
+		case c.chunks <- c.pcm[offset:end]:
+		case <-c.stop:
+			return
+		}
+		c.mu.Lock()
+		c.sent = end
+		c.mu.Unlock()
+		select {
+		case <-ticker.C:
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *fileCapture) Stop() error {
+	c.stopOnce.Do(func() { close(c.stop) })
+	return nil
+}
+
+func (c *fileCapture) Chunks() <-chan []byte { return c.chunks }
+
+func (c *fileCapture) BytesCaptured() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int64(c.sent)
+}
+
+func (c *fileCapture) RawPCM() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pcm[:c.sent]
+}
+
+func (c *fileCapture) Err() error { return nil }
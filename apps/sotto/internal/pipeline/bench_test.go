@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rbright/sotto/internal/config"
+	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// benchRivaServer answers the first audio chunk with an interim and the end
+// of audio with a final, like a live Riva stream.
+type benchRivaServer struct {
+	asrpb.UnimplementedRivaSpeechRecognitionServer
+}
+
+func (benchRivaServer) StreamingRecognize(stream grpc.BidiStreamingServer[asrpb.StreamingRecognizeRequest, asrpb.StreamingRecognizeResponse]) error {
+	sentInterim := false
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(req.GetAudioContent()) > 0 && !sentInterim {
+			sentInterim = true
+			if err := stream.Send(benchResponse("hello", false)); err != nil {
+				return err
+			}
+		}
+	}
+	return stream.Send(benchResponse("hello world", true))
+}
+
+func benchResponse(text string, final bool) *asrpb.StreamingRecognizeResponse {
+	return &asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			IsFinal:      final,
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+		}},
+	}
+}
+
+func TestBenchFileReportsOrderedLatencies(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	asrpb.RegisterRivaSpeechRecognitionServer(server, benchRivaServer{})
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	cfg := config.Default()
+	cfg.RivaGRPC = lis.Addr().String()
+	cfg.Transcript.TrailingSpace = false
+	transcriber := NewTranscriber(cfg, nil)
+
+	pcm := make([]byte, 3*benchChunkBytes) // 300ms of silence
+	result, err := transcriber.Bench(context.Background(), BenchOptions{PCM: pcm, FileName: "/tmp/sample.wav"})
+	require.NoError(t, err)
+
+	require.Equal(t, "sample.wav (file)", result.Source)
+	require.Equal(t, 300*time.Millisecond, result.Audio)
+	require.Equal(t, "Hello world", result.Transcript)
+	require.Positive(t, result.Dial)
+	require.Greater(t, result.FirstPartial, result.Dial)
+	require.GreaterOrEqual(t, result.Final, result.FirstPartial)
+	require.GreaterOrEqual(t, result.Total, result.Final)
+}
+
+func TestFileCaptureStopEndsReplay(t *testing.T) {
+	capture := newFileCapture(make([]byte, 10*benchChunkBytes), benchChunkBytes, time.Hour)
+	<-capture.Chunks()
+	require.NoError(t, capture.Stop())
+	require.NoError(t, capture.Stop())
+
+	for range capture.Chunks() {
+	}
+	<-capture.done
+	require.Equal(t, int64(benchChunkBytes), capture.BytesCaptured())
+	require.Len(t, capture.RawPCM(), benchChunkBytes)
+}
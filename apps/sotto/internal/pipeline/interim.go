@@ -0,0 +1,85 @@
+package pipeline
+
+import "time"
+
+// interimUpdateInterval rate-limits live interim updates so the indicator is
+// not flooded with one notification per recognition response.
+const interimUpdateInterval = 500 * time.Millisecond
+
+// interimSource is the part of a stream that exposes live recognition text.
+type interimSource interface {
+	InterimSnapshot() string
+}
+
+// SetInterimSink registers fn to receive the live transcript while recording
+// (indicator.show_interim). It must be called before Start; fn runs on a
+// polling goroutine at most once per interimUpdateInterval, and only when the
+// text changed.
+func (t *Transcriber) SetInterimSink(fn func(string)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interimSink = fn
+}
+
+// Progress reports the live interim transcript and the bytes captured so far,
+// for `sotto monitor`. Both are empty when no session is running.
+func (t *Transcriber) Progress() (string, int64) {
+	t.mu.Lock()
+	started := t.started
+	capture := t.capture
+	stream := t.stream
+	t.mu.Unlock()
+
+	if !started || capture == nil || stream == nil {
+		return "", 0
+	}
+	return stream.InterimSnapshot(), capture.BytesCaptured()
+}
+
+// stopInterim ends the interim polling goroutine, if any, and waits for it to
+// return so no sink call is still in flight afterwards.
+func (t *Transcriber) stopInterim() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopInterimLocked()
+}
+
+// stopInterimLocked is stopInterim for callers holding t.mu. The poller never
+// takes t.mu, so waiting here cannot deadlock.
+func (t *Transcriber) stopInterimLocked() {
+	if t.interimStop == nil {
+		return
+	}
+	close(t.interimStop)
+	<-t.interimDone
+	t.interimStop = nil
+	t.interimDone = nil
+}
+
+// pollInterim forwards changed interim snapshots from source to sink every
+// interval until stop closes.
+func pollInterim(source interimSource, sink func(string), interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			text := source.InterimSnapshot()
+			if text == "" || text == last {
+				continue
+			}
+			// Re-check stop so a snapshot taken after stop is never delivered.
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			last = text
+			sink(text)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rbright/sotto/internal/audio"
+	"github.com/rbright/sotto/internal/riva"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollInterimForwardsOnlyChangedText(t *testing.T) {
+	stream := &fakeStream{}
+	updates := make(chan string, 8)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pollInterim(stream, func(text string) { updates <- text }, 5*time.Millisecond, stop)
+	}()
+
+	stream.setInterim("hello")
+	require.Equal(t, "hello", <-updates)
+	stream.setInterim("hello world")
+	require.Equal(t, "hello world", <-updates)
+
+	// Unchanged text is not re-sent on later ticks.
+	time.Sleep(30 * time.Millisecond)
+	require.Empty(t, updates)
+
+	close(stop)
+	<-done
+	stream.setInterim("after stop")
+	time.Sleep(20 * time.Millisecond)
+	require.Empty(t, updates)
+}
+
+func TestTranscriberProgressReportsLiveSession(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	interim, bytes := transcriber.Progress()
+	require.Empty(t, interim)
+	require.Zero(t, bytes)
+
+	stream := &fakeStream{}
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		return stream, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		chunks := make(chan []byte)
+		close(chunks)
+		return &fakeCapture{chunks: chunks, bytes: 3200}, nil
+	}
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	stream.setInterim("live words")
+	interim, bytes = transcriber.Progress()
+	require.Equal(t, "live words", interim)
+	require.Equal(t, int64(3200), bytes)
+
+	require.NoError(t, transcriber.Cancel(context.Background()))
+	interim, _ = transcriber.Progress()
+	require.Empty(t, interim)
+}
+
+func TestTranscriberStopEndsInterimUpdates(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	stream := &fakeStream{}
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		return stream, nil
+	}
+	updates := make(chan string, 8)
+	transcriber.SetInterimSink(func(text string) { updates <- text })
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	stream.setInterim("live words")
+	select {
+	case text := <-updates:
+		require.Equal(t, "live words", text)
+	case <-time.After(2 * interimUpdateInterval):
+		t.Fatal("interim text did not reach the sink")
+	}
+
+	require.NoError(t, transcriber.Cancel(context.Background()))
+	stream.setInterim("discarded")
+	time.Sleep(interimUpdateInterval + 50*time.Millisecond)
+	require.Empty(t, updates)
+}
+
+func TestStopInterimWaitsForInFlightSink(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	stream := &fakeStream{}
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		return stream, nil
+	}
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	transcriber.SetInterimSink(func(string) {
+		close(entered)
+		<-release
+		finished.Store(true)
+	})
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	stream.setInterim("live words")
+	select {
+	case <-entered:
+	case <-time.After(2 * interimUpdateInterval):
+		t.Fatal("interim text did not reach the sink")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	require.NoError(t, transcriber.Cancel(context.Background()))
+	require.True(t, finished.Load(), "Cancel returned while the sink was still running")
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// postprocessTimeout bounds one transcript.postprocess_cmd run.
+const postprocessTimeout = 10 * time.Second
+
+// postprocess rewrites an assembled transcript through
+// transcript.postprocess_cmd. The command sees the text without the configured
+// trailing space/newline, which is re-added to its output. Any failure keeps
+// the unprocessed transcript and logs a warning.
+func (t *Transcriber) postprocess(ctx context.Context, text string) string {
+	argv := t.cfg.Transcript.PostprocessCmd.Argv
+	if len(argv) == 0 || strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	suffix := ""
+	switch {
+	case t.cfg.Transcript.TrailingNewline && strings.HasSuffix(text, "\n"):
+		suffix = "\n"
+	case t.cfg.Transcript.TrailingSpace && strings.HasSuffix(text, " "):
+		suffix = " "
+	}
+
+	processed, err := runPostprocess(ctx, argv, strings.TrimSuffix(text, suffix), t.postprocessTimeout)
+	if err != nil {
+		if logger := t.sessionLogger(); logger != nil {
+			logger.Warn("transcript postprocess failed; committing unprocessed transcript", "error", err.Error())
+		}
+		return text
+	}
+	return processed + suffix
+}
+
+// runPostprocess pipes text to argv and returns its trimmed stdout. Empty
+// output is an error so a broken command cannot silently erase a transcript.
+func runPostprocess(ctx context.Context, argv []string, text string, timeout time.Duration) (string, error) {
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	// A killed command's children may hold stdout open; stop waiting for them.
+	cmd.WaitDelay = time.Second
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%s timed out after %s", argv[0], timeout)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+				return "", fmt.Errorf("run %s: %w: %s", argv[0], err, stderr)
+			}
+		}
+		return "", fmt.Errorf("run %s: %w", argv[0], err)
+	}
+
+	processed := strings.TrimSpace(string(out))
+	if processed == "" {
+		return "", fmt.Errorf("%s produced no output", argv[0])
+	}
+	return processed, nil
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/rbright/sotto/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func postprocessTranscriber(t *testing.T, argv []string, logs *bytes.Buffer) *Transcriber {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Transcript.PostprocessCmd = config.CommandConfig{Argv: argv}
+	return NewTranscriber(cfg, slog.New(slog.NewTextHandler(logs, nil)))
+}
+
+func TestPostprocessRewritesTranscript(t *testing.T) {
+	var logs bytes.Buffer
+	transcriber := postprocessTranscriber(t, []string{"sh", "-c", "tr a-z A-Z"}, &logs)
+
+	// The trailing space is held back from the command and restored after.
+	require.Equal(t, "HELLO WORLD ", transcriber.postprocess(context.Background(), "hello world "))
+	require.Empty(t, logs.String())
+}
+
+func TestPostprocessKeepsTranscriptWhenCommandFails(t *testing.T) {
+	var logs bytes.Buffer
+	transcriber := postprocessTranscriber(t, []string{"sh", "-c", "echo model offline >&2; exit 3"}, &logs)
+
+	require.Equal(t, "hello world ", transcriber.postprocess(context.Background(), "hello world "))
+	require.Contains(t, logs.String(), "transcript postprocess failed")
+	require.Contains(t, logs.String(), "model offline")
+}
+
+func TestPostprocessKeepsTranscriptOnEmptyOutputOrTimeout(t *testing.T) {
+	var logs bytes.Buffer
+	transcriber := postprocessTranscriber(t, []string{"sh", "-c", "cat >/dev/null"}, &logs)
+	require.Equal(t, "hello ", transcriber.postprocess(context.Background(), "hello "))
+	require.Contains(t, logs.String(), "produced no output")
+
+	logs.Reset()
+	transcriber = postprocessTranscriber(t, []string{"sleep", "5"}, &logs)
+	transcriber.postprocessTimeout = 50 * time.Millisecond
+	start := time.Now()
+	require.Equal(t, "hello ", transcriber.postprocess(context.Background(), "hello "))
+	require.Less(t, time.Since(start), 3*time.Second)
+	require.Contains(t, logs.String(), "timed out")
+}
+
+func TestPostprocessSkipsWhenUnsetOrEmpty(t *testing.T) {
+	var logs bytes.Buffer
+	transcriber := postprocessTranscriber(t, nil, &logs)
+	require.Equal(t, "hello ", transcriber.postprocess(context.Background(), "hello "))
+
+	transcriber = postprocessTranscriber(t, []string{"sh", "-c", "exit 1"}, &logs)
+	require.Equal(t, "", transcriber.postprocess(context.Background(), ""))
+	require.Empty(t, logs.String())
+}
@@ -0,0 +1,135 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rbright/sotto/internal/riva"
+)
+
+// streamOpener is a ready ASR connection that can open one session stream.
+type streamOpener interface {
+	OpenStream(context.Context, riva.StreamConfig) (streamClient, error)
+	Close() error
+}
+
+// rivaConn adapts riva.Conn to streamOpener.
+type rivaConn struct {
+	conn *riva.Conn
+}
+
+func (c rivaConn) OpenStream(ctx context.Context, cfg riva.StreamConfig) (streamClient, error) {
+	return c.conn.OpenStream(ctx, cfg)
+}
+
+func (c rivaConn) Close() error {
+	return c.conn.Close()
+}
+
+// prewarmResult carries the outcome of one background connection dial.
+type prewarmResult struct {
+	conn     streamOpener
+	dialTook time.Duration
+	err      error
+}
+
+// Prewarm dials the Riva connection in the background so Start only has to
+// open the recognize stream. It is a no-op once started or already warming.
+func (t *Transcriber) Prewarm(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started || t.prewarmCh != nil {
+		return
+	}
+
+	cfg := t.baseStreamConfig()
+	resultCh := make(chan prewarmResult, 1)
+	t.prewarmCh = resultCh
+	go func() {
+		began := time.Now()
+		conn, err := t.dialConn(ctx, cfg)
+		resultCh <- prewarmResult{conn: conn, dialTook: time.Since(began), err: err}
+	}()
+}
+
+// Warm states reported by WarmStatus.
+const (
+	WarmCold    = "cold"
+	WarmDialing = "dialing"
+	WarmReady   = "ready"
+	WarmFailed  = "failed"
+)
+
+// WarmStatus reports the pending pre-warmed connection: cold when none is
+// held, dialing while the background dial runs, then ready or failed.
+func (t *Transcriber) WarmStatus() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.prewarmCh == nil {
+		return WarmCold
+	}
+	// The channel has one slot and only holders of t.mu receive from it, so
+	// the result can be peeked and put back.
+	select {
+	case result := <-t.prewarmCh:
+		t.prewarmCh <- result
+		if result.err != nil {
+			return WarmFailed
+		}
+		return WarmReady
+	default:
+		return WarmDialing
+	}
+}
+
+// openStreamLocked opens the session stream on a pre-warmed connection when
+// one is pending, falling back to a full dial. Caller holds t.mu.
+func (t *Transcriber) openStreamLocked(ctx context.Context, cfg riva.StreamConfig, dial func(context.Context, riva.StreamConfig) (streamClient, error)) (streamClient, error) {
+	resultCh := t.prewarmCh
+	t.prewarmCh = nil
+	if resultCh == nil {
+		return dial(ctx, cfg)
+	}
+
+	waitStart := time.Now()
+	var result prewarmResult
+	select {
+	case result = <-resultCh:
+	case <-ctx.Done():
+		go closePrewarmed(resultCh)
+		return nil, ctx.Err()
+	}
+	if result.err != nil {
+		t.logWarn(fmt.Sprintf("riva pre-warm failed, dialing directly: %v", result.err))
+		return dial(ctx, cfg)
+	}
+
+	if t.logger != nil {
+		waited := time.Since(waitStart)
+		t.logger.Info("using pre-warmed riva connection",
+			"dial_ms", result.dialTook.Milliseconds(),
+			"waited_ms", waited.Milliseconds(),
+			"saved_ms", (result.dialTook - waited).Milliseconds(),
+		)
+	}
+	return result.conn.OpenStream(ctx, cfg)
+}
+
+// discardPrewarmLocked releases a pending pre-warmed connection. Caller holds t.mu.
+func (t *Transcriber) discardPrewarmLocked() {
+	if t.prewarmCh == nil {
+		return
+	}
+	go closePrewarmed(t.prewarmCh)
+	t.prewarmCh = nil
+}
+
+// closePrewarmed waits for a background dial and closes any connection it produced.
+func closePrewarmed(resultCh <-chan prewarmResult) {
+	if result := <-resultCh; result.err == nil && result.conn != nil {
+		_ = result.conn.Close()
+	}
+}
@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbright/sotto/internal/audio"
+	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/riva"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOpener struct {
+	stream    streamClient
+	openCalls int
+	closed    chan struct{}
+}
+
+func (f *fakeOpener) OpenStream(context.Context, riva.StreamConfig) (streamClient, error) {
+	f.openCalls++
+	return f.stream, nil
+}
+
+func (f *fakeOpener) Close() error {
+	if f.closed != nil {
+		close(f.closed)
+	}
+	return nil
+}
+
+func newPrewarmTestTranscriber(t *testing.T) *Transcriber {
+	t.Helper()
+
+	transcriber := NewTranscriber(config.Default(), nil)
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		chunks := make(chan []byte)
+		close(chunks)
+		return &fakeCapture{chunks: chunks}, nil
+	}
+	return transcriber
+}
+
+func TestStartUsesPrewarmedConnectionAndSkipsDial(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	stream := &fakeStream{}
+	opener := &fakeOpener{stream: stream}
+	transcriber.dialConn = func(context.Context, riva.StreamConfig) (streamOpener, error) {
+		return opener, nil
+	}
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		t.Fatal("dialStream must not run when a pre-warmed connection is available")
+		return nil, nil
+	}
+
+	transcriber.Prewarm(context.Background())
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.Equal(t, 1, opener.openCalls)
+	require.Same(t, stream, transcriber.stream)
+	require.Nil(t, transcriber.prewarmCh)
+
+	require.NoError(t, transcriber.Cancel(context.Background()))
+}
+
+func TestWarmStatusTracksPrewarmAndReuseAcrossSessions(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	release := make(chan struct{})
+	dials := 0
+	var openers []*fakeOpener
+	transcriber.dialConn = func(context.Context, riva.StreamConfig) (streamOpener, error) {
+		<-release
+		dials++
+		opener := &fakeOpener{stream: &fakeStream{}}
+		openers = append(openers, opener)
+		return opener, nil
+	}
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		t.Fatal("dialStream must not run while a warm connection is held")
+		return nil, nil
+	}
+
+	require.Equal(t, WarmCold, transcriber.WarmStatus())
+
+	transcriber.Prewarm(context.Background())
+	require.Equal(t, WarmDialing, transcriber.WarmStatus())
+	close(release)
+	require.Eventually(t, func() bool { return transcriber.WarmStatus() == WarmReady }, time.Second, 5*time.Millisecond)
+	// Peeking must leave the connection in place for Start.
+	require.Equal(t, WarmReady, transcriber.WarmStatus())
+
+	for session := 1; session <= 2; session++ {
+		require.NoError(t, transcriber.Start(context.Background()))
+		require.Equal(t, WarmCold, transcriber.WarmStatus())
+		require.Equal(t, session, dials)
+		require.Equal(t, 1, openers[session-1].openCalls)
+		require.NoError(t, transcriber.Cancel(context.Background()))
+
+		transcriber.Prewarm(context.Background())
+		require.Eventually(t, func() bool { return transcriber.WarmStatus() == WarmReady }, time.Second, 5*time.Millisecond)
+	}
+}
+
+func TestWarmStatusReportsFailedPrewarm(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	transcriber.dialConn = func(context.Context, riva.StreamConfig) (streamOpener, error) {
+		return nil, errors.New("riva down")
+	}
+
+	transcriber.Prewarm(context.Background())
+	require.Eventually(t, func() bool { return transcriber.WarmStatus() == WarmFailed }, time.Second, 5*time.Millisecond)
+}
+
+func TestStartFallsBackToDialWhenPrewarmFails(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	transcriber.dialConn = func(context.Context, riva.StreamConfig) (streamOpener, error) {
+		return nil, errors.New("riva down")
+	}
+	dialed := false
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		dialed = true
+		return &fakeStream{}, nil
+	}
+
+	transcriber.Prewarm(context.Background())
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.True(t, dialed)
+
+	require.NoError(t, transcriber.Cancel(context.Background()))
+}
+
+func TestStartFailureBeforeDialReleasesPrewarmedConnection(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*Transcriber)
+		want  string
+	}{
+		{
+			name: "device selection",
+			setup: func(transcriber *Transcriber) {
+				transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+					return audio.Selection{}, errors.New("no audio input devices found")
+				}
+			},
+			want: "no audio input devices found",
+		},
+		{
+			name: "speech phrases",
+			setup: func(transcriber *Transcriber) {
+				transcriber.cfg.Vocab.GlobalSets = []string{"missing"}
+			},
+			want: "build speech contexts",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transcriber := newPrewarmTestTranscriber(t)
+			opener := &fakeOpener{closed: make(chan struct{})}
+			transcriber.dialConn = func(context.Context, riva.StreamConfig) (streamOpener, error) {
+				return opener, nil
+			}
+			tc.setup(transcriber)
+
+			transcriber.Prewarm(context.Background())
+			require.ErrorContains(t, transcriber.Start(context.Background()), tc.want)
+			require.Nil(t, transcriber.prewarmCh)
+			require.Equal(t, WarmCold, transcriber.WarmStatus())
+
+			select {
+			case <-opener.closed:
+			case <-time.After(time.Second):
+				t.Fatal("expected pre-warmed connection to be closed after a failed start")
+			}
+		})
+	}
+}
+
+func TestCancelClosesUnusedPrewarmedConnection(t *testing.T) {
+	transcriber := newPrewarmTestTranscriber(t)
+	opener := &fakeOpener{closed: make(chan struct{})}
+	transcriber.dialConn = func(context.Context, riva.StreamConfig) (streamOpener, error) {
+		return opener, nil
+	}
+
+	transcriber.Prewarm(context.Background())
+	require.NoError(t, transcriber.Cancel(context.Background()))
+	require.Nil(t, transcriber.prewarmCh)
+
+	select {
+	case <-opener.closed:
+	case <-time.After(time.Second):
+		t.Fatal("expected unused pre-warmed connection to be closed")
+	}
+}
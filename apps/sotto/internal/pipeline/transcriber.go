@@ -4,6 +4,8 @@ package pipeline
 import (
 	"context"
 	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -14,9 +16,13 @@ import (
 
 	"github.com/rbright/sotto/internal/audio"
 	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/ipc"
 	"github.com/rbright/sotto/internal/riva"
 	"github.com/rbright/sotto/internal/session"
 	"github.com/rbright/sotto/internal/transcript"
+	"github.com/rbright/sotto/internal/version"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // captureClient is the audio-capture contract needed by the transcriber.
@@ -25,63 +31,191 @@ type captureClient interface {
 	Chunks() <-chan []byte
 	BytesCaptured() int64
 	RawPCM() []byte
+	Err() error
+}
+
+// chunkEncoder compresses PCM chunks before they are sent to Riva.
+type chunkEncoder interface {
+	Encode([]byte) ([]byte, error)
 }
 
 // streamClient is the ASR-stream contract needed by the transcriber.
 type streamClient interface {
 	SendAudio([]byte) error
-	CloseAndCollect(context.Context) ([]string, time.Duration, error)
+	CloseAndCollectTranscript(context.Context) (riva.Transcript, time.Duration, error)
+	FlushSegments() []string
+	InterimSnapshot() string
 	Cancel() error
 }
 
+// spokenDigitsPhrase boosts Riva's numeric-sequence class so digit strings
+// (phone numbers, codes) decode as numbers.
+var spokenDigitsPhrase = riva.SpeechPhrase{Phrase: "$OOV_NUMERIC_SEQUENCE", Boost: 20}
+
 // Transcriber owns one end-to-end capture -> ASR -> transcript pipeline instance.
 type Transcriber struct {
-	cfg    config.Config
+	cfg config.Config
+	// base is the config the transcriber was built with; ConfigureSession
+	// derives cfg from it for each session.
+	base   config.Config
 	logger *slog.Logger
 
 	mu      sync.Mutex
 	started bool
+	// continuesSentence is set when the last flush ended mid-sentence, so
+	// the next flush or the final transcript does not start a new one.
+	continuesSentence bool
 
 	selection audio.Selection
 	capture   captureClient
 	stream    streamClient
+	encoder   chunkEncoder
 
 	sendErrCh chan error
+	prewarmCh chan prewarmResult
+
+	// interimSink receives live interim text while recording; interimStop
+	// ends the polling goroutine for the current session, which closes
+	// interimDone once it has returned.
+	interimSink func(string)
+	interimStop chan struct{}
+	interimDone chan struct{}
 
 	selectDevice func(context.Context, string, string) (audio.Selection, error)
 	startCapture func(context.Context, audio.Device) (captureClient, error)
 	dialStream   func(context.Context, riva.StreamConfig) (streamClient, error)
+	dialConn     func(context.Context, riva.StreamConfig) (streamOpener, error)
+	newEncoder   func() (chunkEncoder, error)
+	// postprocessTimeout bounds each transcript.postprocess_cmd run.
+	postprocessTimeout time.Duration
 
 	debugGRPCFile *os.File
+	// sessionID is the controller's ID for the current session; it tags log
+	// lines and debug artifact names.
+	sessionID string
+	// sessionStart is the shared time origin for the audio and gRPC debug dumps;
+	// captureOffset is how long after it capture began.
+	sessionStart  time.Time
+	captureOffset time.Duration
+}
+
+// captureOptions maps audio config onto Pulse record stream options.
+func captureOptions(cfg config.AudioConfig) audio.CaptureOptions {
+	return audio.CaptureOptions{
+		Latency:          time.Duration(cfg.LatencyMS) * time.Millisecond,
+		InternalResample: cfg.Resampler == "internal",
+	}
 }
 
 // NewTranscriber constructs a pipeline transcriber from runtime config.
 func NewTranscriber(cfg config.Config, logger *slog.Logger) *Transcriber {
+	pulseID := audio.IdentityFromConfig(cfg.Audio)
+	filter := audio.DeviceFilter{Allow: cfg.Audio.Allow, Deny: cfg.Audio.Deny, AllowMonitor: cfg.Audio.AllowMonitor}
+	captureOpts := captureOptions(cfg.Audio)
 	return &Transcriber{
-		cfg:    cfg,
-		logger: logger,
+		cfg:                cfg,
+		base:               cfg,
+		logger:             logger,
+		postprocessTimeout: postprocessTimeout,
 		selectDevice: func(ctx context.Context, input string, fallback string) (audio.Selection, error) {
-			return audio.SelectDevice(ctx, input, fallback)
+			return audio.SelectDevice(ctx, input, fallback, filter, pulseID)
 		},
 		startCapture: func(ctx context.Context, device audio.Device) (captureClient, error) {
-			return audio.StartCapture(ctx, device)
+			return audio.StartCapture(ctx, device, pulseID, captureOpts)
 		},
 		dialStream: func(ctx context.Context, cfg riva.StreamConfig) (streamClient, error) {
 			return riva.DialStream(ctx, cfg)
 		},
+		dialConn: func(ctx context.Context, cfg riva.StreamConfig) (streamOpener, error) {
+			conn, err := riva.DialConn(ctx, cfg)
+			if err != nil {
+				return nil, err
+			}
+			return rivaConn{conn: conn}, nil
+		},
+		newEncoder: func() (chunkEncoder, error) {
+			return audio.NewFLACEncoder(16000)
+		},
+	}
+}
+
+// startSources supplies the device, capture, and ASR stream for one session.
+// Start uses the transcriber's own; Bench substitutes file replay and timing.
+type startSources struct {
+	selectDevice func(context.Context, string, string) (audio.Selection, error)
+	startCapture func(context.Context, audio.Device) (captureClient, error)
+	dialStream   func(context.Context, riva.StreamConfig) (streamClient, error)
+}
+
+func (t *Transcriber) sources() startSources {
+	return startSources{selectDevice: t.selectDevice, startCapture: t.startCapture, dialStream: t.dialStream}
+}
+
+// ConfigureSession applies forwarded per-session overrides on top of the
+// config the transcriber was built with; nil restores that config. It fails
+// while a session is running or when the result does not validate.
+func (t *Transcriber) ConfigureSession(overrides *ipc.SessionOverrides) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started {
+		return fmt.Errorf("transcriber already started")
+	}
+
+	cfg := t.base
+	if overrides != nil {
+		ApplySessionOverrides(&cfg, *overrides)
+		if _, err := config.Validate(cfg); err != nil {
+			return err
+		}
+	}
+	t.cfg = cfg
+	return nil
+}
+
+// ApplySessionOverrides sets the per-session --punctuation, --code, --model,
+// --vocab, and --phrase flags on cfg.
+func ApplySessionOverrides(cfg *config.Config, overrides ipc.SessionOverrides) {
+	switch overrides.Punctuation {
+	case "on":
+		cfg.ASR.AutomaticPunctuation = true
+	case "off":
+		cfg.ASR.AutomaticPunctuation = false
+	}
+	if overrides.Code {
+		cfg.Transcript.CodeMode = true
+	}
+	if overrides.Model != "" {
+		cfg.ASR.Model = overrides.Model
+	}
+	if overrides.Vocab != nil {
+		cfg.Vocab.GlobalSets = append([]string(nil), overrides.Vocab...)
+	}
+	if overrides.Phrases != nil {
+		cfg.Vocab.SessionPhrases = make([]config.SpeechPhrase, 0, len(overrides.Phrases))
+		for _, phrase := range overrides.Phrases {
+			cfg.Vocab.SessionPhrases = append(cfg.Vocab.SessionPhrases, config.SpeechPhrase{Phrase: phrase.Phrase, Boost: phrase.Boost})
+		}
 	}
 }
 
 // Start resolves device selection, opens Riva stream, and starts audio capture.
 func (t *Transcriber) Start(ctx context.Context) error {
+	return t.start(ctx, t.sources())
+}
+
+func (t *Transcriber) start(ctx context.Context, src startSources) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	if t.started {
 		return fmt.Errorf("transcriber already started")
 	}
+	// openStreamLocked takes any pending pre-warmed connection; release it
+	// when Start fails before getting that far so it is not left pending.
+	defer t.discardPrewarmLocked()
+	t.sessionID = session.IDFromContext(ctx)
 
-	selection, err := t.selectDevice(ctx, t.cfg.Audio.Input, t.cfg.Audio.Fallback)
+	selection, err := src.selectDevice(ctx, t.cfg.Audio.Input, t.cfg.Audio.Fallback)
 	if err != nil {
 		return err
 	}
@@ -95,54 +229,126 @@ func (t *Transcriber) Start(ctx context.Context) error {
 		return fmt.Errorf("build speech contexts: %w", err)
 	}
 
+	t.sessionStart = time.Now()
+	t.captureOffset = 0
 	if t.cfg.Debug.EnableGRPCDump {
-		file, ferr := createDebugFile("grpc", "json")
+		file, ferr := createDebugFile("grpc", t.sessionID, "json")
 		if ferr != nil {
 			return ferr
 		}
+		if herr := writeDebugGRPCHeader(file, t.sessionStart, t.sessionID); herr != nil {
+			t.logWarn(fmt.Sprintf("unable to write debug grpc header: %v", herr))
+		}
 		t.debugGRPCFile = file
 	}
 
-	rivaPhrases := make([]riva.SpeechPhrase, 0, len(speechPhrases))
+	rivaPhrases := make([]riva.SpeechPhrase, 0, len(speechPhrases)+1)
 	for _, phrase := range speechPhrases {
 		rivaPhrases = append(rivaPhrases, riva.SpeechPhrase{Phrase: phrase.Phrase, Boost: phrase.Boost})
 	}
+	if t.cfg.ASR.SpokenDigits {
+		rivaPhrases = append(rivaPhrases, spokenDigitsPhrase)
+	}
 
-	stream, err := t.dialStream(ctx, riva.StreamConfig{
-		Endpoint:             t.cfg.RivaGRPC,
-		LanguageCode:         t.cfg.ASR.LanguageCode,
-		Model:                t.cfg.ASR.Model,
-		AutomaticPunctuation: t.cfg.ASR.AutomaticPunctuation,
-		SpeechPhrases:        rivaPhrases,
-		DialTimeout:          3 * time.Second,
-		DebugResponseSinkJSON: func() *os.File {
-			if t.debugGRPCFile == nil {
-				return nil
-			}
-			return t.debugGRPCFile
-		}(),
-	})
+	var encoder chunkEncoder
+	if t.cfg.ASR.Encoding == "flac" {
+		encoder, err = t.newEncoder()
+		if err != nil {
+			t.logWarn(fmt.Sprintf("flac encoder unavailable, sending linear pcm: %v", err))
+			encoder = nil
+		}
+	}
+
+	streamCfg := t.baseStreamConfig()
+	streamCfg.SpeechPhrases = rivaPhrases
+	if encoder != nil {
+		streamCfg.Encoding = "flac"
+	}
+	streamCfg.DebugOrigin = t.sessionStart
+	streamCfg.DebugResponseSinkJSON = func() *os.File {
+		if t.debugGRPCFile == nil {
+			return nil
+		}
+		return t.debugGRPCFile
+	}()
+
+	stream, err := t.openStreamLocked(ctx, streamCfg, src.dialStream)
 	if err != nil {
 		t.closeDebugArtifactsLocked()
-		return err
+		if ctx.Err() != nil || !retryableStartError(err) {
+			return err
+		}
+		// No audio has been captured yet, so the controller may retry the dial.
+		return session.Recoverable(err)
 	}
 	t.stream = stream
 
-	capture, err := t.startCapture(ctx, selection.Device)
+	capture, err := src.startCapture(ctx, selection.Device)
 	if err != nil {
 		_ = stream.Cancel()
 		t.closeDebugArtifactsLocked()
 		return err
 	}
 	t.capture = capture
+	t.captureOffset = time.Since(t.sessionStart)
+	t.encoder = encoder
 
 	t.sendErrCh = make(chan error, 1)
 	go t.sendLoop()
 
+	if t.interimSink != nil {
+		stop, done := make(chan struct{}), make(chan struct{})
+		t.interimStop, t.interimDone = stop, done
+		go func() {
+			defer close(done)
+			pollInterim(stream, t.interimSink, interimUpdateInterval, stop)
+		}()
+	}
+
 	t.started = true
 	return nil
 }
 
+// retryableStartError reports whether a failed stream open may succeed on a
+// second attempt: Riva was unreachable or slow to answer (gRPC Unavailable or
+// DeadlineExceeded, including the dial readiness timeout). Rejections such as
+// InvalidArgument for an unknown model fail the same way every time.
+func retryableStartError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// baseStreamConfig maps runtime config to the Riva stream settings shared by
+// pre-warm dials and per-session streams.
+func (t *Transcriber) baseStreamConfig() riva.StreamConfig {
+	return riva.StreamConfig{
+		Endpoint:              t.cfg.RivaGRPC,
+		LanguageCode:          t.cfg.ASR.LanguageCode,
+		Model:                 t.cfg.ASR.Model,
+		AutomaticPunctuation:  t.cfg.ASR.AutomaticPunctuation,
+		Verbatim:              t.cfg.ASR.Verbatim,
+		DialTimeout:           3 * time.Second,
+		MaxRecvMsgBytes:       t.cfg.RivaMaxRecvMB << 20,
+		MaxSendMsgBytes:       t.cfg.RivaMaxSendMB << 20,
+		KeepaliveInterval:     time.Duration(t.cfg.RivaKeepaliveMS) * time.Millisecond,
+		FirstResponseTimeout:  time.Duration(t.cfg.RivaFirstResponseTimeoutMS) * time.Millisecond,
+		InterimMaxAge:         t.cfg.ASR.InterimMaxAge,
+		FinalDedupeWindow:     t.cfg.ASR.FinalDedupeWindow,
+		InterimFuzzyThreshold: t.cfg.ASR.InterimFuzzyThreshold,
+		Logger:                t.sessionLogger(),
+	}
+}
+
+// UsingFallbackDevice reports whether Start selected a fallback input device.
+func (t *Transcriber) UsingFallbackDevice() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.started && t.selection.Fallback
+}
+
 // StopAndTranscribe stops capture, closes stream, and assembles the transcript.
 func (t *Transcriber) StopAndTranscribe(ctx context.Context) (session.StopResult, error) {
 	t.mu.Lock()
@@ -158,58 +364,140 @@ func (t *Transcriber) StopAndTranscribe(ctx context.Context) (session.StopResult
 	}
 	defer t.resetRuntimeState()
 
+	t.stopInterim()
 	_ = capture.Stop()
 
 	var sendErr error
 	if sendErrCh != nil {
-		sendErr = <-sendErrCh
+		select {
+		case sendErr = <-sendErrCh:
+		case <-ctx.Done():
+			sendErr = ctx.Err()
+		}
+	}
+	disconnected := errors.Is(sendErr, audio.ErrDisconnected)
+	if disconnected && t.cfg.Audio.CommitOnDisconnect {
+		t.logWarn("pulse disconnected mid-capture; committing partial transcript")
+		sendErr = nil
 	}
 	if sendErr != nil {
 		_ = stream.Cancel()
 		result := session.StopResult{
 			AudioDevice:   describeDevice(selection.Device),
 			BytesCaptured: capture.BytesCaptured(),
+			Model:         t.cfg.ASR.Model,
+			LanguageCode:  t.cfg.ASR.LanguageCode,
 		}
 		t.writeDebugAudio(capture.RawPCM())
 		t.closeDebugArtifacts()
+		if disconnected {
+			return result, fmt.Errorf("capture interrupted: %w", sendErr)
+		}
 		return result, fmt.Errorf("send audio stream: %w", sendErr)
 	}
 
+	if capture.BytesCaptured() == 0 {
+		// Nothing was sent, so there is nothing for Riva to recognize.
+		_ = stream.Cancel()
+		t.closeDebugArtifacts()
+		return session.StopResult{
+			AudioDevice:   describeDevice(selection.Device),
+			BytesCaptured: capture.BytesCaptured(),
+			Model:         t.cfg.ASR.Model,
+			LanguageCode:  t.cfg.ASR.LanguageCode,
+		}, session.ErrNoAudioCaptured
+	}
+
 	closeCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
-	segments, grpcLatency, err := stream.CloseAndCollect(closeCtx)
+	collected, grpcLatency, err := stream.CloseAndCollectTranscript(closeCtx)
 	if err != nil {
 		result := session.StopResult{
 			AudioDevice:   describeDevice(selection.Device),
 			BytesCaptured: capture.BytesCaptured(),
 			GRPCLatency:   grpcLatency,
+			Model:         t.cfg.ASR.Model,
+			LanguageCode:  t.cfg.ASR.LanguageCode,
 		}
 		t.writeDebugAudio(capture.RawPCM())
 		t.closeDebugArtifacts()
 		return result, fmt.Errorf("collect final transcript: %w", err)
 	}
 
-	transcribed := transcript.Assemble(segments, transcript.Options{
-		TrailingSpace:       t.cfg.Transcript.TrailingSpace,
-		CapitalizeSentences: t.cfg.Transcript.CapitalizeSentences,
-	})
+	if logger := t.sessionLogger(); collected.InvalidUTF8 > 0 && logger != nil {
+		logger.Debug("stripped invalid utf-8 from transcript", "hypotheses", collected.InvalidUTF8)
+	}
+
+	transcribed := t.postprocess(ctx, transcript.Assemble(collected.Segments(), t.assembleOptions()))
 	rawPCM := capture.RawPCM()
 	t.writeDebugAudio(rawPCM)
 	t.closeDebugArtifacts()
 
 	return session.StopResult{
-		Transcript:    transcribed,
-		AudioDevice:   describeDevice(selection.Device),
-		BytesCaptured: capture.BytesCaptured(),
-		GRPCLatency:   grpcLatency,
+		Transcript:        transcribed,
+		AudioDevice:       describeDevice(selection.Device),
+		BytesCaptured:     capture.BytesCaptured(),
+		GRPCLatency:       grpcLatency,
+		Model:             t.cfg.ASR.Model,
+		LanguageCode:      t.cfg.ASR.LanguageCode,
+		CommittedSegments: collected.Committed,
+		InterimTail:       collected.Interim,
+		RequestID:         collected.RequestID,
+		ModelVersion:      collected.ModelVersion,
 	}, nil
 }
 
+// Flush assembles the segments finalized since the previous flush while capture
+// keeps running. It returns an empty string when nothing new was finalized.
+func (t *Transcriber) Flush(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	started := t.started
+	stream := t.stream
+	t.mu.Unlock()
+
+	if !started || stream == nil {
+		return "", session.ErrPipelineUnavailable
+	}
+	text := transcript.Assemble(stream.FlushSegments(), t.assembleOptions())
+	if text != "" {
+		t.mu.Lock()
+		t.continuesSentence = !transcript.EndsSentence(text)
+		t.mu.Unlock()
+	}
+	return t.postprocess(ctx, text), nil
+}
+
+// assembleOptions maps transcript config to assembly options.
+func (t *Transcriber) assembleOptions() transcript.Options {
+	t.mu.Lock()
+	continuesSentence := t.continuesSentence
+	t.mu.Unlock()
+
+	opts := transcript.Options{
+		TrailingSpace:        t.cfg.Transcript.TrailingSpace,
+		TrailingNewline:      t.cfg.Transcript.TrailingNewline,
+		Capitalize:           t.cfg.Transcript.Capitalize,
+		ContinuesSentence:    continuesSentence,
+		SingleLine:           t.cfg.Transcript.SingleLine,
+		TrimPolicy:           t.cfg.Transcript.TrimPolicy,
+		SpokenDigits:         t.cfg.ASR.SpokenDigits,
+		CollapseInitialisms:  t.cfg.Transcript.CollapseInitialisms,
+		Initialisms:          t.cfg.Transcript.Initialisms,
+		InitialismMinLetters: t.cfg.Transcript.InitialismMinLetters,
+	}
+	if t.cfg.Transcript.CodeMode {
+		opts.SymbolWords = t.cfg.Transcript.SymbolWords
+	}
+	return opts
+}
+
 // Cancel stops capture and stream immediately without transcript commit.
 func (t *Transcriber) Cancel(_ context.Context) error {
 	t.mu.Lock()
 	capture := t.capture
 	stream := t.stream
+	t.discardPrewarmLocked()
+	t.stopInterimLocked()
 	t.mu.Unlock()
 	defer t.resetRuntimeState()
 
@@ -229,9 +517,12 @@ func (t *Transcriber) resetRuntimeState() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.started = false
+	t.continuesSentence = false
 	t.capture = nil
 	t.stream = nil
+	t.encoder = nil
 	t.sendErrCh = nil
+	t.stopInterimLocked()
 }
 
 // sendLoop forwards capture chunks to Riva and reports the first send failure.
@@ -239,6 +530,7 @@ func (t *Transcriber) sendLoop() {
 	t.mu.Lock()
 	capture := t.capture
 	stream := t.stream
+	encoder := t.encoder
 	errCh := t.sendErrCh
 	t.mu.Unlock()
 
@@ -265,12 +557,25 @@ func (t *Transcriber) sendLoop() {
 		if len(chunk) == 0 {
 			continue
 		}
+		if encoder != nil {
+			encoded, err := encoder.Encode(chunk)
+			if err != nil {
+				_ = capture.Stop()
+				sendResult(fmt.Errorf("encode flac: %w", err))
+				return
+			}
+			chunk = encoded
+		}
 		if err := stream.SendAudio(chunk); err != nil {
 			_ = capture.Stop()
 			sendResult(err)
 			return
 		}
 	}
+	// Chunks also closes when Pulse drops the stream; report that distinctly.
+	if err := capture.Err(); err != nil {
+		sendResult(err)
+	}
 }
 
 // describeDevice formats device metadata for logs/session results.
@@ -288,25 +593,38 @@ func describeDevice(device audio.Device) string {
 
 // logWarn emits warning-level logs when logger is configured.
 func (t *Transcriber) logWarn(message string) {
-	if t.logger == nil {
+	logger := t.sessionLogger()
+	if logger == nil {
 		return
 	}
-	t.logger.Warn(message)
+	logger.Warn(message)
 }
 
-// createDebugFile creates timestamped debug artifacts under state/sotto/debug.
-func createDebugFile(prefix string, extension string) (*os.File, error) {
-	stateDir, err := resolveStateDir()
+// sessionLogger returns the logger tagged with the current session ID, or the
+// plain logger outside a session.
+func (t *Transcriber) sessionLogger() *slog.Logger {
+	if t.logger == nil || t.sessionID == "" {
+		return t.logger
+	}
+	return t.logger.With("session_id", t.sessionID)
+}
+
+// createDebugFile creates timestamped debug artifacts under state/sotto/debug,
+// suffixed with sessionID when one is set.
+func createDebugFile(prefix string, sessionID string, extension string) (*os.File, error) {
+	debugDir, err := DebugDir()
 	if err != nil {
 		return nil, err
 	}
-	debugDir := filepath.Join(stateDir, "sotto", "debug")
 	if err := os.MkdirAll(debugDir, 0o700); err != nil {
 		return nil, fmt.Errorf("create debug dir: %w", err)
 	}
 
-	timestamp := time.Now().Format("20060102-150405.000")
-	path := filepath.Join(debugDir, fmt.Sprintf("%s-%s.%s", prefix, timestamp, extension))
+	name := prefix + "-" + time.Now().Format("20060102-150405.000")
+	if sessionID != "" {
+		name += "-" + sessionID
+	}
+	path := filepath.Join(debugDir, name+"."+extension)
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
 	if err != nil {
 		return nil, fmt.Errorf("open debug file %q: %w", path, err)
@@ -314,16 +632,13 @@ func createDebugFile(prefix string, extension string) (*os.File, error) {
 	return file, nil
 }
 
-// resolveStateDir returns XDG_STATE_HOME fallback path for debug artifacts.
-func resolveStateDir() (string, error) {
-	if xdg := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); xdg != "" {
-		return xdg, nil
-	}
-	home, err := os.UserHomeDir()
+// DebugDir returns where debug.audio_dump and debug.grpc_dump files are written.
+func DebugDir() (string, error) {
+	stateDir, err := config.StateDir()
 	if err != nil {
-		return "", fmt.Errorf("resolve home directory for state: %w", err)
+		return "", err
 	}
-	return filepath.Join(home, ".local", "state"), nil
+	return filepath.Join(stateDir, "debug"), nil
 }
 
 // closeDebugArtifacts closes open debug sinks.
@@ -347,20 +662,73 @@ func (t *Transcriber) writeDebugAudio(rawPCM []byte) {
 		return
 	}
 
-	file, err := createDebugFile("audio", "wav")
+	file, err := createDebugFile("audio", t.sessionID, "wav")
 	if err != nil {
 		t.logWarn(fmt.Sprintf("unable to create debug audio dump: %v", err))
 		return
 	}
 	defer file.Close()
 
-	if err := writePCM16WAV(file, rawPCM, 16000, 1); err != nil {
+	t.mu.Lock()
+	info := []wavInfoField{{ID: "ICMT", Text: debugOriginComment(t.sessionStart, t.captureOffset)}}
+	if t.cfg.Debug.WAVMetadata {
+		info = append(info, debugWAVMetadata(t.sessionStart, describeDevice(t.selection.Device))...)
+	}
+	t.mu.Unlock()
+	if err := writePCM16WAV(file, rawPCM, 16000, 1, info); err != nil {
 		t.logWarn(fmt.Sprintf("unable to write debug audio dump: %v", err))
 	}
 }
 
+// debugGRPCHeader is the first line of the gRPC debug dump. Response t_ms
+// offsets are measured from SessionStart.
+type debugGRPCHeader struct {
+	SessionID    string `json:"session_id,omitempty"`
+	SessionStart string `json:"session_start"`
+}
+
+// writeDebugGRPCHeader records the shared time origin at the top of the gRPC dump.
+func writeDebugGRPCHeader(file *os.File, sessionStart time.Time, sessionID string) error {
+	b, err := json.Marshal(debugGRPCHeader{SessionID: sessionID, SessionStart: formatDebugOrigin(sessionStart)})
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(b, '\n'))
+	return err
+}
+
+// debugOriginComment describes where the WAV dump sits on the session timeline:
+// audio time zero is capture_offset_ms after the gRPC dump's t_ms zero.
+func debugOriginComment(sessionStart time.Time, captureOffset time.Duration) string {
+	if sessionStart.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("sotto session_start=%s capture_offset_ms=%d", formatDebugOrigin(sessionStart), captureOffset.Milliseconds())
+}
+
+// debugWAVMetadata describes the dump for debug.wav_metadata: the software
+// that wrote it (ISFT), when capture started (ICRD), and the input device (ISRF).
+func debugWAVMetadata(sessionStart time.Time, device string) []wavInfoField {
+	fields := []wavInfoField{{ID: "ISFT", Text: "sotto " + version.Version}}
+	if !sessionStart.IsZero() {
+		fields = append(fields, wavInfoField{ID: "ICRD", Text: formatDebugOrigin(sessionStart)})
+	}
+	return append(fields, wavInfoField{ID: "ISRF", Text: device})
+}
+
+func formatDebugOrigin(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// wavInfoField is one LIST/INFO entry, e.g. ICMT (comment) or ISFT (software).
+type wavInfoField struct {
+	ID   string
+	Text string
+}
+
 // writePCM16WAV writes raw little-endian PCM bytes with a minimal WAV header.
-func writePCM16WAV(file *os.File, pcm []byte, sampleRate int, channels int) error {
+// A non-empty comment is stored as a LIST/INFO ICMT chunk before the samples.
+func writePCM16WAV(file *os.File, pcm []byte, sampleRate int, channels int, info []wavInfoField) error {
 	if channels <= 0 {
 		channels = 1
 	}
@@ -368,10 +736,11 @@ func writePCM16WAV(file *os.File, pcm []byte, sampleRate int, channels int) erro
 	byteRate := sampleRate * channels * (bitsPerSample / 8)
 	blockAlign := channels * (bitsPerSample / 8)
 
-	chunkSize := uint32(36 + len(pcm))
+	infoChunk := wavInfoChunk(info)
+	chunkSize := uint32(36 + len(infoChunk) + len(pcm))
 	subChunk2Size := uint32(len(pcm))
 
-	header := make([]byte, 44)
+	header := make([]byte, 36)
 	copy(header[0:4], []byte("RIFF"))
 	binary.LittleEndian.PutUint32(header[4:8], chunkSize)
 	copy(header[8:12], []byte("WAVE"))
@@ -383,8 +752,9 @@ func writePCM16WAV(file *os.File, pcm []byte, sampleRate int, channels int) erro
 	binary.LittleEndian.PutUint32(header[28:32], uint32(byteRate))
 	binary.LittleEndian.PutUint16(header[32:34], uint16(blockAlign))
 	binary.LittleEndian.PutUint16(header[34:36], bitsPerSample)
-	copy(header[36:40], []byte("data"))
-	binary.LittleEndian.PutUint32(header[40:44], subChunk2Size)
+	header = append(header, infoChunk...)
+	header = append(header, "data"...)
+	header = binary.LittleEndian.AppendUint32(header, subChunk2Size)
 
 	if _, err := file.Write(header); err != nil {
 		return err
@@ -392,3 +762,31 @@ func writePCM16WAV(file *os.File, pcm []byte, sampleRate int, channels int) erro
 	_, err := file.Write(pcm)
 	return err
 }
+
+// wavInfoChunk encodes fields as one LIST/INFO chunk, skipping empty text.
+// Each entry is NUL-terminated and padded to an even length; no chunk is
+// written when every field is empty.
+func wavInfoChunk(fields []wavInfoField) []byte {
+	entries := make([]byte, 0)
+	for _, field := range fields {
+		if field.Text == "" {
+			continue
+		}
+		text := append([]byte(field.Text), 0)
+		if len(text)%2 == 1 {
+			text = append(text, 0)
+		}
+		entries = append(entries, field.ID...)
+		entries = binary.LittleEndian.AppendUint32(entries, uint32(len(text)))
+		entries = append(entries, text...)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	chunk := make([]byte, 0, 12+len(entries))
+	chunk = append(chunk, "LIST"...)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(4+len(entries)))
+	chunk = append(chunk, "INFO"...)
+	return append(chunk, entries...)
+}
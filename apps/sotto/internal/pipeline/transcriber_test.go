@@ -1,19 +1,33 @@
 package pipeline
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/rbright/sotto/internal/audio"
 	"github.com/rbright/sotto/internal/config"
+	"github.com/rbright/sotto/internal/ipc"
 	"github.com/rbright/sotto/internal/riva"
 	"github.com/rbright/sotto/internal/session"
+	"github.com/rbright/sotto/internal/version"
+	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestDescribeDevice(t *testing.T) {
@@ -22,30 +36,21 @@ func TestDescribeDevice(t *testing.T) {
 	require.Equal(t, "alsa_input.wave3", describeDevice(audio.Device{ID: "alsa_input.wave3"}))
 }
 
-func TestResolveStateDirUsesXDGStateHome(t *testing.T) {
-	xdgStateHome := t.TempDir()
-	t.Setenv("XDG_STATE_HOME", xdgStateHome)
-	t.Setenv("HOME", t.TempDir())
-
-	dir, err := resolveStateDir()
-	require.NoError(t, err)
-	require.Equal(t, xdgStateHome, dir)
-}
+func TestCaptureOptionsFromAudioConfig(t *testing.T) {
+	cfg := config.Default()
+	require.Equal(t, audio.CaptureOptions{}, captureOptions(cfg.Audio))
 
-func TestResolveStateDirFallsBackToHome(t *testing.T) {
-	home := t.TempDir()
-	t.Setenv("XDG_STATE_HOME", "")
-	t.Setenv("HOME", home)
+	cfg.Audio.LatencyMS = 60
+	require.Equal(t, audio.CaptureOptions{Latency: 60 * time.Millisecond}, captureOptions(cfg.Audio))
 
-	dir, err := resolveStateDir()
-	require.NoError(t, err)
-	require.Equal(t, filepath.Join(home, ".local", "state"), dir)
+	cfg.Audio.Resampler = "internal"
+	require.Equal(t, audio.CaptureOptions{Latency: 60 * time.Millisecond, InternalResample: true}, captureOptions(cfg.Audio))
 }
 
 func TestCreateDebugFileCreatesExpectedPath(t *testing.T) {
 	t.Setenv("XDG_STATE_HOME", t.TempDir())
 
-	file, err := createDebugFile("grpc", "json")
+	file, err := createDebugFile("grpc", "", "json")
 	require.NoError(t, err)
 	path := file.Name()
 	require.NoError(t, file.Close())
@@ -55,6 +60,11 @@ func TestCreateDebugFileCreatesExpectedPath(t *testing.T) {
 	require.Contains(t, filepath.Base(path), "grpc-")
 	require.Equal(t, ".json", filepath.Ext(path))
 
+	file, err = createDebugFile("audio", "1a2b3c4d", "wav")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.True(t, strings.HasSuffix(file.Name(), "-1a2b3c4d.wav"), file.Name())
+
 	stat, err := os.Stat(path)
 	require.NoError(t, err)
 	require.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
@@ -65,7 +75,7 @@ func TestWritePCM16WAVWritesHeaderAndPCM(t *testing.T) {
 	require.NoError(t, err)
 
 	pcm := []byte{0x01, 0x00, 0xFF, 0x7F}
-	require.NoError(t, writePCM16WAV(file, pcm, 16000, 0))
+	require.NoError(t, writePCM16WAV(file, pcm, 16000, 0, nil))
 	require.NoError(t, file.Close())
 
 	data, err := os.ReadFile(file.Name())
@@ -81,6 +91,159 @@ func TestWritePCM16WAVWritesHeaderAndPCM(t *testing.T) {
 	require.Equal(t, pcm, data[44:])
 }
 
+func TestWritePCM16WAVEmbedsCommentChunk(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "*.wav")
+	require.NoError(t, err)
+
+	pcm := []byte{0x01, 0x00, 0xFF, 0x7F}
+	require.NoError(t, writePCM16WAV(file, pcm, 16000, 1, []wavInfoField{{ID: "ICMT", Text: "origin"}}))
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+
+	// "origin" plus NUL is 7 bytes, padded to 8.
+	const infoLen = 20 + 8
+	require.Len(t, data, 44+infoLen+len(pcm))
+	require.Equal(t, uint32(len(data)-8), binary.LittleEndian.Uint32(data[4:8]))
+	require.Equal(t, "LIST", string(data[36:40]))
+	require.Equal(t, "INFOICMT", string(data[44:52]))
+	require.Equal(t, "origin\x00\x00", string(data[56:64]))
+	require.Equal(t, "data", string(data[36+infoLen:40+infoLen]))
+	require.Equal(t, pcm, data[44+infoLen:])
+}
+
+func TestWritePCM16WAVEmbedsMetadataFields(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "*.wav")
+	require.NoError(t, err)
+
+	sessionStart := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	info := append([]wavInfoField{{ID: "ICMT", Text: "origin"}}, debugWAVMetadata(sessionStart, "USB Mic (usb-1)")...)
+	pcm := []byte{0x01, 0x00, 0xFF, 0x7F, 0x10, 0x20}
+	require.NoError(t, writePCM16WAV(file, pcm, 16000, 1, info))
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+	require.Equal(t, "RIFF", string(data[0:4]))
+	require.Equal(t, uint32(len(data)-8), binary.LittleEndian.Uint32(data[4:8]))
+
+	// Walk the RIFF chunks after "WAVE" the way a reader would.
+	fields := map[string]string{}
+	var pcmOut []byte
+	for offset := 12; offset+8 <= len(data); {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := data[offset+8 : offset+8+size]
+		switch id {
+		case "LIST":
+			require.Equal(t, "INFO", string(body[0:4]))
+			for sub := 4; sub+8 <= len(body); {
+				subSize := int(binary.LittleEndian.Uint32(body[sub+4 : sub+8]))
+				fields[string(body[sub:sub+4])] = strings.TrimRight(string(body[sub+8:sub+8+subSize]), "\x00")
+				sub += 8 + subSize
+			}
+		case "data":
+			pcmOut = body
+		}
+		offset += 8 + size + size%2
+	}
+
+	require.Equal(t, "origin", fields["ICMT"])
+	require.Equal(t, "sotto "+version.Version, fields["ISFT"])
+	require.Equal(t, "2026-03-04T05:06:07Z", fields["ICRD"])
+	require.Equal(t, "USB Mic (usb-1)", fields["ISRF"])
+	require.Equal(t, pcm, pcmOut)
+}
+
+func TestDebugDumpsShareSessionOrigin(t *testing.T) {
+	xdgStateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", xdgStateHome)
+
+	cfg := config.Default()
+	cfg.Debug.EnableAudioDump = true
+	cfg.Debug.EnableGRPCDump = true
+	transcriber := NewTranscriber(cfg, nil)
+
+	chunks := make(chan []byte)
+	close(chunks)
+	var streamCfg riva.StreamConfig
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+	}
+	transcriber.dialStream = func(_ context.Context, cfg riva.StreamConfig) (streamClient, error) {
+		streamCfg = cfg
+		return &fakeStream{}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks, raw: []byte{0x01, 0x00}}, nil
+	}
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.Equal(t, transcriber.sessionStart, streamCfg.DebugOrigin)
+	require.NoError(t, transcriber.Cancel(context.Background()))
+
+	grpcDumps, err := filepath.Glob(filepath.Join(xdgStateHome, "sotto", "debug", "grpc-*.json"))
+	require.NoError(t, err)
+	require.Len(t, grpcDumps, 1)
+	grpcData, err := os.ReadFile(grpcDumps[0])
+	require.NoError(t, err)
+	var header debugGRPCHeader
+	require.NoError(t, json.Unmarshal([]byte(strings.SplitN(string(grpcData), "\n", 2)[0]), &header))
+	require.NotEmpty(t, header.SessionStart)
+
+	audioDumps, err := filepath.Glob(filepath.Join(xdgStateHome, "sotto", "debug", "audio-*.wav"))
+	require.NoError(t, err)
+	require.Len(t, audioDumps, 1)
+	wavData, err := os.ReadFile(audioDumps[0])
+	require.NoError(t, err)
+	require.Contains(t, string(wavData), "sotto session_start="+header.SessionStart+" capture_offset_ms=")
+}
+
+func TestSessionIDTagsLogsAndDebugArtifacts(t *testing.T) {
+	xdgStateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", xdgStateHome)
+
+	cfg := config.Default()
+	cfg.Debug.EnableAudioDump = true
+	cfg.Debug.EnableGRPCDump = true
+	var logs bytes.Buffer
+	transcriber := NewTranscriber(cfg, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	chunks := make(chan []byte)
+	close(chunks)
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}, Warning: "using fallback mic"}, nil
+	}
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		return &fakeStream{}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks, raw: []byte{0x01, 0x00}}, nil
+	}
+
+	ctx := session.WithID(context.Background(), "1a2b3c4d")
+	require.NoError(t, transcriber.Start(ctx))
+	require.NoError(t, transcriber.Cancel(ctx))
+
+	require.Contains(t, logs.String(), "using fallback mic")
+	require.Contains(t, logs.String(), "session_id=1a2b3c4d")
+
+	debugDir := filepath.Join(xdgStateHome, "sotto", "debug")
+	grpcDumps, err := filepath.Glob(filepath.Join(debugDir, "grpc-*-1a2b3c4d.json"))
+	require.NoError(t, err)
+	require.Len(t, grpcDumps, 1)
+	grpcData, err := os.ReadFile(grpcDumps[0])
+	require.NoError(t, err)
+	var header debugGRPCHeader
+	require.NoError(t, json.Unmarshal([]byte(strings.SplitN(string(grpcData), "\n", 2)[0]), &header))
+	require.Equal(t, "1a2b3c4d", header.SessionID)
+
+	audioDumps, err := filepath.Glob(filepath.Join(debugDir, "audio-*-1a2b3c4d.wav"))
+	require.NoError(t, err)
+	require.Len(t, audioDumps, 1)
+}
+
 func TestWriteDebugAudioCreatesWavWhenEnabled(t *testing.T) {
 	xdgStateHome := t.TempDir()
 	t.Setenv("XDG_STATE_HOME", xdgStateHome)
@@ -207,7 +370,205 @@ func TestStartWiresDependenciesAndBootsSendLoop(t *testing.T) {
 	require.True(t, transcriber.started)
 	require.Equal(t, "mic-1", transcriber.selection.Device.ID)
 	require.NotNil(t, transcriber.sendErrCh)
+	require.False(t, transcriber.UsingFallbackDevice())
+
+	require.NoError(t, transcriber.Cancel(context.Background()))
+}
+
+func TestStartAddsNumericSequenceBoostWhenSpokenDigitsEnabled(t *testing.T) {
+	cfg := config.Default()
+	cfg.ASR.SpokenDigits = true
+	transcriber := NewTranscriber(cfg, nil)
+
+	chunks := make(chan []byte)
+	close(chunks)
+	var got riva.StreamConfig
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+	}
+	transcriber.dialStream = func(_ context.Context, streamCfg riva.StreamConfig) (streamClient, error) {
+		got = streamCfg
+		return &fakeStream{}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks}, nil
+	}
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.Contains(t, got.SpeechPhrases, spokenDigitsPhrase)
+	require.NoError(t, transcriber.Cancel(context.Background()))
+}
+
+func TestStartPassesPunctuationOverrideToStream(t *testing.T) {
+	for _, punctuation := range []bool{true, false} {
+		cfg := config.Default()
+		cfg.ASR.AutomaticPunctuation = punctuation
+		transcriber := NewTranscriber(cfg, nil)
+
+		chunks := make(chan []byte)
+		close(chunks)
+		var got riva.StreamConfig
+		transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+			return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+		}
+		transcriber.dialStream = func(_ context.Context, streamCfg riva.StreamConfig) (streamClient, error) {
+			got = streamCfg
+			return &fakeStream{}, nil
+		}
+		transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+			return &fakeCapture{chunks: chunks}, nil
+		}
+
+		require.NoError(t, transcriber.Start(context.Background()))
+		require.Equal(t, punctuation, got.AutomaticPunctuation)
+		require.NoError(t, transcriber.Cancel(context.Background()))
+	}
+}
+
+func TestConfigureSessionAppliesForwardedOverridesToStream(t *testing.T) {
+	cfg := config.Default()
+	cfg.ASR.AutomaticPunctuation = true
+	transcriber := NewTranscriber(cfg, nil)
+
+	var got riva.StreamConfig
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+	}
+	transcriber.dialStream = func(_ context.Context, streamCfg riva.StreamConfig) (streamClient, error) {
+		got = streamCfg
+		return &fakeStream{}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		chunks := make(chan []byte)
+		close(chunks)
+		return &fakeCapture{chunks: chunks}, nil
+	}
+
+	require.NoError(t, transcriber.ConfigureSession(&ipc.SessionOverrides{
+		Punctuation: "off",
+		Model:       "canary-1b",
+		Phrases:     []ipc.SessionPhrase{{Phrase: "Kubernetes", Boost: 15}},
+	}))
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.False(t, got.AutomaticPunctuation)
+	require.Equal(t, "canary-1b", got.Model)
+	require.Contains(t, got.SpeechPhrases, riva.SpeechPhrase{Phrase: "Kubernetes", Boost: 15})
+	require.Error(t, transcriber.ConfigureSession(nil), "a running session keeps its config")
+	require.NoError(t, transcriber.Cancel(context.Background()))
+
+	require.NoError(t, transcriber.ConfigureSession(nil))
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.True(t, got.AutomaticPunctuation)
+	require.Equal(t, cfg.ASR.Model, got.Model)
+	require.NoError(t, transcriber.Cancel(context.Background()))
+
+	require.ErrorContains(t, transcriber.ConfigureSession(&ipc.SessionOverrides{Vocab: []string{"missing"}}), `unknown set "missing"`)
+}
+
+func TestStartPassesModelAndVocabOverridesToStream(t *testing.T) {
+	cfg := config.Default()
+	cfg.ASR.Model = "canary-1b"
+	cfg.Vocab.GlobalSets = []string{"setB"}
+	cfg.Vocab.Sets["setA"] = config.VocabSet{Name: "setA", Boost: 10, Phrases: []string{"alpha"}}
+	cfg.Vocab.Sets["setB"] = config.VocabSet{Name: "setB", Boost: 12, Phrases: []string{"beta"}}
+	transcriber := NewTranscriber(cfg, nil)
+
+	chunks := make(chan []byte)
+	close(chunks)
+	var got riva.StreamConfig
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+	}
+	transcriber.dialStream = func(_ context.Context, streamCfg riva.StreamConfig) (streamClient, error) {
+		got = streamCfg
+		return &fakeStream{}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks}, nil
+	}
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.Equal(t, "canary-1b", got.Model)
+	require.Equal(t, []riva.SpeechPhrase{{Phrase: "beta", Boost: 12}}, got.SpeechPhrases)
+	require.NoError(t, transcriber.Cancel(context.Background()))
+}
+
+func TestStartSelectsFLACEncodingWhenConfigured(t *testing.T) {
+	cfg := config.Default()
+	cfg.ASR.Encoding = "flac"
+	transcriber := NewTranscriber(cfg, nil)
+
+	chunks := make(chan []byte, 1)
+	chunks <- []byte{1, 0, 2, 0, 3, 0, 4, 0}
+	close(chunks)
+	var got riva.StreamConfig
+	stream := &fakeStream{}
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+	}
+	transcriber.dialStream = func(_ context.Context, streamCfg riva.StreamConfig) (streamClient, error) {
+		got = streamCfg
+		return stream, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks}, nil
+	}
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.NoError(t, <-transcriber.sendErrCh)
+	require.Equal(t, "flac", got.Encoding)
+	require.Len(t, stream.sendChunks, 1)
+	require.Equal(t, []byte("fLaC"), stream.sendChunks[0][:4])
+	require.NoError(t, transcriber.Cancel(context.Background()))
+}
+
+func TestStartFallsBackToLinearPCMWhenEncoderUnavailable(t *testing.T) {
+	cfg := config.Default()
+	cfg.ASR.Encoding = "flac"
+	transcriber := NewTranscriber(cfg, nil)
+
+	chunks := make(chan []byte)
+	close(chunks)
+	var got riva.StreamConfig
+	transcriber.newEncoder = func() (chunkEncoder, error) {
+		return nil, errors.New("no encoder")
+	}
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1"}}, nil
+	}
+	transcriber.dialStream = func(_ context.Context, streamCfg riva.StreamConfig) (streamClient, error) {
+		got = streamCfg
+		return &fakeStream{}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks}, nil
+	}
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.Empty(t, got.Encoding)
+	require.Nil(t, transcriber.encoder)
+	require.NoError(t, transcriber.Cancel(context.Background()))
+}
+
+func TestUsingFallbackDeviceReflectsSelection(t *testing.T) {
+	cfg := config.Default()
+	transcriber := NewTranscriber(cfg, nil)
+	require.False(t, transcriber.UsingFallbackDevice())
+
+	chunks := make(chan []byte)
+	close(chunks)
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-2"}, Fallback: true}, nil
+	}
+	transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+		return &fakeStream{}, nil
+	}
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks}, nil
+	}
 
+	require.NoError(t, transcriber.Start(context.Background()))
+	require.True(t, transcriber.UsingFallbackDevice())
 	require.NoError(t, transcriber.Cancel(context.Background()))
 }
 
@@ -234,9 +595,45 @@ func TestStartFailsOnSpeechPhraseBuildError(t *testing.T) {
 	require.False(t, transcriber.started)
 }
 
+func TestStartMarksOnlyTransientDialFailuresRecoverable(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		recoverable bool
+	}{
+		{name: "unavailable", err: fmt.Errorf("riva not reachable: %w", status.Error(codes.Unavailable, "connection refused")), recoverable: true},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, "timed out after 2s"), recoverable: true},
+		{name: "readiness timeout", err: fmt.Errorf("could not connect: %w", context.DeadlineExceeded), recoverable: true},
+		{name: "invalid argument", err: status.Error(codes.InvalidArgument, "unknown model")},
+		{name: "unauthenticated", err: status.Error(codes.Unauthenticated, "bad token")},
+		{name: "plain error", err: errors.New("riva endpoint is empty")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transcriber := NewTranscriber(config.Default(), nil)
+			transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+				return audio.Selection{Device: audio.Device{ID: "mic-1", Description: "Mic"}}, nil
+			}
+			transcriber.dialStream = func(context.Context, riva.StreamConfig) (streamClient, error) {
+				return nil, tc.err
+			}
+			transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+				t.Fatal("startCapture should not be called when dial fails")
+				return nil, nil
+			}
+
+			err := transcriber.Start(context.Background())
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.recoverable, session.IsRecoverable(err))
+			require.False(t, transcriber.started)
+		})
+	}
+}
+
 func TestStopAndTranscribeSuccessPath(t *testing.T) {
 	cfg := config.Default()
 	cfg.Transcript.TrailingSpace = true
+	cfg.ASR.Model = "parakeet-ctc"
 
 	capture := &fakeCapture{
 		chunks: make(chan []byte),
@@ -246,8 +643,10 @@ func TestStopAndTranscribeSuccessPath(t *testing.T) {
 	close(capture.chunks)
 
 	stream := &fakeStream{
-		closeSegments: []string{"hello", "world"},
+		closeSegments: []string{"hello"},
+		closeInterim:  "world",
 		closeLatency:  12 * time.Millisecond,
+		closeTrailer:  riva.Transcript{RequestID: "req-42", ModelVersion: "v2"},
 	}
 
 	transcriber := NewTranscriber(cfg, nil)
@@ -264,12 +663,168 @@ func TestStopAndTranscribeSuccessPath(t *testing.T) {
 	require.Equal(t, "Mic (mic-1)", result.AudioDevice)
 	require.Equal(t, int64(4096), result.BytesCaptured)
 	require.Equal(t, 12*time.Millisecond, result.GRPCLatency)
+	require.Equal(t, []string{"hello"}, result.CommittedSegments)
+	require.Equal(t, "world", result.InterimTail)
+	require.Equal(t, "parakeet-ctc", result.Model)
+	require.Equal(t, "en-US", result.LanguageCode)
+	require.Equal(t, "req-42", result.RequestID)
+	require.Equal(t, "v2", result.ModelVersion)
 	require.True(t, capture.stopCalled)
 	require.False(t, transcriber.started)
 	require.Nil(t, transcriber.capture)
 	require.Nil(t, transcriber.stream)
 }
 
+// finalRivaServer drains the audio stream and answers with one final
+// hypothesis, sent verbatim so tests control Riva's raw whitespace.
+type finalRivaServer struct {
+	asrpb.UnimplementedRivaSpeechRecognitionServer
+	final string
+}
+
+func (s finalRivaServer) StreamingRecognize(stream grpc.BidiStreamingServer[asrpb.StreamingRecognizeRequest, asrpb.StreamingRecognizeResponse]) error {
+	for {
+		_, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return stream.Send(&asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			IsFinal:      true,
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: s.final}},
+		}},
+	})
+}
+
+// transcribeWithRiva runs one session against a local Riva server that
+// answers with final, feeding a single fake audio chunk.
+func transcribeWithRiva(t *testing.T, cfg config.Config, final string) session.StopResult {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	asrpb.RegisterRivaSpeechRecognitionServer(server, finalRivaServer{final: final})
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	cfg.RivaGRPC = lis.Addr().String()
+	transcriber := NewTranscriber(cfg, nil)
+	transcriber.selectDevice = func(context.Context, string, string) (audio.Selection, error) {
+		return audio.Selection{Device: audio.Device{ID: "mic-1", Description: "Mic"}}, nil
+	}
+	chunks := make(chan []byte, 1)
+	chunks <- make([]byte, 320)
+	close(chunks)
+	transcriber.startCapture = func(context.Context, audio.Device) (captureClient, error) {
+		return &fakeCapture{chunks: chunks, bytes: 320}, nil
+	}
+
+	require.NoError(t, transcriber.Start(context.Background()))
+	result, err := transcriber.StopAndTranscribe(context.Background())
+	require.NoError(t, err)
+	return result
+}
+
+func TestStopAndTranscribeAppliesTrimPolicyToRecognizedEdges(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{policy: "both", want: "Hello world"},
+		{policy: "leading", want: "Hello world "},
+		{policy: "trailing", want: " Hello world"},
+		{policy: "none", want: " Hello world "},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.policy, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Transcript.TrailingSpace = false
+			cfg.Transcript.TrimPolicy = tc.policy
+
+			result := transcribeWithRiva(t, cfg, "  hello world ")
+			require.Equal(t, tc.want, result.Transcript)
+		})
+	}
+}
+
+func TestStopAndTranscribeKeepsRecognizedParagraphBreaks(t *testing.T) {
+	cfg := config.Default()
+	cfg.Transcript.TrailingSpace = false
+
+	result := transcribeWithRiva(t, cfg, "first  paragraph.\n\nsecond one")
+	require.Equal(t, "First paragraph.\n\nSecond one", result.Transcript)
+}
+
+func TestFlushAssemblesIncrementalSegments(t *testing.T) {
+	cfg := config.Default()
+	cfg.Transcript.TrailingSpace = true
+
+	stream := &fakeStream{flushBatches: [][]string{{"hello there."}, nil, {"second part"}}}
+	transcriber := NewTranscriber(cfg, nil)
+	transcriber.started = true
+	transcriber.stream = stream
+
+	first, err := transcriber.Flush(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Hello there. ", first)
+
+	empty, err := transcriber.Flush(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, empty)
+
+	second, err := transcriber.Flush(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Second part ", second)
+	require.True(t, transcriber.started)
+}
+
+func TestFlushCarriesSentenceCaseAcrossFlushes(t *testing.T) {
+	cfg := config.Default()
+	cfg.Transcript.TrailingSpace = false
+
+	transcriber := NewTranscriber(cfg, nil)
+	transcriber.started = true
+	transcriber.stream = &fakeStream{flushBatches: [][]string{{"so we ship it"}, {"after lunch. then rest."}, {"next one"}}}
+
+	for _, want := range []string{"So we ship it", "after lunch. Then rest.", "Next one"} {
+		got, err := transcriber.Flush(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+}
+
+func TestFlushAppliesSymbolWordsOnlyInCodeMode(t *testing.T) {
+	for _, codeMode := range []bool{false, true} {
+		cfg := config.Default()
+		cfg.Transcript.TrailingSpace = false
+		cfg.Transcript.Capitalize = "none"
+		cfg.Transcript.CodeMode = codeMode
+
+		transcriber := NewTranscriber(cfg, nil)
+		transcriber.started = true
+		transcriber.stream = &fakeStream{flushBatches: [][]string{{"open main dot go"}}}
+
+		got, err := transcriber.Flush(context.Background())
+		require.NoError(t, err)
+		if codeMode {
+			require.Equal(t, "open main.go", got)
+			continue
+		}
+		require.Equal(t, "open main dot go", got)
+	}
+}
+
+func TestFlushUnavailableWhenNotStarted(t *testing.T) {
+	_, err := NewTranscriber(config.Default(), nil).Flush(context.Background())
+	require.ErrorIs(t, err, session.ErrPipelineUnavailable)
+}
+
 func TestStopAndTranscribeSendErrorCancelsStream(t *testing.T) {
 	capture := &fakeCapture{
 		chunks: make(chan []byte),
@@ -294,6 +849,48 @@ func TestStopAndTranscribeSendErrorCancelsStream(t *testing.T) {
 	require.True(t, stream.cancelCalled)
 }
 
+func TestStopAndTranscribeWithNoAudioCaptured(t *testing.T) {
+	capture := &fakeCapture{chunks: make(chan []byte)}
+	close(capture.chunks)
+	stream := &fakeStream{}
+
+	transcriber := NewTranscriber(config.Default(), nil)
+	transcriber.started = true
+	transcriber.selection = audio.Selection{Device: audio.Device{ID: "mic-1", Description: "Mic"}}
+	transcriber.capture = capture
+	transcriber.stream = stream
+	transcriber.sendErrCh = make(chan error, 1)
+	transcriber.sendErrCh <- nil
+
+	result, err := transcriber.StopAndTranscribe(context.Background())
+	require.ErrorIs(t, err, session.ErrNoAudioCaptured)
+	require.Equal(t, "Mic (mic-1)", result.AudioDevice)
+	require.Empty(t, result.Transcript)
+	require.True(t, stream.cancelCalled)
+}
+
+func TestStopAndTranscribeImmediateStopStress(t *testing.T) {
+	for range 200 {
+		capture := &fakeCapture{chunks: make(chan []byte)}
+		close(capture.chunks) // Stop before any PCM arrived
+		stream := &fakeStream{}
+
+		transcriber := NewTranscriber(config.Default(), nil)
+		transcriber.started = true
+		transcriber.capture = capture
+		transcriber.stream = stream
+		transcriber.sendErrCh = make(chan error, 1)
+		go transcriber.sendLoop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		_, err := transcriber.StopAndTranscribe(ctx)
+		cancel()
+		require.ErrorIs(t, err, session.ErrNoAudioCaptured)
+		require.Empty(t, stream.sendChunks)
+		require.True(t, stream.cancelCalled)
+	}
+}
+
 func TestStopAndTranscribeCollectErrorIncludesLatency(t *testing.T) {
 	capture := &fakeCapture{
 		chunks: make(chan []byte),
@@ -318,6 +915,37 @@ func TestStopAndTranscribeCollectErrorIncludesLatency(t *testing.T) {
 	require.Equal(t, 77*time.Millisecond, result.GRPCLatency)
 }
 
+func TestStopAndTranscribeAbortsSlowCollectOnContextCancel(t *testing.T) {
+	capture := &fakeCapture{
+		chunks: make(chan []byte),
+		raw:    []byte{1, 2, 3, 4},
+		bytes:  1024,
+	}
+	close(capture.chunks)
+
+	stream := &fakeStream{closeBlock: make(chan struct{}), closeSegments: []string{"never committed"}}
+	defer close(stream.closeBlock)
+
+	transcriber := NewTranscriber(config.Default(), nil)
+	transcriber.started = true
+	transcriber.selection = audio.Selection{Device: audio.Device{ID: "mic-1", Description: "Mic"}}
+	transcriber.capture = capture
+	transcriber.stream = stream
+	transcriber.sendErrCh = make(chan error, 1)
+	transcriber.sendErrCh <- nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(30*time.Millisecond, cancel)
+
+	began := time.Now()
+	result, err := transcriber.StopAndTranscribe(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(began), time.Second)
+	require.Empty(t, result.Transcript)
+	require.Equal(t, "Mic (mic-1)", result.AudioDevice)
+	require.False(t, transcriber.started)
+}
+
 func TestCancelStopsCaptureAndStreamAndResetsState(t *testing.T) {
 	capture := &fakeCapture{chunks: make(chan []byte), raw: []byte{1}, bytes: 1}
 	close(capture.chunks)
@@ -382,11 +1010,67 @@ func TestSendLoopStopsCaptureOnSendError(t *testing.T) {
 	require.True(t, capture.stopCalled)
 }
 
+func TestSendLoopReportsEncodeError(t *testing.T) {
+	chunks := make(chan []byte, 1)
+	chunks <- []byte{1, 2, 3}
+	close(chunks)
+
+	capture := &fakeCapture{chunks: chunks}
+	stream := &fakeStream{}
+	transcriber := NewTranscriber(config.Default(), nil)
+	transcriber.capture = capture
+	transcriber.stream = stream
+	transcriber.encoder, _ = audio.NewFLACEncoder(16000)
+	transcriber.sendErrCh = make(chan error, 1)
+
+	transcriber.sendLoop()
+
+	err := <-transcriber.sendErrCh
+	require.ErrorContains(t, err, "encode flac")
+	require.Empty(t, stream.sendChunks)
+	require.True(t, capture.stopCalled)
+}
+
+func TestStopAndTranscribeAfterPulseDisconnect(t *testing.T) {
+	for _, commitOnDisconnect := range []bool{false, true} {
+		cfg := config.Default()
+		cfg.Transcript.TrailingSpace = false
+		cfg.Audio.CommitOnDisconnect = commitOnDisconnect
+
+		chunks := make(chan []byte, 1)
+		chunks <- []byte{1, 2}
+		close(chunks)
+		capture := &fakeCapture{chunks: chunks, bytes: 2, err: audio.ErrDisconnected}
+		stream := &fakeStream{closeSegments: []string{"partial words"}}
+
+		transcriber := NewTranscriber(cfg, nil)
+		transcriber.started = true
+		transcriber.capture = capture
+		transcriber.stream = stream
+		transcriber.sendErrCh = make(chan error, 1)
+		transcriber.sendLoop()
+
+		result, err := transcriber.StopAndTranscribe(context.Background())
+		require.Len(t, stream.sendChunks, 1)
+		if commitOnDisconnect {
+			require.NoError(t, err)
+			require.Equal(t, "Partial words", result.Transcript)
+			require.False(t, stream.cancelCalled)
+			continue
+		}
+		require.ErrorIs(t, err, audio.ErrDisconnected)
+		require.Contains(t, err.Error(), "capture interrupted")
+		require.Empty(t, result.Transcript)
+		require.True(t, stream.cancelCalled)
+	}
+}
+
 type fakeCapture struct {
 	chunks     chan []byte
 	stopErr    error
 	raw        []byte
 	bytes      int64
+	err        error
 	stopCalled bool
 }
 
@@ -399,6 +1083,8 @@ func (f *fakeCapture) Chunks() <-chan []byte { return f.chunks }
 
 func (f *fakeCapture) BytesCaptured() int64 { return f.bytes }
 
+func (f *fakeCapture) Err() error { return f.err }
+
 func (f *fakeCapture) RawPCM() []byte {
 	out := make([]byte, len(f.raw))
 	copy(out, f.raw)
@@ -409,9 +1095,18 @@ type fakeStream struct {
 	sendErr       error
 	closeErr      error
 	closeSegments []string
+	closeInterim  string
 	closeLatency  time.Duration
-	cancelCalled  bool
-	sendChunks    [][]byte
+	closeTrailer  riva.Transcript // only RequestID and ModelVersion are used
+	// closeBlock, when set, holds the collect open until it closes or the
+	// context ends.
+	closeBlock   chan struct{}
+	cancelCalled bool
+	sendChunks   [][]byte
+	flushBatches [][]string
+
+	interimMu sync.Mutex
+	interim   string
 }
 
 func (f *fakeStream) SendAudio(chunk []byte) error {
@@ -424,12 +1119,44 @@ func (f *fakeStream) SendAudio(chunk []byte) error {
 	return nil
 }
 
-func (f *fakeStream) CloseAndCollect(context.Context) ([]string, time.Duration, error) {
+func (f *fakeStream) CloseAndCollectTranscript(ctx context.Context) (riva.Transcript, time.Duration, error) {
+	if f.closeBlock != nil {
+		select {
+		case <-f.closeBlock:
+		case <-ctx.Done():
+			return riva.Transcript{}, f.closeLatency, ctx.Err()
+		}
+	}
 	if f.closeErr != nil {
-		return nil, f.closeLatency, f.closeErr
+		return riva.Transcript{}, f.closeLatency, f.closeErr
+	}
+	return riva.Transcript{
+		Committed:    append([]string(nil), f.closeSegments...),
+		Interim:      f.closeInterim,
+		RequestID:    f.closeTrailer.RequestID,
+		ModelVersion: f.closeTrailer.ModelVersion,
+	}, f.closeLatency, nil
+}
+
+func (f *fakeStream) FlushSegments() []string {
+	if len(f.flushBatches) == 0 {
+		return nil
 	}
-	segments := append([]string(nil), f.closeSegments...)
-	return segments, f.closeLatency, nil
+	batch := f.flushBatches[0]
+	f.flushBatches = f.flushBatches[1:]
+	return batch
+}
+
+func (f *fakeStream) InterimSnapshot() string {
+	f.interimMu.Lock()
+	defer f.interimMu.Unlock()
+	return f.interim
+}
+
+func (f *fakeStream) setInterim(text string) {
+	f.interimMu.Lock()
+	defer f.interimMu.Unlock()
+	f.interim = text
 }
 
 func (f *fakeStream) Cancel() error {
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +15,8 @@ import (
 	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 )
 
 // SpeechPhrase is one vocabulary boost phrase in request-ready form.
@@ -27,11 +31,39 @@ type StreamConfig struct {
 	LanguageCode          string
 	Model                 string
 	AutomaticPunctuation  bool
+	Verbatim              bool // request verbatim transcripts (no server-side ITN)
 	SpeechPhrases         []SpeechPhrase
+	Encoding              string // "flac" or "linear_pcm" (default)
 	DialTimeout           time.Duration
+	MaxRecvMsgBytes       int
+	MaxSendMsgBytes       int
+	KeepaliveInterval     time.Duration
 	DebugResponseSinkJSON io.Writer
+	// Logger receives debug-level interim merge decisions; nil disables them.
+	Logger *slog.Logger
+	// InterimMaxAge is the interim chain length that commits on divergence;
+	// zero uses the built-in default.
+	InterimMaxAge int
+	// FinalDedupeWindow is how many recent finals a new final of at least
+	// minRepeatedSegmentWords words is checked against for repeats; zero
+	// disables the check.
+	FinalDedupeWindow int
+	// InterimFuzzyThreshold treats a diverging interim as a rewrite of the
+	// previous one when their word edit distance ratio is at most this value;
+	// zero disables the fuzzy check.
+	InterimFuzzyThreshold float64
+	// DebugOrigin is the time debug dump offsets are measured from; zero uses
+	// the moment the stream opens.
+	DebugOrigin time.Time
+	// FirstResponseTimeout fails the stream with ErrNoResponse when Riva sends
+	// nothing back this long after the first audio chunk; zero disables it.
+	FirstResponseTimeout time.Duration
 }
 
+// ErrNoResponse reports that Riva accepted audio but never answered, which
+// usually means the model is missing or not serving.
+var ErrNoResponse = errors.New("riva produced no results; check model")
+
 // Stream wraps one active Riva StreamingRecognize RPC lifecycle.
 type Stream struct {
 	conn   *grpc.ClientConn
@@ -43,99 +75,71 @@ type Stream struct {
 
 	mu                        sync.Mutex
 	segments                  []string // committed transcript segments (final results and sealed interim chains)
+	flushedSegments           int      // leading segments already returned by FlushSegments
+	flushedLast               string   // the last flushed segment as it was returned
 	lastInterim               string
 	lastInterimAge            int
+	interimMaxAge             int      // chain length that commits on divergence; 0 uses the default
+	finalDedupeWindow         int      // recent finals checked for repeats; 0 disables
+	interimFuzzyThreshold     float64  // max word edit distance ratio for a fuzzy continuation; 0 disables
+	recentFinals              []uint64 // normalized hashes of the last finalDedupeWindow finals
 	lastInterimStability      float32
 	lastInterimAudioProcessed float32
+	invalidUTF8               int       // hypotheses that carried invalid UTF-8 bytes
+	leadingSpace              bool      // the first hypothesis began with whitespace
+	trailingSpace             bool      // the latest hypothesis ended with whitespace
+	firstResultAt             time.Time // arrival of the first non-empty hypothesis
 	recvErr                   error
+	trailer                   metadata.MD // RPC trailers, set once Recv ends
+	gotResponse               bool
+	firstResponseTimeout      time.Duration
+	watchdog                  *time.Timer // armed by the first SendAudio; nil until then
 	closedSend                bool
 	debugSinkJSON             io.Writer
+	logger                    *slog.Logger
+	startedAt                 time.Time
 }
 
 // DialStream establishes a stream, sends config, and starts the receive loop.
 func DialStream(ctx context.Context, cfg StreamConfig) (*Stream, error) {
-	endpoint := strings.TrimSpace(cfg.Endpoint)
-	if endpoint == "" {
-		return nil, errors.New("riva endpoint is empty")
-	}
-	if cfg.DialTimeout <= 0 {
-		cfg.DialTimeout = 3 * time.Second
-	}
-	if strings.TrimSpace(cfg.LanguageCode) == "" {
-		cfg.LanguageCode = "en-US"
-	}
-
-	conn, err := grpc.NewClient(
-		endpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := DialConn(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("dial riva grpc %q: %w", endpoint, err)
+		return nil, err
 	}
+	return conn.OpenStream(ctx, cfg)
+}
 
-	readyCtx, cancel := context.WithTimeout(ctx, cfg.DialTimeout)
-	defer cancel()
-	conn.Connect()
-	if err := waitForReady(readyCtx, conn); err != nil {
-		_ = conn.Close()
-		return nil, fmt.Errorf("wait for riva grpc readiness: %w", err)
-	}
+// dialOptions builds client options; non-positive message limits keep gRPC defaults.
+func dialOptions(cfg StreamConfig) []grpc.DialOption {
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 
-	streamCtx, streamCancel := context.WithCancel(ctx)
-	client := asrpb.NewRivaSpeechRecognitionClient(conn)
-	stream, err := openRecognizeWithTimeout(streamCtx, cfg.DialTimeout, func() (asrpb.RivaSpeechRecognition_StreamingRecognizeClient, error) {
-		return client.StreamingRecognize(streamCtx)
-	})
-	if err != nil {
-		streamCancel()
-		_ = conn.Close()
-		return nil, fmt.Errorf("open streaming recognizer: %w", err)
-	}
-
-	req := &asrpb.StreamingRecognizeRequest{
-		StreamingRequest: &asrpb.StreamingRecognizeRequest_StreamingConfig{
-			StreamingConfig: &asrpb.StreamingRecognitionConfig{
-				Config: &asrpb.RecognitionConfig{
-					Encoding:                   asrpb.AudioEncoding_LINEAR_PCM,
-					SampleRateHertz:            16000,
-					LanguageCode:               cfg.LanguageCode,
-					EnableAutomaticPunctuation: cfg.AutomaticPunctuation,
-					AudioChannelCount:          1,
-					Model:                      strings.TrimSpace(cfg.Model),
-				},
-				InterimResults: true,
-			},
-		},
-	}
-
-	for _, phrase := range cfg.SpeechPhrases {
-		phraseText := strings.TrimSpace(phrase.Phrase)
-		if phraseText == "" {
-			continue
-		}
-		req.GetStreamingConfig().GetConfig().SpeechContexts = append(
-			req.GetStreamingConfig().GetConfig().SpeechContexts,
-			&asrpb.SpeechContext{Phrases: []string{phraseText}, Boost: phrase.Boost},
-		)
+	callOpts := make([]grpc.CallOption, 0, 2)
+	if cfg.MaxRecvMsgBytes > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(cfg.MaxRecvMsgBytes))
 	}
-
-	if err := runWithTimeout(streamCtx, cfg.DialTimeout, func() error {
-		return stream.Send(req)
-	}); err != nil {
-		streamCancel()
-		_ = conn.Close()
-		return nil, fmt.Errorf("send initial streaming config: %w", err)
+	if cfg.MaxSendMsgBytes > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(cfg.MaxSendMsgBytes))
+	}
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
 	}
+	if params, ok := keepaliveParams(cfg.KeepaliveInterval); ok {
+		opts = append(opts, grpc.WithKeepaliveParams(params))
+	}
+	return opts
+}
 
-	s := &Stream{
-		conn:          conn,
-		stream:        stream,
-		cancel:        streamCancel,
-		recvDone:      make(chan struct{}),
-		debugSinkJSON: cfg.DebugResponseSinkJSON,
+// keepaliveParams pings only while a stream is active so idle clients do not
+// trip server-side ping enforcement.
+func keepaliveParams(interval time.Duration) (keepalive.ClientParameters, bool) {
+	if interval <= 0 {
+		return keepalive.ClientParameters{}, false
 	}
-	go s.recvLoop()
-	return s, nil
+	return keepalive.ClientParameters{
+		Time:                interval,
+		Timeout:             20 * time.Second,
+		PermitWithoutStream: false,
+	}, true
 }
 
 // SendAudio sends one chunk of PCM audio over the active stream.
@@ -156,13 +160,62 @@ func (s *Stream) SendAudio(chunk []byte) error {
 		return fmt.Errorf("stream receive loop failed: %w", recvErr)
 	}
 
-	return s.stream.Send(&asrpb.StreamingRecognizeRequest{
+	if err := s.stream.Send(&asrpb.StreamingRecognizeRequest{
 		StreamingRequest: &asrpb.StreamingRecognizeRequest_AudioContent{AudioContent: chunk},
-	})
+	}); err != nil {
+		return err
+	}
+	s.armWatchdog()
+	return nil
+}
+
+// armWatchdog starts the first-response timer once audio is flowing.
+func (s *Stream) armWatchdog() {
+	if s.firstResponseTimeout <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchdog != nil || s.gotResponse {
+		return
+	}
+	s.watchdog = time.AfterFunc(s.firstResponseTimeout, s.expireWatchdog)
+}
+
+// expireWatchdog fails the stream when no response arrived in time. Cancelling
+// the RPC unblocks recvLoop, so a pending collect returns immediately.
+func (s *Stream) expireWatchdog() {
+	s.mu.Lock()
+	if s.gotResponse || s.recvErr != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.recvErr = fmt.Errorf("%w (no response within %s)", ErrNoResponse, s.firstResponseTimeout)
+	s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// stopWatchdog disarms the first-response timer; callers must hold s.mu.
+func (s *Stream) stopWatchdog() {
+	if s.watchdog != nil {
+		s.watchdog.Stop()
+	}
 }
 
 // CloseAndCollect closes send-side audio and returns merged transcript segments.
 func (s *Stream) CloseAndCollect(ctx context.Context) ([]string, time.Duration, error) {
+	collected, latency, err := s.CloseAndCollectTranscript(ctx)
+	if err != nil {
+		return nil, latency, err
+	}
+	return collected.Segments(), latency, nil
+}
+
+// CloseAndCollectTranscript closes send-side audio and returns committed
+// segments separately from the trailing interim hypothesis.
+func (s *Stream) CloseAndCollectTranscript(ctx context.Context) (Transcript, time.Duration, error) {
 	closedAt := time.Now()
 
 	s.mu.Lock()
@@ -175,16 +228,20 @@ func (s *Stream) CloseAndCollect(ctx context.Context) ([]string, time.Duration,
 	select {
 	case <-s.recvDone:
 	case <-ctx.Done():
+		s.mu.Lock()
+		s.stopWatchdog()
+		s.mu.Unlock()
 		if s.cancel != nil {
 			s.cancel()
 		}
 		_ = s.conn.Close()
-		return nil, 0, ctx.Err()
+		return Transcript{}, 0, ctx.Err()
 	}
 	latency := time.Since(closedAt)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.stopWatchdog()
 	defer func() {
 		if s.cancel != nil {
 			s.cancel()
@@ -192,12 +249,78 @@ func (s *Stream) CloseAndCollect(ctx context.Context) ([]string, time.Duration,
 		_ = s.conn.Close()
 	}()
 
+	requestID := trailerValue(s.trailer, requestIDTrailerKeys)
 	if s.recvErr != nil {
-		return nil, latency, s.recvErr
+		err := explainStatus(s.recvErr)
+		if requestID != "" {
+			err = fmt.Errorf("%w (riva request id %s)", err, requestID)
+		}
+		return Transcript{}, latency, err
+	}
+
+	committed := s.pendingLocked("")
+	interim := cleanSegment(s.lastInterim)
+	if s.flushedSegments > 0 {
+		// Keep only what the interim adds beyond the flushed text, so a
+		// repeat or extension of it is not committed twice.
+		merged := s.pendingLocked(interim)
+		switch {
+		case len(merged) > len(committed):
+			interim = merged[len(merged)-1]
+		case slices.Equal(merged, committed):
+			interim = ""
+		}
+	}
+
+	return Transcript{
+		Committed:     committed,
+		Interim:       interim,
+		InvalidUTF8:   s.invalidUTF8,
+		RequestID:     requestID,
+		ModelVersion:  trailerValue(s.trailer, modelVersionTrailerKeys),
+		LeadingSpace:  s.leadingSpace,
+		TrailingSpace: s.trailingSpace,
+	}, latency, nil
+}
+
+// FlushSegments returns the committed segments received since the previous
+// flush. The trailing interim hypothesis is kept: Riva may still revise it,
+// so it belongs to a later flush or the close. Flushed segments stay in the
+// stream as merge context, so a later repeat or extension of them only
+// contributes its new words.
+func (s *Stream) FlushSegments() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flushed := s.pendingLocked("")
+	if len(s.segments) > 0 {
+		s.flushedSegments = len(s.segments)
+		s.flushedLast = s.segments[len(s.segments)-1]
 	}
+	return flushed
+}
+
+// pendingLocked returns the segments not yet flushed, with interim merged in
+// against the full segment history. Callers hold s.mu.
+func (s *Stream) pendingLocked(interim string) []string {
+	return unflushedSegments(collectSegments(s.segments, interim), s.flushedSegments, s.flushedLast)
+}
 
-	segments := collectSegments(s.segments, s.lastInterim)
-	return segments, latency, nil
+// FirstResultAt reports when the first non-empty hypothesis (interim or
+// final) arrived, or the zero time when none has.
+func (s *Stream) FirstResultAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.firstResultAt
+}
+
+// InterimSnapshot returns the text recognized so far in this stream: segments
+// not yet flushed followed by the trailing interim hypothesis. It is a display
+// preview only; Riva may still revise the interim part.
+func (s *Stream) InterimSnapshot() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.pendingLocked(s.lastInterim), " ")
 }
 
 // Cancel aborts stream processing and closes the underlying grpc connection.
@@ -207,6 +330,7 @@ func (s *Stream) Cancel() error {
 		s.closedSend = true
 		_ = s.stream.CloseSend()
 	}
+	s.stopWatchdog()
 	s.mu.Unlock()
 	if s.cancel != nil {
 		s.cancel()
@@ -3,9 +3,12 @@ package riva
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +16,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -60,6 +65,114 @@ func TestRecordResponseTracksInterimThenFinal(t *testing.T) {
 	require.Equal(t, []string{"hello world"}, s.segments)
 }
 
+func TestFlushSegmentsReturnsOnlyNewSegments(t *testing.T) {
+	s := &Stream{}
+	final := func(text string) *asrpb.StreamingRecognizeResponse {
+		return &asrpb.StreamingRecognizeResponse{
+			Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      true,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+			}},
+		}
+	}
+
+	s.recordResponse(final("first sentence"))
+	s.recordResponse(&asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "still talk"}},
+		}},
+	})
+
+	require.Equal(t, []string{"first sentence"}, s.FlushSegments())
+	require.Equal(t, "still talk", s.lastInterim)
+	require.Equal(t, "still talk", s.InterimSnapshot())
+	require.Empty(t, s.FlushSegments())
+
+	s.recordResponse(final("still talking here"))
+	require.Equal(t, []string{"still talking here"}, s.FlushSegments())
+}
+
+func TestFlushSegmentsKeepsMergeContext(t *testing.T) {
+	s := &Stream{}
+	final := func(text string) *asrpb.StreamingRecognizeResponse {
+		return &asrpb.StreamingRecognizeResponse{
+			Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      true,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+			}},
+		}
+	}
+
+	s.recordResponse(final("ship the release today"))
+	require.Equal(t, []string{"ship the release today"}, s.FlushSegments())
+
+	// A replayed final is still recognized as a repeat after the flush.
+	s.recordResponse(final("ship the release today"))
+	require.Empty(t, s.FlushSegments())
+
+	// An extension of flushed text only contributes its new words.
+	s.recordResponse(final("ship the release today and tag it"))
+	require.Equal(t, []string{"and tag it"}, s.FlushSegments())
+
+	s.recordResponse(&asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "ship the release today and tag it please"}},
+		}},
+	})
+	require.Equal(t, "please", s.InterimSnapshot())
+}
+
+func TestInterimSnapshotJoinsCommittedAndInterim(t *testing.T) {
+	s := &Stream{}
+	require.Empty(t, s.InterimSnapshot())
+
+	s.recordResponse(&asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			IsFinal:      true,
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "first sentence"}},
+		}},
+	})
+	s.recordResponse(&asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "still talk"}},
+		}},
+	})
+
+	require.Equal(t, "first sentence still talk", s.InterimSnapshot())
+	require.Equal(t, []string{"first sentence"}, s.segments, "snapshot must not consume segments")
+}
+
+func TestRecordResponseStripsInvalidUTF8(t *testing.T) {
+	s := &Stream{}
+
+	s.recordResponse(&asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			IsFinal:      false,
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "caf\xc3"}},
+		}},
+	})
+	require.Equal(t, "caf", s.lastInterim)
+
+	s.recordResponse(&asrpb.StreamingRecognizeResponse{
+		Results: []*asrpb.StreamingRecognitionResult{{
+			IsFinal:      true,
+			Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "caf\xc3\xa9 \xe2\x82 ok"}},
+		}},
+	})
+	require.Equal(t, []string{"café ok"}, s.segments)
+	require.Equal(t, 2, s.invalidUTF8)
+}
+
+func TestCleanSegmentDropsInvalidOnlyInput(t *testing.T) {
+	require.Empty(t, cleanSegment("\xff\xfe"))
+	require.Equal(t, "hello", cleanSegment(" \xffhello\x80 "))
+}
+
+func TestCleanSegmentKeepsLineBreaks(t *testing.T) {
+	require.Equal(t, "one two\n\nthree", cleanSegment("\n one \t two \r\n\r\n three \n"))
+	require.Equal(t, "a\nb", cleanSegment("a\rb"))
+}
+
 func TestRecordResponseReplacesDivergentInterimWithoutPrecommit(t *testing.T) {
 	s := &Stream{}
 
@@ -82,6 +195,62 @@ func TestRecordResponseReplacesDivergentInterimWithoutPrecommit(t *testing.T) {
 	require.Equal(t, []string{"second phrase"}, segments)
 }
 
+func TestRecordResponseDropsRepeatedFinalAcrossInterims(t *testing.T) {
+	final := func(text string) *asrpb.StreamingRecognizeResponse {
+		return &asrpb.StreamingRecognizeResponse{
+			Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      true,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+			}},
+		}
+	}
+	stableInterim := func(text string) *asrpb.StreamingRecognizeResponse {
+		return &asrpb.StreamingRecognizeResponse{
+			Results: []*asrpb.StreamingRecognitionResult{{
+				Stability:    0.9,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+			}},
+		}
+	}
+	// final, then an interim committed by a diverging interim, then the same
+	// final again: appendSegment alone no longer sees the repeat as the tail.
+	replay := func(s *Stream) {
+		s.recordResponse(final("Hello there friend."))
+		s.recordResponse(stableInterim("uh huh"))
+		s.recordResponse(stableInterim("okay so"))
+		s.recordResponse(final("hello there friend"))
+	}
+
+	withoutDedupe := &Stream{}
+	replay(withoutDedupe)
+	require.Equal(t, []string{"Hello there friend.", "uh huh", "hello there friend"}, withoutDedupe.segments)
+
+	withDedupe := &Stream{finalDedupeWindow: 4}
+	replay(withDedupe)
+	require.Equal(t, []string{"Hello there friend.", "uh huh"}, withDedupe.segments)
+	require.Empty(t, withDedupe.lastInterim)
+
+	// Short finals like "Yes." are legitimately repeated and never dropped.
+	short := &Stream{finalDedupeWindow: 4}
+	short.recordResponse(final("Yes."))
+	short.recordResponse(stableInterim("uh huh"))
+	short.recordResponse(stableInterim("okay so"))
+	short.recordResponse(final("Yes."))
+	require.Equal(t, []string{"Yes.", "uh huh", "Yes."}, short.segments)
+}
+
+func TestRepeatsRecentFinalForgetsFinalsOutsideWindow(t *testing.T) {
+	s := &Stream{finalDedupeWindow: 2}
+
+	require.False(t, s.repeatsRecentFinal("alpha bravo charlie"))
+	require.False(t, s.repeatsRecentFinal("beta gamma delta"))
+	require.True(t, s.repeatsRecentFinal("Alpha, bravo charlie!"))
+	require.False(t, s.repeatsRecentFinal("echo foxtrot golf"))
+	// The window now holds the last two finals; "beta gamma delta" aged out.
+	require.False(t, s.repeatsRecentFinal("beta gamma delta"))
+	require.Len(t, s.recentFinals, 2)
+}
+
 func TestRecordResponseCommitsStableSingleInterimOnDivergence(t *testing.T) {
 	s := &Stream{}
 
@@ -185,6 +354,43 @@ func TestRecordResponseCommitsInterimChainOnDivergence(t *testing.T) {
 	require.Equal(t, []string{"first phrase extended", "second phrase"}, segments)
 }
 
+func TestTranscriptSeparatesCommittedFromInterimTail(t *testing.T) {
+	s := &Stream{}
+	for _, text := range []string{"first phrase", "first phrase extended", "second phrase"} {
+		s.recordResponse(&asrpb.StreamingRecognizeResponse{
+			Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      false,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+			}},
+		})
+	}
+
+	collected := Transcript{Committed: s.segments, Interim: s.lastInterim}
+	require.Equal(t, []string{"first phrase extended"}, collected.Committed)
+	require.Equal(t, "second phrase", collected.Interim)
+	require.Equal(t, []string{"first phrase extended", "second phrase"}, collected.Segments())
+}
+
+func TestRecordResponseTracksEdgeWhitespace(t *testing.T) {
+	s := &Stream{}
+	for _, result := range []struct {
+		text  string
+		final bool
+	}{{" hello", false}, {"hello world ", true}} {
+		s.recordResponse(&asrpb.StreamingRecognizeResponse{
+			Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      result.final,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: result.text}},
+			}},
+		})
+	}
+
+	require.Equal(t, []string{"hello world"}, s.segments)
+	collected := Transcript{Committed: s.segments, LeadingSpace: s.leadingSpace, TrailingSpace: s.trailingSpace}
+	require.Equal(t, []string{" hello world "}, collected.Segments())
+	require.Empty(t, Transcript{LeadingSpace: true, TrailingSpace: true}.Segments())
+}
+
 func TestRecordResponseBuildsMultipleSegmentsAcrossLongInterimStream(t *testing.T) {
 	s := &Stream{}
 
@@ -270,8 +476,23 @@ func TestAppendSegmentDedupAndPrefixMerge(t *testing.T) {
 	require.Equal(t, []string{"hello world", "new sentence"}, segments)
 }
 
+func TestAppendSegmentDropsRepeatOfRecentSegment(t *testing.T) {
+	segments := []string{"first thing to say", "second thing to say", "third one here"}
+
+	segments = appendSegment(segments, " second  thing to say ")
+	require.Equal(t, []string{"first thing to say", "second thing to say", "third one here"}, segments)
+
+	// Short repeats are plausible speech ("yes ... yes") and are kept.
+	segments = appendSegment([]string{"yes", "no"}, "yes")
+	require.Equal(t, []string{"yes", "no", "yes"}, segments)
+
+	// Repeats older than the window are treated as new speech.
+	old := []string{"way back then", "a", "b", "c", "d"}
+	require.Equal(t, append(append([]string(nil), old...), "way back then"), appendSegment(old, "way back then"))
+}
+
 func TestInterimHelpers(t *testing.T) {
-	require.Equal(t, "hello world", cleanSegment("  hello\n world  "))
+	require.Equal(t, "hello\nworld", cleanSegment("  hello\n world  "))
 	require.Empty(t, cleanSegment("   \n\t"))
 
 	continuationCases := []struct {
@@ -288,17 +509,123 @@ func TestInterimHelpers(t *testing.T) {
 	}
 	for _, tc := range continuationCases {
 		t.Run(tc.name, func(t *testing.T) {
-			require.Equal(t, tc.want, isInterimContinuation(tc.previous, tc.current))
+			require.Equal(t, tc.want, isInterimContinuation(tc.previous, tc.current, 0))
 		})
 	}
 
-	require.False(t, shouldCommitInterimBoundary("", 5, 0.9, 1.0, 2.0))
-	require.False(t, shouldCommitInterimBoundary("first phrase", 1, 0.1, 1.0, 1.2))
-	require.True(t, shouldCommitInterimBoundary("first phrase", 2, 0.1, 1.0, 1.1))
-	require.True(t, shouldCommitInterimBoundary("first phrase", 1, 0.9, 1.0, 1.1))
-	require.True(t, shouldCommitInterimBoundary("done.", 1, 0.0, 1.0, 1.1))
-	require.True(t, shouldCommitInterimBoundary("first phrase has enough words", 1, 0.1, 1.0, 2.0))
-	require.False(t, shouldCommitInterimBoundary("too short", 1, 0.1, 1.0, 2.0))
+	require.Empty(t, interimBoundaryReason("", 5, 0, 0.9, 1.0, 2.0))
+	require.Empty(t, interimBoundaryReason("first phrase", 1, 0, 0.1, 1.0, 1.2))
+	require.Equal(t, "chain_age", interimBoundaryReason("first phrase", 2, 0, 0.1, 1.0, 1.1))
+	require.Equal(t, "stability", interimBoundaryReason("first phrase", 1, 0, 0.9, 1.0, 1.1))
+	require.Equal(t, "sentence_end", interimBoundaryReason("done.", 1, 0, 0.0, 1.0, 1.1))
+	require.Equal(t, "audio_advance", interimBoundaryReason("first phrase has enough words", 1, 0, 0.1, 1.0, 2.0))
+	require.Empty(t, interimBoundaryReason("too short", 1, 0, 0.1, 1.0, 2.0))
+	require.Empty(t, interimBoundaryReason("first phrase", 2, 3, 0.1, 1.0, 1.1))
+	require.Equal(t, "chain_age", interimBoundaryReason("first phrase", 3, 3, 0.1, 1.0, 1.1))
+}
+
+func TestIsInterimContinuationFuzzyThreshold(t *testing.T) {
+	previous := "the review thread looks good"
+	current := "a review thread looked good"
+
+	require.False(t, isInterimContinuation(previous, current, 0))
+	require.True(t, isInterimContinuation(previous, current, 0.4))
+	require.False(t, isInterimContinuation(previous, current, 0.3))
+	require.False(t, isInterimContinuation("first phrase", "second thought", 0.4))
+}
+
+func TestWordDistanceRatio(t *testing.T) {
+	require.Zero(t, wordDistanceRatio(nil, nil))
+	require.Zero(t, wordDistanceRatio([]string{"Hello", "world"}, []string{"hello", "world"}))
+	require.InDelta(t, 0.5, wordDistanceRatio([]string{"hello", "world"}, []string{"hello"}), 1e-9)
+	require.InDelta(t, 1.0, wordDistanceRatio([]string{"one", "two"}, []string{"three", "four"}), 1e-9)
+}
+
+func TestRecordResponseFuzzyModeMergesNearDuplicateInterims(t *testing.T) {
+	interims := []string{"the review thread looks good", "a review thread looked good"}
+	feed := func(s *Stream) {
+		for _, text := range interims {
+			s.recordResponse(&asrpb.StreamingRecognizeResponse{Results: []*asrpb.StreamingRecognitionResult{{
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+			}}})
+		}
+	}
+
+	fuzzy := &Stream{interimFuzzyThreshold: 0.4}
+	feed(fuzzy)
+	require.Empty(t, fuzzy.segments)
+	require.Equal(t, "a review thread looked good", fuzzy.lastInterim)
+	require.Equal(t, 2, fuzzy.lastInterimAge)
+
+	strict := &Stream{}
+	feed(strict)
+	require.Equal(t, 1, strict.lastInterimAge)
+	require.Equal(t, "a review thread looked good", strict.lastInterim)
+}
+
+func TestRecordResponseLogsMergeDecisionsAtDebug(t *testing.T) {
+	record := func(s *Stream, texts ...string) {
+		for _, text := range texts {
+			s.recordResponse(&asrpb.StreamingRecognizeResponse{
+				Results: []*asrpb.StreamingRecognitionResult{{
+					IsFinal:      false,
+					Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+				}},
+			})
+		}
+	}
+
+	var logBuf bytes.Buffer
+	s := &Stream{logger: slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	record(s, "first phrase", "first phrase extended", "second phrase")
+
+	var commit map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logBuf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		require.Equal(t, "interim merge decision", entry["msg"])
+		if entry["decision"] == "boundary_commit" {
+			commit = entry
+		}
+	}
+	require.NotNil(t, commit)
+	require.Equal(t, "chain_age", commit["reason"])
+	require.EqualValues(t, 1, commit["segments"])
+
+	// A boundary that only repeats the last segment is logged as such.
+	logBuf.Reset()
+	s = &Stream{segments: []string{"first phrase"}, logger: s.logger}
+	record(s, "first phrase", "first phrase", "something else")
+	require.Contains(t, logBuf.String(), `"decision":"boundary_repeat"`)
+	require.NotContains(t, logBuf.String(), `"decision":"boundary_commit"`)
+
+	logBuf.Reset()
+	s = &Stream{logger: slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelInfo}))}
+	record(s, "first phrase", "first phrase extended", "second phrase")
+	require.Empty(t, logBuf.String())
+}
+
+func TestRecordResponseHigherInterimMaxAgeDelaysCommit(t *testing.T) {
+	record := func(s *Stream, texts ...string) {
+		for _, text := range texts {
+			s.recordResponse(&asrpb.StreamingRecognizeResponse{
+				Results: []*asrpb.StreamingRecognitionResult{{
+					IsFinal:      false,
+					Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: text}},
+				}},
+			})
+		}
+	}
+
+	s := &Stream{interimMaxAge: 3}
+	record(s, "first phrase", "first phrase extended", "second phrase")
+	require.Empty(t, s.segments)
+	require.Equal(t, "second phrase", s.lastInterim)
+
+	s = &Stream{interimMaxAge: 3}
+	record(s, "first phrase", "first phrase extended", "first phrase extended again", "second phrase")
+	require.Equal(t, []string{"first phrase extended again"}, s.segments)
+	require.Equal(t, "second phrase", s.lastInterim)
 }
 
 func TestDialStreamEndToEndWithDebugSinkAndSpeechContexts(t *testing.T) {
@@ -343,9 +670,11 @@ func TestDialStreamEndToEndWithDebugSinkAndSpeechContexts(t *testing.T) {
 	require.NoError(t, stream.SendAudio([]byte{1, 2, 3, 4}))
 	require.NoError(t, stream.SendAudio(nil)) // no-op path
 
-	segments, latency, err := stream.CloseAndCollect(ctx)
+	collected, latency, err := stream.CloseAndCollectTranscript(ctx)
 	require.NoError(t, err)
-	require.Equal(t, []string{"hello world", "second phrase"}, segments)
+	require.Equal(t, []string{"hello world"}, collected.Committed)
+	require.Equal(t, "second phrase", collected.Interim)
+	require.Equal(t, []string{"hello world", "second phrase"}, collected.Segments())
 	require.GreaterOrEqual(t, latency, time.Duration(0))
 
 	require.NotNil(t, server.receivedConfig)
@@ -359,6 +688,142 @@ func TestDialStreamEndToEndWithDebugSinkAndSpeechContexts(t *testing.T) {
 	require.Equal(t, 1, server.audioChunks)
 
 	require.Contains(t, debug.String(), "results")
+	for _, line := range strings.Split(strings.TrimSpace(debug.String()), "\n") {
+		var entry map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		require.Contains(t, entry, "t_ms")
+		require.Contains(t, entry, "resp")
+	}
+}
+
+func TestDialConnThenOpenStream(t *testing.T) {
+	server := &testRivaServer{
+		responses: []*asrpb.StreamingRecognizeResponse{
+			{Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      true,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "warm"}},
+			}}},
+		},
+	}
+	endpoint, shutdown := startTestRivaServer(t, server)
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cfg := StreamConfig{Endpoint: endpoint, DialTimeout: 2 * time.Second, Encoding: "flac"}
+	conn, err := DialConn(ctx, cfg)
+	require.NoError(t, err)
+
+	stream, err := conn.OpenStream(ctx, cfg)
+	require.NoError(t, err)
+	collected, _, err := stream.CloseAndCollectTranscript(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"warm"}, collected.Committed)
+	require.Equal(t, "en-US", server.receivedConfig.Config.LanguageCode)
+	require.Equal(t, asrpb.AudioEncoding_FLAC, server.receivedConfig.Config.Encoding)
+	require.False(t, server.receivedConfig.Config.EnableAutomaticPunctuation)
+	require.False(t, server.receivedConfig.Config.VerbatimTranscripts)
+}
+
+func TestOpenStreamSendsVerbatimFlag(t *testing.T) {
+	server := &testRivaServer{}
+	endpoint, shutdown := startTestRivaServer(t, server)
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := DialStream(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: 2 * time.Second, Verbatim: true})
+	require.NoError(t, err)
+	_, _, err = stream.CloseAndCollect(ctx)
+	require.NoError(t, err)
+	require.True(t, server.receivedConfig.Config.VerbatimTranscripts)
+}
+
+func TestRecognitionEncodingDefaultsToLinearPCM(t *testing.T) {
+	require.Equal(t, asrpb.AudioEncoding_LINEAR_PCM, recognitionEncoding(""))
+	require.Equal(t, asrpb.AudioEncoding_LINEAR_PCM, recognitionEncoding("linear_pcm"))
+	require.Equal(t, asrpb.AudioEncoding_FLAC, recognitionEncoding(" FLAC "))
+}
+
+func TestDialConnRejectsEmptyEndpoint(t *testing.T) {
+	_, err := DialConn(context.Background(), StreamConfig{Endpoint: "  "})
+	require.ErrorContains(t, err, "endpoint is empty")
+}
+
+func TestListModelsReturnsSortedUniqueNames(t *testing.T) {
+	server := &testRivaServer{models: []string{"parakeet-rnnt", "", "conformer", "parakeet-rnnt"}}
+	endpoint, shutdown := startTestRivaServer(t, server)
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	models, err := ListModels(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: 2 * time.Second})
+	require.NoError(t, err)
+	require.Equal(t, []string{"conformer", "parakeet-rnnt"}, models)
+}
+
+func TestListModelsReportsUnsupportedServer(t *testing.T) {
+	endpoint, shutdown := startTestRivaServer(t, &asrpb.UnimplementedRivaSpeechRecognitionServer{})
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := ListModels(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: 2 * time.Second})
+	require.ErrorIs(t, err, ErrModelListingUnsupported)
+}
+
+func TestDebugResponseLineCarriesOffset(t *testing.T) {
+	line, err := debugResponseLine(1500*time.Millisecond, &asrpb.StreamingRecognizeResponse{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"t_ms":1500,"resp":{}}`, string(line))
+}
+
+func TestDialStreamMaxRecvMsgBytesGovernsLargeResponses(t *testing.T) {
+	large := strings.Repeat("a", 5<<20)
+	server := &testRivaServer{
+		responses: []*asrpb.StreamingRecognizeResponse{
+			{Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      true,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: large}},
+			}}},
+		},
+	}
+	endpoint, shutdown := startTestRivaServer(t, server)
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := DialStream(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: time.Second, MaxRecvMsgBytes: 8 << 20})
+	require.NoError(t, err)
+	segments, _, err := stream.CloseAndCollect(ctx)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	require.Len(t, segments[0], len(large))
+
+	stream, err = DialStream(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: time.Second, MaxRecvMsgBytes: 1 << 20})
+	require.NoError(t, err)
+	_, _, err = stream.CloseAndCollect(ctx)
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestKeepaliveParams(t *testing.T) {
+	_, ok := keepaliveParams(0)
+	require.False(t, ok)
+
+	params, ok := keepaliveParams(45 * time.Second)
+	require.True(t, ok)
+	require.Equal(t, 45*time.Second, params.Time)
+	require.Positive(t, params.Timeout)
+	require.False(t, params.PermitWithoutStream)
+
+	require.Len(t, dialOptions(StreamConfig{}), 1)
+	require.Len(t, dialOptions(StreamConfig{KeepaliveInterval: 45 * time.Second}), 2)
 }
 
 func TestDialStreamEmptyEndpoint(t *testing.T) {
@@ -379,6 +844,34 @@ func TestDialStreamReadinessTimeout(t *testing.T) {
 	require.Contains(t, err.Error(), "readiness")
 }
 
+func TestDialConnRedactsEndpointCredentialsInErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := DialConn(ctx, StreamConfig{
+		Endpoint:    "user:hunter2@127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "user:***@127.0.0.1:1")
+	require.NotContains(t, err.Error(), "hunter2")
+}
+
+func TestWaitForReadyReportsTargetAndLastState(t *testing.T) {
+	conn, err := grpc.NewClient("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	conn.Connect()
+	err = waitForReady(ctx, conn, "127.0.0.1:1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not connect to Riva at 127.0.0.1:1")
+	require.Contains(t, err.Error(), "last state")
+}
+
 func TestRunWithTimeoutTimesOut(t *testing.T) {
 	err := runWithTimeout(context.Background(), 20*time.Millisecond, func() error {
 		time.Sleep(120 * time.Millisecond)
@@ -422,6 +915,156 @@ func TestCloseAndCollectReturnsServerStreamError(t *testing.T) {
 	require.Contains(t, err.Error(), "boom")
 }
 
+func TestCloseAndCollectSurfacesTrailerMetadata(t *testing.T) {
+	server := &testRivaServer{
+		responses: []*asrpb.StreamingRecognizeResponse{
+			{Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      true,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "hello"}},
+			}}},
+		},
+		trailer: metadata.Pairs("x-request-id", "req-42", "x-model-version", "parakeet-1.1b-2"),
+	}
+	endpoint, shutdown := startTestRivaServer(t, server)
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stream, err := DialStream(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: time.Second})
+	require.NoError(t, err)
+	collected, _, err := stream.CloseAndCollectTranscript(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"hello"}, collected.Committed)
+	require.Equal(t, "req-42", collected.RequestID)
+	require.Equal(t, "parakeet-1.1b-2", collected.ModelVersion)
+
+	server.trailer = nil
+	stream, err = DialStream(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: time.Second})
+	require.NoError(t, err)
+	collected, _, err = stream.CloseAndCollectTranscript(ctx)
+	require.NoError(t, err)
+	require.Empty(t, collected.RequestID)
+	require.Empty(t, collected.ModelVersion)
+}
+
+func TestCloseAndCollectErrorIncludesTrailerRequestID(t *testing.T) {
+	server := &testRivaServer{
+		streamErr: status.Error(codes.Internal, "boom"),
+		trailer:   metadata.Pairs("request-id", "req-7"),
+	}
+	endpoint, shutdown := startTestRivaServer(t, server)
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stream, err := DialStream(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: time.Second})
+	require.NoError(t, err)
+	_, _, err = stream.CloseAndCollect(ctx)
+	require.ErrorContains(t, err, "boom")
+	require.ErrorContains(t, err, "riva request id req-7")
+	require.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestCloseAndCollectExplainsServerStatus(t *testing.T) {
+	tests := []struct {
+		code    codes.Code
+		summary string
+	}{
+		{code: codes.Unavailable, summary: "riva not reachable"},
+		{code: codes.Unauthenticated, summary: "riva auth failed"},
+		{code: codes.PermissionDenied, summary: "riva auth failed"},
+		{code: codes.InvalidArgument, summary: "riva rejected the model or recognition config"},
+		{code: codes.Internal, summary: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.code.String(), func(t *testing.T) {
+			server := &testRivaServer{streamErr: status.Error(tc.code, "server detail")}
+			endpoint, shutdown := startTestRivaServer(t, server)
+			defer shutdown()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			stream, err := DialStream(ctx, StreamConfig{Endpoint: endpoint, DialTimeout: time.Second})
+			require.NoError(t, err)
+
+			_, _, err = stream.CloseAndCollect(ctx)
+			require.Error(t, err)
+			require.Equal(t, tc.code, status.Code(err))
+			require.Contains(t, err.Error(), "server detail")
+			if tc.summary == "" {
+				require.True(t, strings.HasPrefix(err.Error(), "rpc error:"), err.Error())
+				return
+			}
+			require.True(t, strings.HasPrefix(err.Error(), tc.summary+": "), err.Error())
+		})
+	}
+}
+
+func TestExplainStatusPassesThroughNonStatusErrors(t *testing.T) {
+	require.NoError(t, explainStatus(nil))
+
+	plain := errors.New("plain")
+	require.Equal(t, plain, explainStatus(plain))
+}
+
+func TestFirstResponseTimeoutFailsSilentStream(t *testing.T) {
+	endpoint, shutdown := startTestRivaServer(t, &testRivaServer{silent: true})
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stream, err := DialStream(ctx, StreamConfig{
+		Endpoint:             endpoint,
+		DialTimeout:          time.Second,
+		FirstResponseTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, stream.SendAudio([]byte{1, 2}))
+
+	require.Eventually(t, func() bool {
+		return errors.Is(stream.SendAudio([]byte{3, 4}), ErrNoResponse)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	started := time.Now()
+	_, _, err = stream.CloseAndCollect(ctx)
+	require.ErrorIs(t, err, ErrNoResponse)
+	require.Contains(t, err.Error(), "check model")
+	require.Less(t, time.Since(started), time.Second)
+}
+
+func TestFirstResponseTimeoutIgnoresAnsweredStream(t *testing.T) {
+	server := &testRivaServer{
+		responses: []*asrpb.StreamingRecognizeResponse{
+			{Results: []*asrpb.StreamingRecognitionResult{{
+				IsFinal:      true,
+				Alternatives: []*asrpb.SpeechRecognitionAlternative{{Transcript: "hello"}},
+			}}},
+		},
+	}
+	endpoint, shutdown := startTestRivaServer(t, server)
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stream, err := DialStream(ctx, StreamConfig{
+		Endpoint:             endpoint,
+		DialTimeout:          time.Second,
+		FirstResponseTimeout: time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, stream.SendAudio([]byte{1, 2}))
+
+	segments, _, err := stream.CloseAndCollect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"hello"}, segments)
+}
+
 func TestSendAudioAfterCloseReturnsError(t *testing.T) {
 	server := &testRivaServer{}
 	endpoint, shutdown := startTestRivaServer(t, server)
@@ -446,12 +1089,20 @@ type testRivaServer struct {
 
 	responses []*asrpb.StreamingRecognizeResponse
 	streamErr error
+	models    []string
+	// silent reads audio but never answers or ends the RPC.
+	silent bool
+	// trailer is sent as the RPC trailer metadata when non-nil.
+	trailer metadata.MD
 
 	receivedConfig *asrpb.StreamingRecognitionConfig
 	audioChunks    int
 }
 
 func (s *testRivaServer) StreamingRecognize(stream grpc.BidiStreamingServer[asrpb.StreamingRecognizeRequest, asrpb.StreamingRecognizeResponse]) error {
+	if s.trailer != nil {
+		stream.SetTrailer(s.trailer)
+	}
 	for {
 		req, err := stream.Recv()
 		if errors.Is(err, io.EOF) {
@@ -470,6 +1121,11 @@ func (s *testRivaServer) StreamingRecognize(stream grpc.BidiStreamingServer[asrp
 		}
 	}
 
+	if s.silent {
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	}
+
 	for _, resp := range s.responses {
 		if err := stream.Send(resp); err != nil {
 			return err
@@ -481,6 +1137,14 @@ func (s *testRivaServer) StreamingRecognize(stream grpc.BidiStreamingServer[asrp
 	return nil
 }
 
+func (s *testRivaServer) GetRivaSpeechRecognitionConfig(context.Context, *asrpb.RivaSpeechRecognitionConfigRequest) (*asrpb.RivaSpeechRecognitionConfigResponse, error) {
+	resp := &asrpb.RivaSpeechRecognitionConfigResponse{}
+	for _, name := range s.models {
+		resp.ModelConfig = append(resp.ModelConfig, &asrpb.RivaSpeechRecognitionConfigResponse_Config{ModelName: name})
+	}
+	return resp, nil
+}
+
 func startTestRivaServer(t *testing.T, srv asrpb.RivaSpeechRecognitionServer) (string, func()) {
 	t.Helper()
 
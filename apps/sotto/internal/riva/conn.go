@@ -0,0 +1,143 @@
+package riva
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbright/sotto/internal/config"
+	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
+	"google.golang.org/grpc"
+)
+
+// Conn is a Riva gRPC connection that has reached the Ready state.
+//
+// A Conn can be dialed ahead of recording so the session hot path only pays
+// for opening the recognize stream. It backs exactly one Stream: OpenStream
+// hands the connection to the returned Stream and closes it on failure.
+type Conn struct {
+	conn *grpc.ClientConn
+}
+
+// DialConn connects to the configured endpoint and waits for readiness.
+func DialConn(ctx context.Context, cfg StreamConfig) (*Conn, error) {
+	cfg = normalizeStreamConfig(cfg)
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	if endpoint == "" {
+		return nil, errors.New("riva endpoint is empty")
+	}
+
+	displayEndpoint := config.RedactEndpoint(endpoint)
+	conn, err := grpc.NewClient(endpoint, dialOptions(cfg)...)
+	if err != nil {
+		return nil, fmt.Errorf("dial riva grpc %q: %w", displayEndpoint, err)
+	}
+
+	readyCtx, cancel := context.WithTimeout(ctx, cfg.DialTimeout)
+	defer cancel()
+	conn.Connect()
+	if err := waitForReady(readyCtx, conn, displayEndpoint); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("wait for riva grpc readiness: %w", err)
+	}
+
+	return &Conn{conn: conn}, nil
+}
+
+// OpenStream opens a recognize stream, sends config, and starts the receive loop.
+func (c *Conn) OpenStream(ctx context.Context, cfg StreamConfig) (*Stream, error) {
+	cfg = normalizeStreamConfig(cfg)
+
+	streamCtx, streamCancel := context.WithCancel(ctx)
+	client := asrpb.NewRivaSpeechRecognitionClient(c.conn)
+	stream, err := openRecognizeWithTimeout(streamCtx, cfg.DialTimeout, func() (asrpb.RivaSpeechRecognition_StreamingRecognizeClient, error) {
+		return client.StreamingRecognize(streamCtx)
+	})
+	if err != nil {
+		streamCancel()
+		_ = c.conn.Close()
+		return nil, fmt.Errorf("open streaming recognizer: %w", err)
+	}
+
+	req := &asrpb.StreamingRecognizeRequest{
+		StreamingRequest: &asrpb.StreamingRecognizeRequest_StreamingConfig{
+			StreamingConfig: &asrpb.StreamingRecognitionConfig{
+				Config: &asrpb.RecognitionConfig{
+					Encoding:                   recognitionEncoding(cfg.Encoding),
+					SampleRateHertz:            16000,
+					LanguageCode:               cfg.LanguageCode,
+					EnableAutomaticPunctuation: cfg.AutomaticPunctuation,
+					VerbatimTranscripts:        cfg.Verbatim,
+					AudioChannelCount:          1,
+					Model:                      strings.TrimSpace(cfg.Model),
+				},
+				InterimResults: true,
+			},
+		},
+	}
+
+	for _, phrase := range cfg.SpeechPhrases {
+		phraseText := strings.TrimSpace(phrase.Phrase)
+		if phraseText == "" {
+			continue
+		}
+		req.GetStreamingConfig().GetConfig().SpeechContexts = append(
+			req.GetStreamingConfig().GetConfig().SpeechContexts,
+			&asrpb.SpeechContext{Phrases: []string{phraseText}, Boost: phrase.Boost},
+		)
+	}
+
+	if err := runWithTimeout(streamCtx, cfg.DialTimeout, func() error {
+		return stream.Send(req)
+	}); err != nil {
+		streamCancel()
+		_ = c.conn.Close()
+		return nil, fmt.Errorf("send initial streaming config: %w", err)
+	}
+
+	startedAt := cfg.DebugOrigin
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+	s := &Stream{
+		conn:                  c.conn,
+		stream:                stream,
+		cancel:                streamCancel,
+		recvDone:              make(chan struct{}),
+		debugSinkJSON:         cfg.DebugResponseSinkJSON,
+		logger:                cfg.Logger,
+		interimMaxAge:         cfg.InterimMaxAge,
+		finalDedupeWindow:     cfg.FinalDedupeWindow,
+		interimFuzzyThreshold: cfg.InterimFuzzyThreshold,
+		startedAt:             startedAt,
+		firstResponseTimeout:  cfg.FirstResponseTimeout,
+	}
+	go s.recvLoop()
+	return s, nil
+}
+
+// Close releases a connection that was never handed to a Stream.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// recognitionEncoding maps the configured audio encoding to the Riva enum.
+func recognitionEncoding(name string) asrpb.AudioEncoding {
+	if strings.EqualFold(strings.TrimSpace(name), "flac") {
+		return asrpb.AudioEncoding_FLAC
+	}
+	return asrpb.AudioEncoding_LINEAR_PCM
+}
+
+// normalizeStreamConfig fills defaults for unset dial timeout and language.
+func normalizeStreamConfig(cfg StreamConfig) StreamConfig {
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 3 * time.Second
+	}
+	if strings.TrimSpace(cfg.LanguageCode) == "" {
+		cfg.LanguageCode = "en-US"
+	}
+	return cfg
+}
@@ -0,0 +1,30 @@
+package riva
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusSummaries maps gRPC codes that have an obvious user-side cause to a
+// short explanation. Other codes are returned unchanged.
+var statusSummaries = map[codes.Code]string{
+	codes.Unavailable:      "riva not reachable",
+	codes.Unauthenticated:  "riva auth failed",
+	codes.PermissionDenied: "riva auth failed",
+	codes.InvalidArgument:  "riva rejected the model or recognition config",
+}
+
+// explainStatus prefixes err with a friendly summary of its gRPC status.
+// The original error stays reachable through errors.Is/As and status.Code.
+func explainStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	summary, ok := statusSummaries[status.Code(err)]
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%s: %w", summary, err)
+}
@@ -6,11 +6,22 @@ import (
 	"fmt"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 )
 
+// maxReadyReconnects bounds explicit reconnect kicks after TransientFailure.
+const maxReadyReconnects = 3
+
 // waitForReady blocks until gRPC connection enters Ready or fails.
-func waitForReady(ctx context.Context, conn *grpc.ClientConn) error {
+//
+// target names the endpoint in errors; callers pass it redacted.
+//
+// TransientFailure re-kicks the connection (the channel applies its own
+// backoff) up to maxReadyReconnects times before giving up.
+func waitForReady(ctx context.Context, conn *grpc.ClientConn, target string) error {
+	reconnects := 0
 	for {
 		state := conn.GetState()
 		switch state {
@@ -18,13 +29,24 @@ func waitForReady(ctx context.Context, conn *grpc.ClientConn) error {
 			return nil
 		case connectivity.Shutdown:
 			return errors.New("grpc connection entered shutdown state")
+		case connectivity.TransientFailure:
+			if reconnects >= maxReadyReconnects {
+				return connectError(target, state, status.Errorf(codes.Unavailable, "gave up after %d reconnect attempts", reconnects))
+			}
+			reconnects++
+			conn.Connect()
 		}
 
 		if !conn.WaitForStateChange(ctx, state) {
 			if ctx.Err() != nil {
-				return ctx.Err()
+				return connectError(target, state, ctx.Err())
 			}
 			return fmt.Errorf("grpc readiness wait timed out in state %s", state.String())
 		}
 	}
 }
+
+// connectError formats a readiness failure with target and last connectivity state.
+func connectError(target string, state connectivity.State, cause error) error {
+	return fmt.Errorf("could not connect to Riva at %s (last state %s): %w", target, state.String(), cause)
+}
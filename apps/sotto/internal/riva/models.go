@@ -0,0 +1,54 @@
+package riva
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrModelListingUnsupported reports a server without the ASR config RPC.
+var ErrModelListingUnsupported = errors.New("riva server does not expose model listing")
+
+// ListModels returns the sorted, de-duplicated ASR model names loaded on the server.
+func ListModels(ctx context.Context, cfg StreamConfig) ([]string, error) {
+	conn, err := DialConn(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	cfg = normalizeStreamConfig(cfg)
+	callCtx, cancel := context.WithTimeout(ctx, cfg.DialTimeout)
+	defer cancel()
+
+	client := asrpb.NewRivaSpeechRecognitionClient(conn.conn)
+	resp, err := client.GetRivaSpeechRecognitionConfig(callCtx, &asrpb.RivaSpeechRecognitionConfigRequest{})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, ErrModelListingUnsupported
+		}
+		return nil, fmt.Errorf("get riva speech recognition config: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	models := make([]string, 0, len(resp.GetModelConfig()))
+	for _, modelCfg := range resp.GetModelConfig() {
+		name := strings.TrimSpace(modelCfg.GetModelName())
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		models = append(models, name)
+	}
+	sort.Strings(models)
+	return models, nil
+}
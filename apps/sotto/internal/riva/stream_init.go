@@ -2,10 +2,11 @@ package riva
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type openResult struct {
@@ -36,7 +37,7 @@ func openRecognizeWithTimeout(
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case <-timer.C:
-		return nil, fmt.Errorf("timed out after %s", timeout)
+		return nil, status.Errorf(codes.DeadlineExceeded, "timed out after %s", timeout)
 	case result := <-resultCh:
 		return result.stream, result.err
 	}
@@ -60,7 +61,7 @@ func runWithTimeout(ctx context.Context, timeout time.Duration, call func() erro
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-timer.C:
-		return fmt.Errorf("timed out after %s", timeout)
+		return status.Errorf(codes.DeadlineExceeded, "timed out after %s", timeout)
 	case err := <-resultCh:
 		return err
 	}
@@ -1,9 +1,16 @@
 package riva
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"hash/fnv"
 	"io"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	asrpb "github.com/rbright/sotto/proto/gen/go/riva/proto"
 )
@@ -18,21 +25,37 @@ func (s *Stream) recvLoop() {
 			s.recordResponse(resp)
 			continue
 		}
-		if errors.Is(err, io.EOF) {
-			return
-		}
-
+		// Trailers are only available once Recv has returned an error; a
+		// server that sends none leaves them empty.
+		trailer := s.stream.Trailer()
 		s.mu.Lock()
-		s.recvErr = err
+		s.trailer = trailer
+		if !errors.Is(err, io.EOF) && s.recvErr == nil {
+			s.recvErr = err
+		}
 		s.mu.Unlock()
+		if s.logger != nil && len(trailer) > 0 {
+			s.logger.Debug("riva stream trailers", "trailers", trailer)
+		}
 		return
 	}
 }
 
+// debugResponseEntry is one line of the gRPC debug dump.
+type debugResponseEntry struct {
+	OffsetMS int64                             `json:"t_ms"`
+	Response *asrpb.StreamingRecognizeResponse `json:"resp"`
+}
+
+// debugResponseLine wraps a response with its arrival offset from stream start.
+func debugResponseLine(offset time.Duration, resp *asrpb.StreamingRecognizeResponse) ([]byte, error) {
+	return json.Marshal(debugResponseEntry{OffsetMS: offset.Milliseconds(), Response: resp})
+}
+
 // recordResponse merges final/interim segments into stream state.
 func (s *Stream) recordResponse(resp *asrpb.StreamingRecognizeResponse) {
 	if sink := s.debugSinkJSON; sink != nil {
-		b, err := json.Marshal(resp)
+		b, err := debugResponseLine(time.Since(s.startedAt), resp)
 		if err == nil {
 			_, _ = sink.Write(append(b, '\n'))
 		}
@@ -41,17 +64,35 @@ func (s *Stream) recordResponse(resp *asrpb.StreamingRecognizeResponse) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.gotResponse = true
 	for _, result := range resp.GetResults() {
 		alternatives := result.GetAlternatives()
 		if len(alternatives) == 0 {
 			continue
 		}
-		transcript := cleanSegment(alternatives[0].GetTranscript())
+		raw := alternatives[0].GetTranscript()
+		if !utf8.ValidString(raw) {
+			s.invalidUTF8++
+		}
+		transcript := cleanSegment(raw)
 		if transcript == "" {
 			continue
 		}
+		if s.firstResultAt.IsZero() {
+			s.firstResultAt = time.Now()
+			r, _ := utf8.DecodeRuneInString(raw)
+			s.leadingSpace = unicode.IsSpace(r)
+		}
+		l, _ := utf8.DecodeLastRuneInString(raw)
+		s.trailingSpace = unicode.IsSpace(l)
 		if result.GetIsFinal() {
-			s.segments = appendSegment(s.segments, transcript)
+			if s.repeatsRecentFinal(transcript) {
+				s.logMergeDecision("final_repeat", "recent_final")
+			} else if s.commitSegment(transcript) {
+				s.logMergeDecision("final", "is_final")
+			} else {
+				s.logMergeDecision("final_repeat", "recent_segment")
+			}
 			s.lastInterim = ""
 			s.lastInterimAge = 0
 			s.lastInterimStability = 0
@@ -61,21 +102,29 @@ func (s *Stream) recordResponse(resp *asrpb.StreamingRecognizeResponse) {
 
 		currentAudioProcessed := result.GetAudioProcessed()
 		if s.lastInterim != "" {
-			if isInterimContinuation(s.lastInterim, transcript) {
+			if isInterimContinuation(s.lastInterim, transcript, s.interimFuzzyThreshold) {
 				s.lastInterim = transcript
 				s.lastInterimAge++
 				s.lastInterimStability = result.GetStability()
 				s.lastInterimAudioProcessed = currentAudioProcessed
+				s.logMergeDecision("continuation", "")
 				continue
 			}
-			if shouldCommitInterimBoundary(
+			reason := interimBoundaryReason(
 				s.lastInterim,
 				s.lastInterimAge,
+				s.interimMaxAge,
 				s.lastInterimStability,
 				s.lastInterimAudioProcessed,
 				currentAudioProcessed,
-			) {
-				s.segments = appendSegment(s.segments, s.lastInterim)
+			)
+			switch {
+			case reason == "":
+				s.logMergeDecision("boundary_drop", "unestablished")
+			case s.commitSegment(s.lastInterim):
+				s.logMergeDecision("boundary_commit", reason)
+			default:
+				s.logMergeDecision("boundary_repeat", reason)
 			}
 		}
 
@@ -85,3 +134,68 @@ func (s *Stream) recordResponse(resp *asrpb.StreamingRecognizeResponse) {
 		s.lastInterimAudioProcessed = currentAudioProcessed
 	}
 }
+
+// repeatsRecentFinal reports whether transcript normalizes to one of the last
+// finalDedupeWindow finals, and records it as the newest final either way.
+// Unlike appendSegment, this survives interims committed between the two
+// finals. Like repeatsRecentSegment, finals shorter than
+// minRepeatedSegmentWords are never repeats: "Yes." twice is real speech.
+// Callers hold s.mu.
+func (s *Stream) repeatsRecentFinal(transcript string) bool {
+	if s.finalDedupeWindow <= 0 {
+		return false
+	}
+	key := finalKey(transcript)
+	repeated := false
+	if len(strings.Fields(transcript)) >= minRepeatedSegmentWords {
+		for _, recent := range s.recentFinals {
+			if recent == key {
+				repeated = true
+				break
+			}
+		}
+	}
+	s.recentFinals = append(s.recentFinals, key)
+	if len(s.recentFinals) > s.finalDedupeWindow {
+		s.recentFinals = s.recentFinals[len(s.recentFinals)-s.finalDedupeWindow:]
+	}
+	return repeated
+}
+
+// finalKey hashes transcript with case and punctuation dropped, so "Hello,
+// world." and "hello world" compare equal.
+func finalKey(transcript string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(transcript), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join(words, " ")))
+	return h.Sum64()
+}
+
+// commitSegment merges text into s.segments and reports whether they
+// changed; appendSegment drops repeats and prefixes of the last segment.
+// Callers hold s.mu.
+func (s *Stream) commitSegment(text string) bool {
+	count := len(s.segments)
+	last := ""
+	if count > 0 {
+		last = s.segments[count-1]
+	}
+	s.segments = appendSegment(s.segments, text)
+	return len(s.segments) != count || (count > 0 && s.segments[count-1] != last)
+}
+
+// logMergeDecision records one interim merge decision at debug level. It is a
+// no-op unless the stream logger has debug enabled. Callers hold s.mu.
+func (s *Stream) logMergeDecision(decision string, reason string) {
+	if s.logger == nil || !s.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	s.logger.Debug("interim merge decision",
+		"decision", decision,
+		"reason", reason,
+		"chain_age", s.lastInterimAge,
+		"segments", len(s.segments),
+	)
+}
@@ -0,0 +1,26 @@
+package riva
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Trailer keys Riva deployments and the proxies in front of them commonly use
+// to identify a request and the serving model. The first non-empty key wins.
+var (
+	requestIDTrailerKeys    = []string{"x-request-id", "request-id", "x-riva-request-id"}
+	modelVersionTrailerKeys = []string{"x-model-version", "model-version", "x-riva-model-version"}
+)
+
+// trailerValue returns the first non-empty value of keys in md.
+func trailerValue(md metadata.MD, keys []string) string {
+	for _, key := range keys {
+		for _, value := range md.Get(key) {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
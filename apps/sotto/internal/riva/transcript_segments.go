@@ -3,12 +3,54 @@ package riva
 import "strings"
 
 const (
-	minInterimChainUpdates             = 2
+	defaultInterimMaxAge               = 2
 	stableInterimBoundaryThreshold     = 0.85
 	interimBoundaryAudioAdvanceSeconds = 0.75
 	minInterimWordsForAudioBoundary    = 3
+
+	// Riva can replay an earlier final after a reconnect; exact repeats of one of
+	// the last few segments are dropped when long enough to be unlikely speech.
+	repeatedSegmentWindow   = 4
+	minRepeatedSegmentWords = 3
 )
 
+// Transcript separates finalized segments from the tentative trailing interim.
+type Transcript struct {
+	// Committed holds final results and sealed interim chains, in order.
+	Committed []string
+	// Interim is the last unsealed interim hypothesis (empty when none).
+	Interim string
+	// InvalidUTF8 counts received hypotheses that carried invalid UTF-8 bytes,
+	// which were stripped before merging.
+	InvalidUTF8 int
+	// RequestID and ModelVersion come from the RPC trailers when the server
+	// sends them; empty otherwise. They help match a session to server logs.
+	RequestID    string
+	ModelVersion string
+	// LeadingSpace and TrailingSpace report whether Riva's first and latest
+	// hypotheses began and ended with whitespace, which cleanup strips from
+	// the segments themselves.
+	LeadingSpace  bool
+	TrailingSpace bool
+}
+
+// Segments flattens the transcript, merging the interim tail into committed
+// text. Recognized edge whitespace is restored as a single space on the first
+// and last segments so transcript.trim_policy can keep it.
+func (t Transcript) Segments() []string {
+	segments := collectSegments(t.Committed, t.Interim)
+	if len(segments) == 0 {
+		return segments
+	}
+	if t.LeadingSpace {
+		segments[0] = " " + segments[0]
+	}
+	if t.TrailingSpace {
+		segments[len(segments)-1] += " "
+	}
+	return segments
+}
+
 // collectSegments appends a valid trailing interim segment when needed.
 func collectSegments(committedSegments []string, lastInterim string) []string {
 	segments := append([]string(nil), committedSegments...)
@@ -18,6 +60,22 @@ func collectSegments(committedSegments []string, lastInterim string) []string {
 	return segments
 }
 
+// unflushedSegments returns the segments after the first flushed ones. When
+// the last flushed segment, flushedLast at the time, has since been extended
+// in place, the extension leads the result so it is not lost.
+func unflushedSegments(segments []string, flushed int, flushedLast string) []string {
+	if flushed <= 0 {
+		return append([]string(nil), segments...)
+	}
+	pending := make([]string, 0, len(segments)-flushed+1)
+	if last := segments[flushed-1]; last != flushedLast && strings.HasPrefix(last, flushedLast) {
+		if extension := cleanSegment(last[len(flushedLast):]); extension != "" {
+			pending = append(pending, extension)
+		}
+	}
+	return append(pending, segments[flushed:]...)
+}
+
 // appendSegment merges continuation segments to avoid duplicate transcript growth.
 func appendSegment(segments []string, transcript string) []string {
 	transcript = cleanSegment(transcript)
@@ -37,14 +95,36 @@ func appendSegment(segments []string, transcript string) []string {
 		return segments
 	case strings.HasPrefix(last, transcript):
 		return segments
+	case repeatsRecentSegment(segments, transcript):
+		return segments
 	default:
 		return append(segments, transcript)
 	}
 }
 
+// repeatsRecentSegment reports whether transcript exactly repeats one of the
+// segments within repeatedSegmentWindow of the end.
+func repeatsRecentSegment(segments []string, transcript string) bool {
+	if len(strings.Fields(transcript)) < minRepeatedSegmentWords {
+		return false
+	}
+	start := len(segments) - repeatedSegmentWindow
+	if start < 0 {
+		start = 0
+	}
+	for _, segment := range segments[start:] {
+		if cleanSegment(segment) == transcript {
+			return true
+		}
+	}
+	return false
+}
+
 // isInterimContinuation reports whether the new interim looks like a rewrite or
-// extension of the prior interim hypothesis.
-func isInterimContinuation(previous string, current string) bool {
+// extension of the prior interim hypothesis. A positive fuzzyThreshold also
+// accepts hypotheses whose word edit distance, relative to the longer one, is
+// at most that fraction.
+func isInterimContinuation(previous string, current string, fuzzyThreshold float64) bool {
 	previous = cleanSegment(previous)
 	current = cleanSegment(current)
 	if previous == "" || current == "" {
@@ -76,33 +156,74 @@ func isInterimContinuation(previous string, current string) bool {
 	if shorter >= 3 && commonSuffixWords(prevWords, currWords)*2 >= shorter {
 		return true
 	}
+	if fuzzyThreshold > 0 && wordDistanceRatio(prevWords, currWords) <= fuzzyThreshold {
+		return true
+	}
 
 	return false
 }
 
-// shouldCommitInterimBoundary returns true when a divergent interim chain looks
-// established enough to preserve as a committed segment.
-func shouldCommitInterimBoundary(
+// wordDistanceRatio is the word-level Levenshtein distance between two
+// hypotheses divided by the longer word count.
+func wordDistanceRatio(left []string, right []string) float64 {
+	longer := len(left)
+	if len(right) > longer {
+		longer = len(right)
+	}
+	if longer == 0 {
+		return 0
+	}
+
+	prev := make([]int, len(right)+1)
+	curr := make([]int, len(right)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(left); i++ {
+		curr[0] = i
+		for j := 1; j <= len(right); j++ {
+			cost := 1
+			if strings.EqualFold(left[i-1], right[j-1]) {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return float64(prev[len(right)]) / float64(longer)
+}
+
+// interimBoundaryReason names the rule that commits a divergent interim chain:
+// "chain_age", "stability", "sentence_end", or "audio_advance". It returns ""
+// when the chain should be dropped. A chain of at least maxAge updates always
+// qualifies; non-positive maxAge uses the default.
+func interimBoundaryReason(
 	previous string,
 	chainUpdates int,
+	maxAge int,
 	stability float32,
 	previousAudioProcessed float32,
 	currentAudioProcessed float32,
-) bool {
+) string {
 	previous = cleanSegment(previous)
 	if previous == "" {
-		return false
-	}
-	if chainUpdates >= minInterimChainUpdates {
-		return true
+		return ""
 	}
-	if stability >= stableInterimBoundaryThreshold {
-		return true
+	if maxAge <= 0 {
+		maxAge = defaultInterimMaxAge
 	}
-	if endsWithSentencePunctuation(previous) {
-		return true
+	switch {
+	case chainUpdates >= maxAge:
+		return "chain_age"
+	case stability >= stableInterimBoundaryThreshold:
+		return "stability"
+	case endsWithSentencePunctuation(previous):
+		return "sentence_end"
+	case advancedAudioBoundary(previous, previousAudioProcessed, currentAudioProcessed):
+		return "audio_advance"
+	default:
+		return ""
 	}
-	return advancedAudioBoundary(previous, previousAudioProcessed, currentAudioProcessed)
 }
 
 func advancedAudioBoundary(previous string, previousAudioProcessed float32, currentAudioProcessed float32) bool {
@@ -163,11 +284,14 @@ func commonSuffixWords(left []string, right []string) int {
 	return count
 }
 
-// cleanSegment normalizes transcript whitespace.
+// cleanSegment strips invalid UTF-8 bytes and collapses spaces and tabs to
+// one space. Line breaks are kept, as "\n", so Assemble can preserve
+// paragraphs; leading and trailing ones are trimmed.
 func cleanSegment(raw string) string {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return ""
+	raw = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(strings.ToValidUTF8(raw, ""))
+	lines := strings.Split(raw, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
 	}
-	return strings.Join(strings.Fields(raw), " ")
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
 }
@@ -7,6 +7,18 @@ type Committer interface {
 	Commit(context.Context, string) error
 }
 
+// clipboardOnlyCommitter is implemented by committers that can skip paste
+// dispatch for a single commit (the --no-paste override).
+type clipboardOnlyCommitter interface {
+	CommitClipboardOnly(context.Context, string) error
+}
+
+// recoveringCommitter is implemented by committers that can save a transcript
+// somewhere durable when Commit fails. An empty path means nothing was saved.
+type recoveringCommitter interface {
+	Recover(context.Context, string) (string, error)
+}
+
 // CommitFunc adapts a function to the Committer interface.
 type CommitFunc func(context.Context, string) error
 
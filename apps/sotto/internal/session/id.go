@@ -0,0 +1,29 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// idKey is the context key carrying the active session ID.
+type idKey struct{}
+
+// NewID returns a short random session ID for correlating one session's logs
+// and debug artifacts.
+func NewID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WithID returns ctx carrying session ID id.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// IDFromContext returns the session ID carried by ctx, or "" outside a session.
+func IDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
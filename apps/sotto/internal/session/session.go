@@ -8,7 +8,9 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/rbright/sotto/internal/fsm"
 	"github.com/rbright/sotto/internal/ipc"
@@ -16,6 +18,13 @@ import (
 
 type action int
 
+// startRequest asks an idle daemon owner to begin a new session.
+type startRequest struct {
+	ptt       bool
+	noPaste   bool
+	overrides *ipc.SessionOverrides
+}
+
 const (
 	actionStop action = iota + 1
 	actionCancel
@@ -23,16 +32,26 @@ const (
 
 // Result is the complete lifecycle output returned by one Run invocation.
 type Result struct {
+	// ID is the random session ID tagged on this session's logs and debug
+	// artifacts.
+	ID             string
 	State          fsm.State
 	Transcript     string
 	Cancelled      bool
 	Err            error
 	AudioDevice    string
+	Model          string
+	LanguageCode   string
+	ConfigPath     string
 	BytesCaptured  int64
 	GRPCLatency    time.Duration
 	StartedAt      time.Time
 	FinishedAt     time.Time
 	FocusedMonitor string
+	// RequestID and ModelVersion identify the request on the ASR server, when
+	// it reports them.
+	RequestID    string
+	ModelVersion string
 }
 
 // Indicator is the session-facing subset of indicator behavior.
@@ -43,10 +62,21 @@ type Indicator interface {
 	CueStop(context.Context)
 	CueComplete(context.Context)
 	CueCancel(context.Context)
+	CueFallback(context.Context)
 	Hide(context.Context)
 	FocusedMonitor() string
 }
 
+// cancelPrompter is implemented by indicators that can ask for a second
+// cancel under session.confirm_cancel.
+type cancelPrompter interface {
+	ShowConfirmCancel(context.Context, time.Duration)
+}
+
+// ConfirmCancelWindow is how long a first cancel waits for the confirming
+// second one when confirm-cancel is enabled.
+const ConfirmCancelWindow = 2 * time.Second
+
 // noopIndicator preserves session flow when no indicator is wired.
 type noopIndicator struct{}
 
@@ -56,6 +86,7 @@ func (noopIndicator) ShowError(context.Context, string) {}
 func (noopIndicator) CueStop(context.Context)           {}
 func (noopIndicator) CueComplete(context.Context)       {}
 func (noopIndicator) CueCancel(context.Context)         {}
+func (noopIndicator) CueFallback(context.Context)       {}
 func (noopIndicator) Hide(context.Context)              {}
 func (noopIndicator) FocusedMonitor() string            { return "" }
 
@@ -68,8 +99,53 @@ type Controller struct {
 
 	mu    sync.RWMutex
 	state fsm.State
+	// last is the most recently committed transcript, served over IPC.
+	last string
+	// abortTranscribe cancels the in-flight StopAndTranscribe; it is set only
+	// while the transcript is being collected. transcribeAborted records that
+	// cancel used it.
+	abortTranscribe   context.CancelFunc
+	transcribeAborted bool
+	// cancelArmedUntil ends the window in which a cancel confirms the
+	// previous one; zero when no cancel is pending confirmation.
+	cancelArmedUntil time.Time
+	// recordingSince is when the current run started; zero between runs.
+	recordingSince time.Time
+
+	noPaste        atomic.Bool
+	idempotentStop atomic.Bool
+
+	// maxRecording stops a recording that outlives it; zero disables the limit.
+	maxRecording time.Duration
+	// pttTimeout is the maxRecording applied to ptt-start cycles under RunDaemon.
+	pttTimeout time.Duration
+	// timeoutCommits commits a recording that reaches maxRecording instead of
+	// cancelling it.
+	timeoutCommits bool
+	// overrides are the per-session flags forwarded with the request that
+	// started the current RunDaemon cycle; nil uses the configured values.
+	overrides *ipc.SessionOverrides
+	// noAudioAsError reports ErrNoAudioCaptured as a failure instead of a
+	// silent cancel.
+	noAudioAsError bool
+	// transcribingDelay holds back ShowTranscribing after stop; zero shows it
+	// immediately.
+	transcribingDelay time.Duration
+	// minCommitChars drops trimmed transcripts shorter than this many
+	// characters as if they were empty; zero disables the check.
+	minCommitChars int
+	// confirmCancel makes cancel two-phase with this confirm window; zero
+	// cancels on the first request.
+	confirmCancel time.Duration
+
+	// daemon is set while RunDaemon waits for and runs sessions.
+	daemon atomic.Bool
+	starts chan startRequest
 
 	actions chan action
+	// flushes requests a mid-recording commit; it is separate from actions so a
+	// pending flush never blocks a stop or cancel.
+	flushes chan struct{}
 }
 
 // NewController constructs a session controller with safe default fallbacks.
@@ -96,9 +172,64 @@ func NewController(
 		indicator:  indicator,
 		state:      fsm.StateIdle,
 		actions:    make(chan action, 1),
+		flushes:    make(chan struct{}, 1),
+		starts:     make(chan startRequest, 1),
 	}
 }
 
+// SetIdempotentStop makes stop/toggle requests during transcription succeed as
+// no-ops instead of failing with "already transcribing".
+func (c *Controller) SetIdempotentStop(enabled bool) {
+	c.idempotentStop.Store(enabled)
+}
+
+// SetMaxRecording bounds how long Run records before stopping on its own.
+// It must be called before Run.
+func (c *Controller) SetMaxRecording(limit time.Duration) {
+	c.maxRecording = limit
+}
+
+// SetPTTTimeout bounds ptt-start recordings started through RunDaemon.
+// It must be called before RunDaemon.
+func (c *Controller) SetPTTTimeout(limit time.Duration) {
+	c.pttTimeout = limit
+}
+
+// SetTimeoutCommits makes a recording that reaches its max duration stop and
+// commit; by default it is cancelled, since focus may have moved on by then.
+// It must be called before Run.
+func (c *Controller) SetTimeoutCommits(enabled bool) {
+	c.timeoutCommits = enabled
+}
+
+// SetNoAudioAsError makes a stop with no captured audio fail with
+// ErrNoAudioCaptured; by default it ends the session as a silent cancel.
+// It must be called before Run.
+func (c *Controller) SetNoAudioAsError(enabled bool) {
+	c.noAudioAsError = enabled
+}
+
+// SetTranscribingDelay holds back the transcribing indicator after stop so
+// transcriptions that finish within delay never show it. It must be called
+// before Run.
+func (c *Controller) SetTranscribingDelay(delay time.Duration) {
+	c.transcribingDelay = delay
+}
+
+// SetMinCommitChars drops final transcripts shorter than n characters after
+// trimming, failing the session with ErrTranscriptTooShort instead of
+// committing. It must be called before Run.
+func (c *Controller) SetMinCommitChars(n int) {
+	c.minCommitChars = n
+}
+
+// SetConfirmCancel makes cancel two-phase: the first request only prompts
+// through the indicator, and a second one within window discards the session.
+// Zero disables it. It must be called before Run.
+func (c *Controller) SetConfirmCancel(window time.Duration) {
+	c.confirmCancel = window
+}
+
 // State returns the current FSM state snapshot.
 func (c *Controller) State() fsm.State {
 	c.mu.RLock()
@@ -106,6 +237,13 @@ func (c *Controller) State() fsm.State {
 	return c.state
 }
 
+// LastTranscript returns the most recently committed transcript, if any.
+func (c *Controller) LastTranscript() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.last
+}
+
 // transition applies one FSM event to the controller state.
 func (c *Controller) transition(event fsm.Event) error {
 	c.mu.Lock()
@@ -119,9 +257,39 @@ func (c *Controller) transition(event fsm.Event) error {
 	return nil
 }
 
+// showTranscribingAfterDelay shows the transcribing indicator once
+// transcribingDelay passes. The returned func cancels a pending show, or waits
+// for one already in progress so it cannot land after later indicator updates.
+func (c *Controller) showTranscribingAfterDelay(ctx context.Context) func() {
+	if c.transcribingDelay <= 0 {
+		c.indicator.ShowTranscribing(ctx)
+		return func() {}
+	}
+
+	shown := make(chan struct{})
+	timer := time.AfterFunc(c.transcribingDelay, func() {
+		defer close(shown)
+		c.indicator.ShowTranscribing(ctx)
+	})
+	return func() {
+		if !timer.Stop() {
+			<-shown
+		}
+	}
+}
+
 // Run executes one owner lifecycle from start to stop/cancel/failure completion.
+// Each run gets a fresh session ID, carried to the transcriber through ctx and
+// attached to every controller log line for the run.
 func (c *Controller) Run(ctx context.Context) Result {
-	result := Result{StartedAt: time.Now()}
+	id := NewID()
+	result := Result{ID: id, StartedAt: time.Now()}
+	ctx = WithID(ctx, id)
+	if c.logger != nil {
+		logger := c.logger
+		c.logger = logger.With("session_id", id)
+		defer func() { c.logger = logger }()
+	}
 
 	if err := c.transition(fsm.EventStart); err != nil {
 		result.State = c.State()
@@ -129,10 +297,19 @@ func (c *Controller) Run(ctx context.Context) Result {
 		result.FinishedAt = time.Now()
 		return result
 	}
+	c.mu.Lock()
+	c.cancelArmedUntil = time.Time{}
+	c.recordingSince = result.StartedAt
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.recordingSince = time.Time{}
+		c.mu.Unlock()
+	}()
 
 	c.indicator.ShowRecording(ctx)
 
-	if err := c.transcribe.Start(ctx); err != nil {
+	if err := c.startTranscriber(ctx); err != nil {
 		c.indicator.ShowError(ctx, "Unable to start recording")
 		c.toErrorAndReset()
 		result.State = c.State()
@@ -142,146 +319,462 @@ func (c *Controller) Run(ctx context.Context) Result {
 		return result
 	}
 
+	if usingFallbackDevice(c.transcribe) {
+		c.indicator.CueFallback(ctx)
+	}
+
 	defer func() {
 		cleanupCtx, cancel := context.WithTimeout(context.Background(), 800*time.Millisecond)
 		defer cancel()
 		c.indicator.Hide(cleanupCtx)
 	}()
 
-	select {
-	case <-ctx.Done():
+	var timeout <-chan time.Time
+	if limit := c.maxRecording; limit > 0 {
+		timer := time.NewTimer(limit)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var a action
+	for a == 0 {
+		select {
+		case <-ctx.Done():
+			_ = c.transcribe.Cancel(context.Background())
+			c.indicator.CueCancel(context.Background())
+			c.indicator.ShowError(context.Background(), "Cancelled")
+			c.toErrorAndReset()
+			result.State = c.State()
+			result.Err = ctx.Err()
+			result.FinishedAt = time.Now()
+			result.FocusedMonitor = c.indicator.FocusedMonitor()
+			return result
+		case <-timeout:
+			// A missed key-up must not leave push-to-talk recording forever.
+			a = actionCancel
+			if c.timeoutCommits {
+				a = actionStop
+			}
+			if c.logger != nil {
+				c.logger.Warn("recording exceeded max duration", "max_ms", c.maxRecording.Milliseconds(), "commit", c.timeoutCommits)
+			}
+		case <-c.flushes:
+			c.flushTranscript(ctx)
+		case a = <-c.actions:
+		}
+	}
+
+	switch a {
+	case actionCancel:
 		_ = c.transcribe.Cancel(context.Background())
 		c.indicator.CueCancel(context.Background())
-		c.indicator.ShowError(context.Background(), "Cancelled")
-		c.toErrorAndReset()
+		_ = c.transition(fsm.EventCancel)
 		result.State = c.State()
-		result.Err = ctx.Err()
+		result.Cancelled = true
 		result.FinishedAt = time.Now()
 		result.FocusedMonitor = c.indicator.FocusedMonitor()
 		return result
-	case a := <-c.actions:
-		switch a {
-		case actionCancel:
-			_ = c.transcribe.Cancel(context.Background())
+	case actionStop:
+		stopCtx, abort := context.WithCancel(ctx)
+		defer abort()
+		c.setTranscribeAbort(abort)
+		if err := c.transition(fsm.EventStop); err != nil {
+			c.clearTranscribeAbort()
+			c.toErrorAndReset()
+			result.State = c.State()
+			result.Err = err
+			result.FinishedAt = time.Now()
+			result.FocusedMonitor = c.indicator.FocusedMonitor()
+			return result
+		}
+		settleTranscribing := c.showTranscribingAfterDelay(ctx)
+		stopResult, err := c.transcribe.StopAndTranscribe(stopCtx)
+		aborted := c.clearTranscribeAbort()
+		settleTranscribing()
+		if aborted {
 			c.indicator.CueCancel(context.Background())
 			_ = c.transition(fsm.EventCancel)
 			result.State = c.State()
 			result.Cancelled = true
+			result.AudioDevice = stopResult.AudioDevice
+			result.Model = stopResult.Model
+			result.LanguageCode = stopResult.LanguageCode
+			result.BytesCaptured = stopResult.BytesCaptured
 			result.FinishedAt = time.Now()
 			result.FocusedMonitor = c.indicator.FocusedMonitor()
 			return result
-		case actionStop:
-			if err := c.transition(fsm.EventStop); err != nil {
-				c.toErrorAndReset()
-				result.State = c.State()
-				result.Err = err
-				result.FinishedAt = time.Now()
-				result.FocusedMonitor = c.indicator.FocusedMonitor()
-				return result
-			}
-			c.indicator.ShowTranscribing(ctx)
-
-			stopResult, err := c.transcribe.StopAndTranscribe(ctx)
-			c.indicator.CueStop(context.Background())
-			if err != nil {
-				c.indicator.ShowError(context.Background(), "Speech recognition failed")
-				c.toErrorAndReset()
-				result.State = c.State()
-				result.Err = err
-				result.BytesCaptured = stopResult.BytesCaptured
-				result.AudioDevice = stopResult.AudioDevice
-				result.GRPCLatency = stopResult.GRPCLatency
-				result.FinishedAt = time.Now()
-				result.FocusedMonitor = c.indicator.FocusedMonitor()
-				return result
-			}
-
-			if strings.TrimSpace(stopResult.Transcript) == "" {
-				c.indicator.ShowError(context.Background(), "No speech detected")
-				c.toErrorAndReset()
-				result.State = c.State()
-				result.Err = ErrEmptyTranscript
-				result.Transcript = stopResult.Transcript
-				result.AudioDevice = stopResult.AudioDevice
-				result.BytesCaptured = stopResult.BytesCaptured
-				result.GRPCLatency = stopResult.GRPCLatency
-				result.FinishedAt = time.Now()
-				result.FocusedMonitor = c.indicator.FocusedMonitor()
-				return result
-			}
-
-			if err := c.commit.Commit(ctx, stopResult.Transcript); err != nil {
-				c.indicator.ShowError(context.Background(), "Output dispatch failed")
-				c.toErrorAndReset()
-				result.State = c.State()
-				result.Err = err
-				result.Transcript = stopResult.Transcript
-				result.AudioDevice = stopResult.AudioDevice
-				result.BytesCaptured = stopResult.BytesCaptured
-				result.GRPCLatency = stopResult.GRPCLatency
-				result.FinishedAt = time.Now()
-				result.FocusedMonitor = c.indicator.FocusedMonitor()
-				return result
-			}
-			c.indicator.CueComplete(context.Background())
-
-			if err := c.transition(fsm.EventTranscribed); err != nil {
-				result.State = c.State()
-				result.Err = err
-				result.Transcript = stopResult.Transcript
-				result.AudioDevice = stopResult.AudioDevice
-				result.BytesCaptured = stopResult.BytesCaptured
-				result.GRPCLatency = stopResult.GRPCLatency
-				result.FinishedAt = time.Now()
-				result.FocusedMonitor = c.indicator.FocusedMonitor()
-				return result
+		}
+		if errors.Is(err, ErrNoAudioCaptured) && !c.noAudioAsError {
+			c.indicator.CueCancel(context.Background())
+			_ = c.transition(fsm.EventCancel)
+			result.State = c.State()
+			result.Cancelled = true
+			result.AudioDevice = stopResult.AudioDevice
+			result.Model = stopResult.Model
+			result.LanguageCode = stopResult.LanguageCode
+			result.BytesCaptured = stopResult.BytesCaptured
+			result.FinishedAt = time.Now()
+			result.FocusedMonitor = c.indicator.FocusedMonitor()
+			return result
+		}
+		c.indicator.CueStop(context.Background())
+		if err != nil {
+			message := "Speech recognition failed"
+			if errors.Is(err, ErrNoAudioCaptured) {
+				message = "No audio captured"
 			}
+			c.indicator.ShowError(context.Background(), message)
+			c.toErrorAndReset()
+			result.State = c.State()
+			result.Err = err
+			result.BytesCaptured = stopResult.BytesCaptured
+			result.AudioDevice = stopResult.AudioDevice
+			result.Model = stopResult.Model
+			result.LanguageCode = stopResult.LanguageCode
+			result.GRPCLatency = stopResult.GRPCLatency
+			result.RequestID = stopResult.RequestID
+			result.ModelVersion = stopResult.ModelVersion
+			result.FinishedAt = time.Now()
+			result.FocusedMonitor = c.indicator.FocusedMonitor()
+			return result
+		}
 
+		if message, emptyErr := c.checkTranscriptLength(stopResult.Transcript); emptyErr != nil {
+			c.indicator.ShowError(context.Background(), message)
+			c.toErrorAndReset()
 			result.State = c.State()
+			result.Err = emptyErr
 			result.Transcript = stopResult.Transcript
 			result.AudioDevice = stopResult.AudioDevice
+			result.Model = stopResult.Model
+			result.LanguageCode = stopResult.LanguageCode
 			result.BytesCaptured = stopResult.BytesCaptured
 			result.GRPCLatency = stopResult.GRPCLatency
+			result.RequestID = stopResult.RequestID
+			result.ModelVersion = stopResult.ModelVersion
 			result.FinishedAt = time.Now()
 			result.FocusedMonitor = c.indicator.FocusedMonitor()
 			return result
-		default:
+		}
+
+		if err := c.commitTranscript(ctx, stopResult.Transcript); err != nil {
+			message, err := c.recoverTranscript(ctx, stopResult.Transcript, err)
+			c.indicator.ShowError(context.Background(), message)
 			c.toErrorAndReset()
 			result.State = c.State()
-			result.Err = fmt.Errorf("unknown action %d", a)
+			result.Err = err
+			result.Transcript = stopResult.Transcript
+			result.AudioDevice = stopResult.AudioDevice
+			result.Model = stopResult.Model
+			result.LanguageCode = stopResult.LanguageCode
+			result.BytesCaptured = stopResult.BytesCaptured
+			result.GRPCLatency = stopResult.GRPCLatency
+			result.RequestID = stopResult.RequestID
+			result.ModelVersion = stopResult.ModelVersion
 			result.FinishedAt = time.Now()
 			result.FocusedMonitor = c.indicator.FocusedMonitor()
 			return result
 		}
+		c.mu.Lock()
+		c.last = stopResult.Transcript
+		c.mu.Unlock()
+		c.indicator.CueComplete(context.Background())
+
+		if err := c.transition(fsm.EventTranscribed); err != nil {
+			result.State = c.State()
+			result.Err = err
+			result.Transcript = stopResult.Transcript
+			result.AudioDevice = stopResult.AudioDevice
+			result.Model = stopResult.Model
+			result.LanguageCode = stopResult.LanguageCode
+			result.BytesCaptured = stopResult.BytesCaptured
+			result.GRPCLatency = stopResult.GRPCLatency
+			result.RequestID = stopResult.RequestID
+			result.ModelVersion = stopResult.ModelVersion
+			result.FinishedAt = time.Now()
+			result.FocusedMonitor = c.indicator.FocusedMonitor()
+			return result
+		}
+
+		result.State = c.State()
+		result.Transcript = stopResult.Transcript
+		result.AudioDevice = stopResult.AudioDevice
+		result.Model = stopResult.Model
+		result.LanguageCode = stopResult.LanguageCode
+		result.BytesCaptured = stopResult.BytesCaptured
+		result.GRPCLatency = stopResult.GRPCLatency
+		result.RequestID = stopResult.RequestID
+		result.ModelVersion = stopResult.ModelVersion
+		result.FinishedAt = time.Now()
+		result.FocusedMonitor = c.indicator.FocusedMonitor()
+		return result
+	default:
+		c.toErrorAndReset()
+		result.State = c.State()
+		result.Err = fmt.Errorf("unknown action %d", a)
+		result.FinishedAt = time.Now()
+		result.FocusedMonitor = c.indicator.FocusedMonitor()
+		return result
+	}
+}
+
+// RunDaemon keeps the owner alive across sessions: each toggle or ptt-start
+// received while idle runs one Run lifecycle, reported through onResult. It
+// returns when ctx ends or, when idleTimeout is positive, after that long
+// without a session.
+func (c *Controller) RunDaemon(ctx context.Context, idleTimeout time.Duration, onResult func(Result)) {
+	c.daemon.Store(true)
+	defer c.daemon.Store(false)
+
+	for {
+		var idle <-chan time.Time
+		var timer *time.Timer
+		if idleTimeout > 0 {
+			timer = time.NewTimer(idleTimeout)
+			idle = timer.C
+		}
+
+		var start startRequest
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			return
+		case <-idle:
+			if c.logger != nil {
+				c.logger.Info("owner idle timeout reached; exiting", "idle_ms", idleTimeout.Milliseconds())
+			}
+			return
+		case start = <-c.starts:
+			stopTimer(timer)
+		}
+
+		// Each cycle starts clean: per-session overrides and stale requests from
+		// the previous session must not leak into this one.
+		c.noPaste.Store(start.noPaste)
+		c.overrides = start.overrides
+		c.maxRecording = 0
+		if start.ptt {
+			c.maxRecording = c.pttTimeout
+		}
+		c.drainRequests()
+
+		result := c.Run(ctx)
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+}
+
+// drainRequests discards queued stop/cancel/flush requests between sessions.
+func (c *Controller) drainRequests() {
+	for {
+		select {
+		case <-c.actions:
+		case <-c.flushes:
+		default:
+			return
+		}
 	}
 }
 
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+// startTranscriber starts capture and retries once through the error state when
+// the transcriber reports a recoverable failure (for example a transient Riva
+// dial error before any audio was captured). Forwarded session overrides are
+// applied first.
+func (c *Controller) startTranscriber(ctx context.Context) error {
+	if configurer, ok := c.transcribe.(sessionConfigurer); ok {
+		if err := configurer.ConfigureSession(c.overrides); err != nil {
+			return fmt.Errorf("apply session overrides: %w", err)
+		}
+	}
+	err := c.transcribe.Start(ctx)
+	if err == nil || !IsRecoverable(err) {
+		return err
+	}
+
+	if c.logger != nil {
+		c.logger.Warn("transcriber start failed; retrying", "error", err.Error())
+	}
+	_ = c.transition(fsm.EventFail)
+	if retryErr := c.transition(fsm.EventRetry); retryErr != nil {
+		return errors.Join(err, retryErr)
+	}
+	return c.transcribe.Start(ctx)
+}
+
+// commitTranscript commits through the clipboard-only path when a stop request
+// asked for --no-paste and the committer supports it.
+func (c *Controller) commitTranscript(ctx context.Context, transcript string) error {
+	if c.noPaste.Load() {
+		if committer, ok := c.commit.(clipboardOnlyCommitter); ok {
+			return committer.CommitClipboardOnly(ctx, transcript)
+		}
+	}
+	return c.commit.Commit(ctx, transcript)
+}
+
+// flushTranscript commits text finalized since the previous flush while the
+// recording continues. Failures are logged and leave the session recording.
+func (c *Controller) flushTranscript(ctx context.Context) {
+	f, ok := c.transcribe.(flusher)
+	if !ok {
+		return
+	}
+	text, err := f.Flush(ctx)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn("transcript flush failed", "error", err.Error())
+		}
+		return
+	}
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	if err := c.commitTranscript(ctx, text); err != nil {
+		_, err = c.recoverTranscript(ctx, text, err)
+		if c.logger != nil {
+			c.logger.Error("flushed transcript commit failed", "error", err.Error())
+		}
+		return
+	}
+	c.mu.Lock()
+	c.last = text
+	c.mu.Unlock()
+	c.indicator.CueComplete(context.Background())
+}
+
+// checkTranscriptLength rejects a blank transcript, or one shorter than
+// minCommitChars after trimming, returning the indicator message and error.
+func (c *Controller) checkTranscriptLength(transcript string) (string, error) {
+	trimmed := strings.TrimSpace(transcript)
+	if trimmed == "" {
+		return "No speech detected", ErrEmptyTranscript
+	}
+	if c.minCommitChars > 0 && utf8.RuneCountInString(trimmed) < c.minCommitChars {
+		return "Transcript too short", ErrTranscriptTooShort
+	}
+	return "", nil
+}
+
+// recoverTranscript saves the transcript through the committer after a failed
+// commit, returning the indicator message and error to surface.
+func (c *Controller) recoverTranscript(ctx context.Context, transcript string, commitErr error) (string, error) {
+	const message = "Output dispatch failed"
+
+	committer, ok := c.commit.(recoveringCommitter)
+	if !ok {
+		return message, commitErr
+	}
+	path, err := committer.Recover(ctx, transcript)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Error("transcript recovery failed", "error", err.Error())
+		}
+		return message, commitErr
+	}
+	if path == "" {
+		return message, commitErr
+	}
+	return message + "; transcript saved to " + path, fmt.Errorf("%w (transcript saved to %s)", commitErr, path)
+}
+
 // Handle serves IPC commands for the active owner session.
 func (c *Controller) Handle(_ context.Context, req ipc.Request) ipc.Response {
 	switch req.Command {
 	case "status":
 		return ipc.Response{OK: true, State: string(c.State()), Message: "status"}
 	case "toggle":
-		return c.requestStop("toggle")
+		if c.idleDaemon() {
+			return c.requestStart(startRequest{noPaste: req.NoPaste, overrides: req.Overrides})
+		}
+		return c.requestStop("toggle", req.NoPaste)
+	case "ptt-start":
+		if c.idleDaemon() {
+			return c.requestStart(startRequest{ptt: true, noPaste: req.NoPaste, overrides: req.Overrides})
+		}
+		// Key-down auto-repeat re-sends ptt-start while the owner records.
+		state := c.State()
+		if state == fsm.StateRecording {
+			return ipc.Response{OK: true, State: string(state), Message: "already recording"}
+		}
+		return ipc.Response{OK: true, State: string(state), Message: fmt.Sprintf("ptt-start ignored while %s", state)}
 	case "stop":
-		return c.requestStop("stop")
+		return c.requestStop("stop", req.NoPaste)
 	case "cancel":
 		return c.requestCancel()
+	case "flush":
+		return c.requestFlush()
+	case "partial":
+		return c.partialStatus()
+	case "warmup-status":
+		return ipc.Response{OK: true, State: string(c.State()), Message: warmStatus(c.transcribe)}
+	case "last":
+		last := c.LastTranscript()
+		if last == "" {
+			return ipc.Response{OK: false, State: string(c.State()), Error: "no transcript committed yet"}
+		}
+		return ipc.Response{OK: true, State: string(c.State()), Message: last}
 	default:
 		return ipc.Response{OK: false, State: string(c.State()), Error: fmt.Sprintf("unknown command: %s", req.Command)}
 	}
 }
 
+// partialStatus reports the live interim text, elapsed time, and captured
+// bytes of the current run; outside a run only the state is set.
+func (c *Controller) partialStatus() ipc.Response {
+	c.mu.RLock()
+	state := c.state
+	since := c.recordingSince
+	c.mu.RUnlock()
+
+	resp := ipc.Response{OK: true, State: string(state)}
+	if since.IsZero() {
+		return resp
+	}
+	resp.ElapsedMS = time.Since(since).Milliseconds()
+	resp.Message, resp.Bytes = progress(c.transcribe)
+	return resp
+}
+
+// idleDaemon reports whether a RunDaemon owner is waiting for its next session.
+func (c *Controller) idleDaemon() bool {
+	return c.daemon.Load() && c.State() == fsm.StateIdle
+}
+
+// requestStart asks an idle daemon owner to begin a session.
+func (c *Controller) requestStart(req startRequest) ipc.Response {
+	state := c.State()
+	select {
+	case c.starts <- req:
+		return ipc.Response{OK: true, State: string(state), Message: "start requested"}
+	default:
+		return ipc.Response{OK: true, State: string(state), Message: "start already requested"}
+	}
+}
+
 // requestStop enqueues a stop action when state permits it.
-func (c *Controller) requestStop(source string) ipc.Response {
+func (c *Controller) requestStop(source string, noPaste bool) ipc.Response {
 	state := c.State()
 	if state == fsm.StateTranscribing {
+		if c.idempotentStop.Load() {
+			return ipc.Response{OK: true, State: string(state), Message: "already transcribing"}
+		}
 		return ipc.Response{OK: false, State: string(state), Error: "already transcribing"}
 	}
 	if state != fsm.StateRecording {
 		return ipc.Response{OK: false, State: string(state), Error: fmt.Sprintf("cannot %s from state %s", source, state)}
 	}
 
+	if noPaste {
+		c.noPaste.Store(true)
+	}
+
 	select {
 	case c.actions <- actionStop:
 		return ipc.Response{OK: true, State: string(state), Message: "stop requested"}
@@ -290,15 +783,48 @@ func (c *Controller) requestStop(source string) ipc.Response {
 	}
 }
 
-// requestCancel enqueues a cancel action when state permits it.
+// requestFlush enqueues a mid-recording commit when state permits it.
+func (c *Controller) requestFlush() ipc.Response {
+	state := c.State()
+	if state != fsm.StateRecording {
+		return ipc.Response{OK: false, State: string(state), Error: fmt.Sprintf("cannot flush from state %s", state)}
+	}
+	if _, ok := c.transcribe.(flusher); !ok {
+		return ipc.Response{OK: false, State: string(state), Error: "flush is not supported by this transcriber"}
+	}
+
+	select {
+	case c.flushes <- struct{}{}:
+		return ipc.Response{OK: true, State: string(state), Message: "flush requested"}
+	default:
+		return ipc.Response{OK: true, State: string(state), Message: "flush already requested"}
+	}
+}
+
+// requestCancel enqueues a cancel action when state permits it. With
+// confirm-cancel enabled, a cancel outside the confirm window only prompts.
 func (c *Controller) requestCancel() ipc.Response {
 	state := c.State()
 	if state == fsm.StateTranscribing {
-		return ipc.Response{OK: false, State: string(state), Error: "cannot cancel while transcribing"}
+		if c.transcriptionAbortable() && !c.confirmCancelRequest() {
+			return c.promptCancel(state)
+		}
+		requested, first := c.abortTranscription()
+		if !requested {
+			// The transcript is already being committed.
+			return ipc.Response{OK: false, State: string(state), Error: "cannot cancel while committing"}
+		}
+		if !first {
+			return ipc.Response{OK: true, State: string(state), Message: "cancel already requested"}
+		}
+		return ipc.Response{OK: true, State: string(state), Message: "cancel requested"}
 	}
 	if state != fsm.StateRecording {
 		return ipc.Response{OK: false, State: string(state), Error: fmt.Sprintf("cannot cancel from state %s", state)}
 	}
+	if !c.confirmCancelRequest() {
+		return c.promptCancel(state)
+	}
 
 	select {
 	case c.actions <- actionCancel:
@@ -308,12 +834,87 @@ func (c *Controller) requestCancel() ipc.Response {
 	}
 }
 
+// confirmCancelRequest reports whether a cancel request should discard the
+// session. Under confirm-cancel, a request outside the confirm window instead
+// opens a new one and reports false.
+func (c *Controller) confirmCancelRequest() bool {
+	if c.confirmCancel <= 0 {
+		return true
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.cancelArmedUntil.IsZero() && now.Before(c.cancelArmedUntil) {
+		c.cancelArmedUntil = time.Time{}
+		return true
+	}
+	c.cancelArmedUntil = now.Add(c.confirmCancel)
+	return false
+}
+
+// promptCancel asks for the confirming second cancel.
+func (c *Controller) promptCancel(state fsm.State) ipc.Response {
+	if prompter, ok := c.indicator.(cancelPrompter); ok {
+		prompter.ShowConfirmCancel(context.Background(), c.confirmCancel)
+	}
+	return ipc.Response{OK: true, State: string(state), Message: "press cancel again to confirm"}
+}
+
+// transcriptionAbortable reports whether a cancel would abort a running
+// transcript collection, as opposed to arriving after one was aborted or
+// while the transcript is being committed.
+func (c *Controller) transcriptionAbortable() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.abortTranscribe != nil && !c.transcribeAborted
+}
+
+// setTranscribeAbort arms cancel-while-transcribing for one StopAndTranscribe.
+func (c *Controller) setTranscribeAbort(abort context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.abortTranscribe = abort
+	c.transcribeAborted = false
+}
+
+// clearTranscribeAbort disarms cancel-while-transcribing and reports whether
+// cancel fired while it was armed.
+func (c *Controller) clearTranscribeAbort() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.abortTranscribe = nil
+	aborted := c.transcribeAborted
+	c.transcribeAborted = false
+	return aborted
+}
+
+// abortTranscription cancels the in-flight transcript collection. requested
+// is false when no collection is running; first is false for repeat cancels.
+func (c *Controller) abortTranscription() (requested bool, first bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.abortTranscribe == nil {
+		return false, false
+	}
+	if c.transcribeAborted {
+		return true, false
+	}
+	c.transcribeAborted = true
+	c.abortTranscribe()
+	return true, true
+}
+
 // toErrorAndReset transitions to error and back to idle best-effort.
 func (c *Controller) toErrorAndReset() {
 	_ = c.transition(fsm.EventFail)
 	_ = c.transition(fsm.EventReset)
 }
 
+// IsRecoverable reports whether an error was marked retryable by the transcriber.
+func IsRecoverable(err error) bool {
+	return errors.Is(err, ErrRecoverable)
+}
+
 // IsPipelineUnavailable reports whether an error represents missing pipeline wiring.
 func IsPipelineUnavailable(err error) bool {
 	return errors.Is(err, ErrPipelineUnavailable)
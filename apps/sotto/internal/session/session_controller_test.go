@@ -3,11 +3,16 @@ package session
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/rbright/sotto/internal/config"
 	"github.com/rbright/sotto/internal/fsm"
 	"github.com/rbright/sotto/internal/ipc"
+	"github.com/rbright/sotto/internal/output"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,7 +49,84 @@ func TestRequestStopAndCancelStateGuards(t *testing.T) {
 
 	cancelFromTranscribing := ctrl.Handle(context.Background(), ipc.Request{Command: "cancel"})
 	require.False(t, cancelFromTranscribing.OK)
-	require.Contains(t, cancelFromTranscribing.Error, "cannot cancel while transcribing")
+	require.Contains(t, cancelFromTranscribing.Error, "cannot cancel while committing")
+}
+
+func TestIdempotentStopWhileTranscribing(t *testing.T) {
+	for _, idempotent := range []bool{false, true} {
+		ctrl := NewController(nil, &fakeTranscriber{}, nil, &fakeIndicator{})
+		ctrl.SetIdempotentStop(idempotent)
+		ctrl.mu.Lock()
+		ctrl.state = fsm.StateTranscribing
+		ctrl.mu.Unlock()
+
+		for _, command := range []string{"stop", "toggle"} {
+			resp := ctrl.Handle(context.Background(), ipc.Request{Command: command})
+			require.Equal(t, idempotent, resp.OK, command)
+			require.Equal(t, string(fsm.StateTranscribing), resp.State, command)
+			if idempotent {
+				require.Equal(t, "already transcribing", resp.Message, command)
+			} else {
+				require.Equal(t, "already transcribing", resp.Error, command)
+			}
+		}
+		require.Empty(t, ctrl.actions)
+	}
+}
+
+func TestHandlePTTStartIsNoopForActiveOwner(t *testing.T) {
+	ctrl := NewController(nil, &fakeTranscriber{}, nil, &fakeIndicator{})
+	ctrl.mu.Lock()
+	ctrl.state = fsm.StateRecording
+	ctrl.mu.Unlock()
+
+	resp := ctrl.Handle(context.Background(), ipc.Request{Command: "ptt-start"})
+	require.True(t, resp.OK)
+	require.Equal(t, string(fsm.StateRecording), resp.State)
+	require.Equal(t, "already recording", resp.Message)
+	require.Empty(t, ctrl.actions)
+
+	ctrl.mu.Lock()
+	ctrl.state = fsm.StateTranscribing
+	ctrl.mu.Unlock()
+
+	resp = ctrl.Handle(context.Background(), ipc.Request{Command: "ptt-start"})
+	require.True(t, resp.OK)
+	require.Equal(t, string(fsm.StateTranscribing), resp.State)
+	require.Equal(t, "ptt-start ignored while transcribing", resp.Message)
+	require.Empty(t, ctrl.actions)
+}
+
+func TestRunEndsAfterMaxRecording(t *testing.T) {
+	for _, commits := range []bool{false, true} {
+		var committed string
+		ctrl := NewController(
+			nil,
+			&fakeTranscriber{transcript: "held too long"},
+			CommitFunc(func(_ context.Context, transcript string) error {
+				committed = transcript
+				return nil
+			}),
+			&fakeIndicator{},
+		)
+		ctrl.SetMaxRecording(20 * time.Millisecond)
+		ctrl.SetTimeoutCommits(commits)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		result := ctrl.Run(ctx)
+		cancel()
+		require.NoError(t, result.Err)
+		require.Equal(t, fsm.StateIdle, result.State)
+		if !commits {
+			// By default a timed-out recording is discarded rather than pasted
+			// into whatever window has focus by then.
+			require.True(t, result.Cancelled)
+			require.Empty(t, committed)
+			continue
+		}
+		require.False(t, result.Cancelled)
+		require.Equal(t, "held too long", committed)
+	}
 }
 
 func TestRequestStopAndCancelAlreadyRequested(t *testing.T) {
@@ -55,7 +137,7 @@ func TestRequestStopAndCancelAlreadyRequested(t *testing.T) {
 	ctrl.mu.Unlock()
 
 	ctrl.actions <- actionStop
-	stop := ctrl.requestStop("stop")
+	stop := ctrl.requestStop("stop", false)
 	require.True(t, stop.OK)
 	require.Equal(t, "stop already requested", stop.Message)
 
@@ -66,6 +148,96 @@ func TestRequestStopAndCancelAlreadyRequested(t *testing.T) {
 	require.Equal(t, "cancel already requested", cancel.Message)
 }
 
+// promptingIndicator records confirm-cancel prompts.
+type promptingIndicator struct {
+	fakeIndicator
+	prompts atomic.Int32
+}
+
+func (p *promptingIndicator) ShowConfirmCancel(context.Context, time.Duration) { p.prompts.Add(1) }
+
+func TestRequestCancelConfirmWindow(t *testing.T) {
+	ind := &promptingIndicator{}
+	ctrl := NewController(nil, &fakeTranscriber{}, nil, ind)
+	ctrl.SetConfirmCancel(time.Minute)
+
+	ctrl.mu.Lock()
+	ctrl.state = fsm.StateRecording
+	ctrl.mu.Unlock()
+
+	first := ctrl.requestCancel()
+	require.True(t, first.OK)
+	require.Equal(t, "press cancel again to confirm", first.Message)
+	require.Equal(t, int32(1), ind.prompts.Load())
+	require.Empty(t, ctrl.actions)
+
+	// A second cancel after the window lapses prompts again.
+	ctrl.mu.Lock()
+	ctrl.cancelArmedUntil = time.Now().Add(-time.Millisecond)
+	ctrl.mu.Unlock()
+	again := ctrl.requestCancel()
+	require.Equal(t, "press cancel again to confirm", again.Message)
+	require.Equal(t, int32(2), ind.prompts.Load())
+	require.Empty(t, ctrl.actions)
+
+	confirmed := ctrl.requestCancel()
+	require.True(t, confirmed.OK)
+	require.Equal(t, "cancel requested", confirmed.Message)
+	require.Equal(t, actionCancel, <-ctrl.actions)
+}
+
+func TestRunConfirmCancelSingleCancelKeepsSession(t *testing.T) {
+	var committed string
+	committer := CommitFunc(func(_ context.Context, text string) error {
+		committed = text
+		return nil
+	})
+	ind := &promptingIndicator{}
+	ctrl := NewController(nil, &fakeTranscriber{transcript: "keep me"}, committer, ind)
+	ctrl.SetConfirmCancel(time.Minute)
+
+	resultCh := make(chan Result, 1)
+	go func() { resultCh <- ctrl.Run(context.Background()) }()
+
+	waitForState(t, ctrl, fsm.StateRecording)
+	resp := ctrl.Handle(context.Background(), ipc.Request{Command: "cancel"})
+	require.True(t, resp.OK)
+	require.Equal(t, fsm.StateRecording, ctrl.State())
+
+	require.True(t, ctrl.Handle(context.Background(), ipc.Request{Command: "stop"}).OK)
+	result := <-resultCh
+	require.NoError(t, result.Err)
+	require.False(t, result.Cancelled)
+	require.Equal(t, "keep me", committed)
+	require.Equal(t, int32(1), ind.prompts.Load())
+	require.Equal(t, int32(0), ind.cancelCues.Load())
+}
+
+func TestRunConfirmCancelDoubleCancelDiscards(t *testing.T) {
+	var commits atomic.Int32
+	transcriber := &fakeTranscriber{transcript: "discard me"}
+	ind := &promptingIndicator{}
+	ctrl := NewController(nil, transcriber, CommitFunc(func(context.Context, string) error {
+		commits.Add(1)
+		return nil
+	}), ind)
+	ctrl.SetConfirmCancel(time.Minute)
+
+	resultCh := make(chan Result, 1)
+	go func() { resultCh <- ctrl.Run(context.Background()) }()
+
+	waitForState(t, ctrl, fsm.StateRecording)
+	require.Equal(t, "press cancel again to confirm", ctrl.Handle(context.Background(), ipc.Request{Command: "cancel"}).Message)
+	require.Equal(t, "cancel requested", ctrl.Handle(context.Background(), ipc.Request{Command: "cancel"}).Message)
+
+	result := <-resultCh
+	require.True(t, result.Cancelled)
+	require.Equal(t, fsm.StateIdle, result.State)
+	require.Zero(t, commits.Load())
+	require.Equal(t, int32(1), transcriber.cancelCalls.Load())
+	require.Equal(t, int32(1), ind.cancelCues.Load())
+}
+
 func TestRunStartFailure(t *testing.T) {
 	transcriber := &fakeTranscriber{startErr: errors.New("start failed")}
 	indicator := &fakeIndicator{}
@@ -79,6 +251,63 @@ func TestRunStartFailure(t *testing.T) {
 	require.Equal(t, int32(0), indicator.completeCues.Load())
 }
 
+// flakyStartTranscriber fails Start with each queued error in turn, then
+// succeeds.
+type flakyStartTranscriber struct {
+	fakeTranscriber
+	startErrs []error
+	starts    int
+}
+
+func (f *flakyStartTranscriber) Start(context.Context) error {
+	f.starts++
+	if f.starts <= len(f.startErrs) {
+		return f.startErrs[f.starts-1]
+	}
+	return nil
+}
+
+func TestRunRetriesRecoverableStartOnce(t *testing.T) {
+	transient := Recoverable(errors.New("riva not reachable"))
+
+	t.Run("recovers", func(t *testing.T) {
+		transcriber := &flakyStartTranscriber{startErrs: []error{transient}}
+		ctrl := NewController(nil, transcriber, nil, &fakeIndicator{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		resultCh := make(chan Result, 1)
+		go func() { resultCh <- ctrl.Run(ctx) }()
+
+		waitForState(t, ctrl, fsm.StateRecording)
+		require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "cancel"}).OK)
+		result := <-resultCh
+		require.NoError(t, result.Err)
+		require.True(t, result.Cancelled)
+		require.Equal(t, 2, transcriber.starts)
+	})
+
+	t.Run("gives up after one retry", func(t *testing.T) {
+		transcriber := &flakyStartTranscriber{startErrs: []error{transient, transient}}
+		ctrl := NewController(nil, transcriber, nil, &fakeIndicator{})
+
+		result := ctrl.Run(context.Background())
+		require.ErrorIs(t, result.Err, ErrRecoverable)
+		require.Equal(t, fsm.StateIdle, result.State)
+		require.Equal(t, 2, transcriber.starts)
+	})
+
+	t.Run("does not retry other failures", func(t *testing.T) {
+		transcriber := &flakyStartTranscriber{startErrs: []error{errors.New("unknown model")}}
+		ctrl := NewController(nil, transcriber, nil, &fakeIndicator{})
+
+		result := ctrl.Run(context.Background())
+		require.EqualError(t, result.Err, "unknown model")
+		require.Equal(t, fsm.StateIdle, result.State)
+		require.Equal(t, 1, transcriber.starts)
+	})
+}
+
 func TestRunCommitFailure(t *testing.T) {
 	indicator := &fakeIndicator{}
 	ctrl := NewController(
@@ -107,6 +336,41 @@ func TestRunCommitFailure(t *testing.T) {
 	require.Equal(t, int32(0), indicator.completeCues.Load())
 }
 
+func TestRunCommitFailureWritesRecoveryFile(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	cfg := config.Default()
+	cfg.Paste.Enable = false
+	cfg.Clipboard = config.CommandConfig{Argv: []string{"false"}}
+	ctrl := NewController(nil, &fakeTranscriber{transcript: "hello world"}, output.NewCommitter(cfg, nil), &fakeIndicator{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resultCh := make(chan Result, 1)
+	go func() {
+		resultCh <- ctrl.Run(ctx)
+	}()
+
+	waitForState(t, ctrl, fsm.StateRecording)
+	resp := ctrl.Handle(ctx, ipc.Request{Command: "stop"})
+	require.True(t, resp.OK)
+
+	result := <-resultCh
+	require.Error(t, result.Err)
+	require.Contains(t, result.Err.Error(), "set clipboard")
+	require.Contains(t, result.Err.Error(), "transcript saved to")
+
+	matches, err := filepath.Glob(filepath.Join(stateDir, "sotto", "recovery", "transcript-*.txt"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.Contains(t, result.Err.Error(), matches[0])
+	saved, err := os.ReadFile(matches[0])
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(saved))
+}
+
 func TestRunContextCancelled(t *testing.T) {
 	indicator := &fakeIndicator{}
 	ctrl := NewController(nil, &fakeTranscriber{}, nil, indicator)
@@ -176,6 +440,238 @@ func TestCommitFuncDelegates(t *testing.T) {
 	require.True(t, called)
 }
 
+func TestHandleLastReturnsCommittedTranscript(t *testing.T) {
+	ctrl := NewController(nil, &fakeTranscriber{transcript: "hello world"}, nil, &fakeIndicator{})
+
+	before := ctrl.Handle(context.Background(), ipc.Request{Command: "last"})
+	require.False(t, before.OK)
+	require.Contains(t, before.Error, "no transcript")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resultCh := make(chan Result, 1)
+	go func() {
+		resultCh <- ctrl.Run(ctx)
+	}()
+
+	waitForState(t, ctrl, fsm.StateRecording)
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "stop"}).OK)
+	require.NoError(t, (<-resultCh).Err)
+
+	last := ctrl.Handle(ctx, ipc.Request{Command: "last"})
+	require.True(t, last.OK)
+	require.Equal(t, "hello world", last.Message)
+	require.Equal(t, "hello world", ctrl.LastTranscript())
+}
+
+// warmTranscriber reports a fixed pre-warm state.
+type warmTranscriber struct {
+	fakeTranscriber
+	warm string
+}
+
+func (w *warmTranscriber) WarmStatus() string {
+	return w.warm
+}
+
+func TestHandleWarmupStatus(t *testing.T) {
+	warm := NewController(nil, &warmTranscriber{warm: "ready"}, nil, &fakeIndicator{})
+	resp := warm.Handle(context.Background(), ipc.Request{Command: "warmup-status"})
+	require.True(t, resp.OK)
+	require.Equal(t, string(fsm.StateIdle), resp.State)
+	require.Equal(t, "ready", resp.Message)
+
+	cold := NewController(nil, &fakeTranscriber{}, nil, &fakeIndicator{})
+	resp = cold.Handle(context.Background(), ipc.Request{Command: "warmup-status"})
+	require.True(t, resp.OK)
+	require.Equal(t, "cold", resp.Message)
+}
+
+// flushingTranscriber hands out one queued batch per Flush call.
+type flushingTranscriber struct {
+	fakeTranscriber
+	batches []string
+	flushes atomic.Int32
+}
+
+func (f *flushingTranscriber) Flush(context.Context) (string, error) {
+	f.flushes.Add(1)
+	if len(f.batches) == 0 {
+		return "", nil
+	}
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+	return batch, nil
+}
+
+func TestFlushCommitsIncrementallyWhileRecording(t *testing.T) {
+	commits := make(chan string, 4)
+	transcriber := &flushingTranscriber{
+		fakeTranscriber: fakeTranscriber{transcript: "final words"},
+		batches:         []string{"first part ", "", "second part "},
+	}
+	ind := &fakeIndicator{}
+	ctrl := NewController(nil, transcriber, CommitFunc(func(_ context.Context, text string) error {
+		commits <- text
+		return nil
+	}), ind)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resultCh := make(chan Result, 1)
+	go func() {
+		resultCh <- ctrl.Run(ctx)
+	}()
+
+	waitForState(t, ctrl, fsm.StateRecording)
+	resp := ctrl.Handle(ctx, ipc.Request{Command: "flush"})
+	require.True(t, resp.OK, resp.Error)
+	require.Equal(t, "first part ", <-commits)
+	require.Equal(t, "first part ", ctrl.LastTranscript())
+	require.Equal(t, fsm.StateRecording, ctrl.State())
+
+	// An empty flush commits nothing; the next one carries only new text.
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "flush"}).OK)
+	require.Eventually(t, func() bool { return transcriber.flushes.Load() == 2 }, time.Second, time.Millisecond)
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "flush"}).OK)
+	require.Equal(t, "second part ", <-commits)
+
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "stop"}).OK)
+	result := <-resultCh
+	require.NoError(t, result.Err)
+	require.Equal(t, "final words", <-commits)
+	require.Equal(t, int32(3), ind.completeCues.Load())
+}
+
+func TestRequestFlushStateGuards(t *testing.T) {
+	ctrl := NewController(nil, &flushingTranscriber{}, nil, &fakeIndicator{})
+	resp := ctrl.Handle(context.Background(), ipc.Request{Command: "flush"})
+	require.False(t, resp.OK)
+	require.Contains(t, resp.Error, "cannot flush from state idle")
+
+	ctrl.mu.Lock()
+	ctrl.state = fsm.StateRecording
+	ctrl.mu.Unlock()
+	require.Equal(t, "flush requested", ctrl.requestFlush().Message)
+	require.Equal(t, "flush already requested", ctrl.requestFlush().Message)
+	require.Empty(t, ctrl.actions)
+
+	unsupported := NewController(nil, &fakeTranscriber{}, nil, &fakeIndicator{})
+	unsupported.mu.Lock()
+	unsupported.state = fsm.StateRecording
+	unsupported.mu.Unlock()
+	resp = unsupported.requestFlush()
+	require.False(t, resp.OK)
+	require.Contains(t, resp.Error, "not supported")
+}
+
+func TestRunDaemonRunsMultipleSessions(t *testing.T) {
+	commits := make(chan string, 4)
+	ctrl := NewController(nil, &fakeTranscriber{transcript: "cycle"}, CommitFunc(func(_ context.Context, text string) error {
+		commits <- text
+		return nil
+	}), &fakeIndicator{})
+	ctrl.SetPTTTimeout(20 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make(chan Result, 4)
+	done := make(chan struct{})
+	go func() {
+		ctrl.RunDaemon(ctx, 0, func(result Result) { results <- result })
+		close(done)
+	}()
+	require.Eventually(t, ctrl.daemon.Load, time.Second, time.Millisecond)
+
+	for range 2 {
+		require.Equal(t, "start requested", ctrl.Handle(ctx, ipc.Request{Command: "toggle"}).Message)
+		waitForState(t, ctrl, fsm.StateRecording)
+		require.Equal(t, "stop requested", ctrl.Handle(ctx, ipc.Request{Command: "toggle"}).Message)
+
+		result := <-results
+		require.NoError(t, result.Err)
+		require.Equal(t, fsm.StateIdle, result.State)
+		require.Equal(t, "cycle", <-commits)
+	}
+
+	// A ptt-start cycle picks up the push-to-talk limit and ends on its own.
+	require.Equal(t, "start requested", ctrl.Handle(ctx, ipc.Request{Command: "ptt-start"}).Message)
+	result := <-results
+	require.NoError(t, result.Err)
+	require.True(t, result.Cancelled)
+	require.Empty(t, commits)
+
+	cancel()
+	<-done
+	require.False(t, ctrl.daemon.Load())
+}
+
+// configuringTranscriber records the overrides applied before each Start.
+type configuringTranscriber struct {
+	fakeTranscriber
+	applied chan *ipc.SessionOverrides
+}
+
+func (c *configuringTranscriber) ConfigureSession(overrides *ipc.SessionOverrides) error {
+	c.applied <- overrides
+	return nil
+}
+
+func TestRunDaemonAppliesForwardedOverridesPerSession(t *testing.T) {
+	transcriber := &configuringTranscriber{fakeTranscriber: fakeTranscriber{transcript: "cycle"}, applied: make(chan *ipc.SessionOverrides, 2)}
+	ctrl := NewController(nil, transcriber, nil, &fakeIndicator{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make(chan Result, 2)
+	done := make(chan struct{})
+	go func() {
+		ctrl.RunDaemon(ctx, 0, func(result Result) { results <- result })
+		close(done)
+	}()
+	require.Eventually(t, ctrl.daemon.Load, time.Second, time.Millisecond)
+
+	overrides := &ipc.SessionOverrides{Punctuation: "off", Model: "canary-1b"}
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "toggle", Overrides: overrides}).OK)
+	require.Equal(t, overrides, <-transcriber.applied)
+	waitForState(t, ctrl, fsm.StateRecording)
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "toggle", Overrides: overrides}).OK)
+	require.NoError(t, (<-results).Err)
+
+	// The next session without flags goes back to the configured values.
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "toggle"}).OK)
+	require.Nil(t, <-transcriber.applied)
+	waitForState(t, ctrl, fsm.StateRecording)
+	require.True(t, ctrl.Handle(ctx, ipc.Request{Command: "toggle"}).OK)
+	require.NoError(t, (<-results).Err)
+
+	cancel()
+	<-done
+}
+
+func TestRunDaemonExitsAfterIdleTimeout(t *testing.T) {
+	ctrl := NewController(nil, &fakeTranscriber{}, nil, &fakeIndicator{})
+
+	done := make(chan struct{})
+	go func() {
+		ctrl.RunDaemon(context.Background(), 30*time.Millisecond, nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("daemon did not exit after idle timeout")
+	}
+
+	resp := ctrl.Handle(context.Background(), ipc.Request{Command: "toggle"})
+	require.False(t, resp.OK)
+	require.Contains(t, resp.Error, "cannot toggle from state idle")
+}
+
 func TestResultTimestampsAdvance(t *testing.T) {
 	ctrl := NewController(nil, &fakeTranscriber{transcript: "ok"}, nil, &fakeIndicator{})
 
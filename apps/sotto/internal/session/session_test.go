@@ -1,8 +1,11 @@
 package session
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -12,45 +15,102 @@ import (
 )
 
 type fakeIndicator struct {
+	transcribing atomic.Int32
 	stopCues     atomic.Int32
 	completeCues atomic.Int32
 	cancelCues   atomic.Int32
+	fallbackCues atomic.Int32
 }
 
-func (*fakeIndicator) ShowRecording(context.Context)     {}
-func (*fakeIndicator) ShowTranscribing(context.Context)  {}
-func (*fakeIndicator) ShowError(context.Context, string) {}
-func (f *fakeIndicator) CueStop(context.Context)         { f.stopCues.Add(1) }
-func (f *fakeIndicator) CueComplete(context.Context)     { f.completeCues.Add(1) }
-func (f *fakeIndicator) CueCancel(context.Context)       { f.cancelCues.Add(1) }
-func (*fakeIndicator) Hide(context.Context)              {}
-func (*fakeIndicator) FocusedMonitor() string            { return "DP-1" }
+func (*fakeIndicator) ShowRecording(context.Context)      {}
+func (f *fakeIndicator) ShowTranscribing(context.Context) { f.transcribing.Add(1) }
+func (*fakeIndicator) ShowError(context.Context, string)  {}
+func (f *fakeIndicator) CueStop(context.Context)          { f.stopCues.Add(1) }
+func (f *fakeIndicator) CueComplete(context.Context)      { f.completeCues.Add(1) }
+func (f *fakeIndicator) CueCancel(context.Context)        { f.cancelCues.Add(1) }
+func (f *fakeIndicator) CueFallback(context.Context)      { f.fallbackCues.Add(1) }
+func (*fakeIndicator) Hide(context.Context)               {}
+func (*fakeIndicator) FocusedMonitor() string             { return "DP-1" }
 
 type fakeTranscriber struct {
 	startErr    error
 	transcript  string
 	stopErr     error
+	fallback    bool
+	stopDelay   time.Duration
 	cancelCalls atomic.Int32
+	sessionID   string
 }
 
-func (f *fakeTranscriber) Start(context.Context) error {
+func (f *fakeTranscriber) Start(ctx context.Context) error {
+	f.sessionID = IDFromContext(ctx)
 	return f.startErr
 }
 
 func (f *fakeTranscriber) StopAndTranscribe(context.Context) (StopResult, error) {
+	time.Sleep(f.stopDelay)
 	return StopResult{
 		Transcript:    f.transcript,
 		AudioDevice:   "test mic",
 		BytesCaptured: 3200,
 		GRPCLatency:   200 * time.Millisecond,
+		RequestID:     "req-1",
 	}, f.stopErr
 }
 
+func (f *fakeTranscriber) UsingFallbackDevice() bool {
+	return f.fallback
+}
+
 func (f *fakeTranscriber) Cancel(context.Context) error {
 	f.cancelCalls.Add(1)
 	return nil
 }
 
+// progressTranscriber reports fixed live progress for the partial command.
+type progressTranscriber struct {
+	*fakeTranscriber
+}
+
+func (progressTranscriber) Progress() (string, int64) { return "live words", 1600 }
+
+func TestControllerPartialReportsLiveProgress(t *testing.T) {
+	ctrl := NewController(nil, progressTranscriber{&fakeTranscriber{}}, nil, &fakeIndicator{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resp := ctrl.Handle(ctx, ipc.Request{Command: "partial"})
+	if !resp.OK || resp.Message != "" || resp.Bytes != 0 || resp.ElapsedMS != 0 {
+		t.Fatalf("expected empty progress before recording, got %+v", resp)
+	}
+
+	resultCh := make(chan Result, 1)
+	go func() {
+		resultCh <- ctrl.Run(ctx)
+	}()
+	waitForState(t, ctrl, fsm.StateRecording)
+	time.Sleep(20 * time.Millisecond)
+
+	resp = ctrl.Handle(ctx, ipc.Request{Command: "partial"})
+	if !resp.OK || resp.State != string(fsm.StateRecording) {
+		t.Fatalf("expected recording progress, got %+v", resp)
+	}
+	if resp.Message != "live words" || resp.Bytes != 1600 {
+		t.Fatalf("unexpected progress: message=%q bytes=%d", resp.Message, resp.Bytes)
+	}
+	if resp.ElapsedMS < 20 {
+		t.Fatalf("expected elapsed >= 20ms, got %d", resp.ElapsedMS)
+	}
+
+	ctrl.Handle(ctx, ipc.Request{Command: "cancel"})
+	<-resultCh
+	resp = ctrl.Handle(ctx, ipc.Request{Command: "partial"})
+	if resp.ElapsedMS != 0 || resp.Message != "" {
+		t.Fatalf("expected progress cleared after cancel, got %+v", resp)
+	}
+}
+
 func TestControllerCancel(t *testing.T) {
 	transcriber := &fakeTranscriber{}
 	ind := &fakeIndicator{}
@@ -91,6 +151,77 @@ func TestControllerCancel(t *testing.T) {
 	}
 }
 
+// slowCollectTranscriber blocks StopAndTranscribe until its context ends,
+// like a Riva collect that never returns.
+type slowCollectTranscriber struct {
+	fakeTranscriber
+	collecting chan struct{}
+}
+
+func (s *slowCollectTranscriber) StopAndTranscribe(ctx context.Context) (StopResult, error) {
+	close(s.collecting)
+	select {
+	case <-ctx.Done():
+		return StopResult{AudioDevice: "test mic", BytesCaptured: 3200}, ctx.Err()
+	case <-time.After(5 * time.Second):
+		return StopResult{Transcript: "too late"}, nil
+	}
+}
+
+func TestControllerCancelAbortsInFlightTranscription(t *testing.T) {
+	transcriber := &slowCollectTranscriber{collecting: make(chan struct{})}
+	ind := &fakeIndicator{}
+	var committed atomic.Bool
+	ctrl := NewController(nil, transcriber, CommitFunc(func(context.Context, string) error {
+		committed.Store(true)
+		return nil
+	}), ind)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resultCh := make(chan Result, 1)
+	go func() {
+		resultCh <- ctrl.Run(ctx)
+	}()
+
+	waitForState(t, ctrl, fsm.StateRecording)
+	if resp := ctrl.Handle(ctx, ipc.Request{Command: "stop"}); !resp.OK {
+		t.Fatalf("stop response not OK: %+v", resp)
+	}
+	<-transcriber.collecting
+
+	resp := ctrl.Handle(ctx, ipc.Request{Command: "cancel"})
+	if !resp.OK || resp.Message != "cancel requested" {
+		t.Fatalf("cancel while transcribing = %+v, want cancel requested", resp)
+	}
+
+	var result Result
+	select {
+	case result = <-resultCh:
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not interrupt the in-flight collect")
+	}
+	if !result.Cancelled || result.Err != nil {
+		t.Fatalf("expected cancelled result without error, got %+v", result)
+	}
+	if result.AudioDevice != "test mic" {
+		t.Fatalf("AudioDevice = %q, want test mic", result.AudioDevice)
+	}
+	if state := ctrl.State(); state != fsm.StateIdle {
+		t.Fatalf("expected idle state after cancel, got %s", state)
+	}
+	if committed.Load() {
+		t.Fatal("expected no commit after cancelling transcription")
+	}
+	if ind.cancelCues.Load() != 1 {
+		t.Fatalf("cancel cues = %d, want 1", ind.cancelCues.Load())
+	}
+	if ind.completeCues.Load() != 0 {
+		t.Fatal("expected no complete cue after cancelling transcription")
+	}
+}
+
 func TestControllerStopCommitsTranscript(t *testing.T) {
 	var committed atomic.Bool
 	ind := &fakeIndicator{}
@@ -131,6 +262,9 @@ func TestControllerStopCommitsTranscript(t *testing.T) {
 	if result.BytesCaptured != 3200 {
 		t.Fatalf("unexpected bytes captured: %d", result.BytesCaptured)
 	}
+	if result.RequestID != "req-1" {
+		t.Fatalf("unexpected request id: %q", result.RequestID)
+	}
 	if !committed.Load() {
 		t.Fatalf("expected committer to run")
 	}
@@ -145,6 +279,159 @@ func TestControllerStopCommitsTranscript(t *testing.T) {
 	}
 }
 
+func TestControllerRunTagsSessionID(t *testing.T) {
+	var logs bytes.Buffer
+	transcriber := &fakeTranscriber{transcript: "hello world"}
+	ctrl := NewController(
+		slog.New(slog.NewTextHandler(&logs, nil)),
+		transcriber,
+		CommitFunc(func(context.Context, string) error { return nil }),
+		&fakeIndicator{},
+	)
+	// The max-recording stop logs a warning from inside the run.
+	ctrl.SetMaxRecording(10 * time.Millisecond)
+
+	first := ctrl.Run(context.Background())
+	if first.Err != nil {
+		t.Fatalf("unexpected result error: %v", first.Err)
+	}
+	if first.ID == "" {
+		t.Fatalf("expected a session ID on the result")
+	}
+	if transcriber.sessionID != first.ID {
+		t.Fatalf("transcriber saw session ID %q, want %q", transcriber.sessionID, first.ID)
+	}
+	if !strings.Contains(logs.String(), "session_id="+first.ID) {
+		t.Fatalf("expected session ID in logs, got %q", logs.String())
+	}
+
+	second := ctrl.Run(context.Background())
+	if second.ID == "" || second.ID == first.ID {
+		t.Fatalf("expected a fresh session ID, got %q after %q", second.ID, first.ID)
+	}
+}
+
+type recordingCommitter struct {
+	commits       atomic.Int32
+	clipboardOnly atomic.Int32
+}
+
+func (r *recordingCommitter) Commit(context.Context, string) error {
+	r.commits.Add(1)
+	return nil
+}
+
+func (r *recordingCommitter) CommitClipboardOnly(context.Context, string) error {
+	r.clipboardOnly.Add(1)
+	return nil
+}
+
+func TestControllerTranscribingDelaySkipsIndicatorForFastTranscription(t *testing.T) {
+	tests := []struct {
+		name      string
+		delay     time.Duration
+		stopDelay time.Duration
+		want      int32
+	}{
+		{name: "no delay shows immediately", want: 1},
+		{name: "fast transcription skips indicator", delay: time.Second, want: 0},
+		{name: "slow transcription shows indicator", delay: 10 * time.Millisecond, stopDelay: 200 * time.Millisecond, want: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ind := &fakeIndicator{}
+			ctrl := NewController(nil, &fakeTranscriber{transcript: "ok", stopDelay: tc.stopDelay}, nil, ind)
+			ctrl.SetTranscribingDelay(tc.delay)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			resultCh := make(chan Result, 1)
+			go func() {
+				resultCh <- ctrl.Run(ctx)
+			}()
+
+			waitForState(t, ctrl, fsm.StateRecording)
+			if resp := ctrl.Handle(ctx, ipc.Request{Command: "stop"}); !resp.OK {
+				t.Fatalf("stop response not OK: %+v", resp)
+			}
+			if result := <-resultCh; result.Err != nil {
+				t.Fatalf("unexpected result error: %v", result.Err)
+			}
+			if got := ind.transcribing.Load(); got != tc.want {
+				t.Fatalf("ShowTranscribing calls = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestControllerStopNoPasteUsesClipboardOnlyCommit(t *testing.T) {
+	for _, noPaste := range []bool{false, true} {
+		committer := &recordingCommitter{}
+		ctrl := NewController(nil, &fakeTranscriber{transcript: "ok"}, committer, &fakeIndicator{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resultCh := make(chan Result, 1)
+		go func() {
+			resultCh <- ctrl.Run(ctx)
+		}()
+
+		waitForState(t, ctrl, fsm.StateRecording)
+		resp := ctrl.Handle(ctx, ipc.Request{Command: "stop", NoPaste: noPaste})
+		if !resp.OK {
+			t.Fatalf("stop response not OK: %+v", resp)
+		}
+		result := <-resultCh
+		cancel()
+		if result.Err != nil {
+			t.Fatalf("unexpected result error: %v", result.Err)
+		}
+
+		wantClipboardOnly := int32(0)
+		if noPaste {
+			wantClipboardOnly = 1
+		}
+		if got := committer.clipboardOnly.Load(); got != wantClipboardOnly {
+			t.Fatalf("noPaste=%v: expected %d clipboard-only commits, got %d", noPaste, wantClipboardOnly, got)
+		}
+		if got := committer.commits.Load(); got != 1-wantClipboardOnly {
+			t.Fatalf("noPaste=%v: expected %d regular commits, got %d", noPaste, 1-wantClipboardOnly, got)
+		}
+	}
+}
+
+func TestControllerCuesFallbackOnlyWhenDeviceFellBack(t *testing.T) {
+	for _, fallback := range []bool{false, true} {
+		ind := &fakeIndicator{}
+		ctrl := NewController(nil, &fakeTranscriber{transcript: "ok", fallback: fallback}, nil, ind)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resultCh := make(chan Result, 1)
+		go func() {
+			resultCh <- ctrl.Run(ctx)
+		}()
+
+		waitForState(t, ctrl, fsm.StateRecording)
+		resp := ctrl.Handle(ctx, ipc.Request{Command: "stop"})
+		if !resp.OK {
+			t.Fatalf("stop response not OK: %+v", resp)
+		}
+		result := <-resultCh
+		cancel()
+		if result.Err != nil {
+			t.Fatalf("unexpected result error: %v", result.Err)
+		}
+
+		want := int32(0)
+		if fallback {
+			want = 1
+		}
+		if got := ind.fallbackCues.Load(); got != want {
+			t.Fatalf("fallback=%v: expected %d fallback cues, got %d", fallback, want, got)
+		}
+	}
+}
+
 func TestControllerStopPipelineError(t *testing.T) {
 	ind := &fakeIndicator{}
 	ctrl := NewController(nil, &fakeTranscriber{stopErr: ErrPipelineUnavailable}, nil, ind)
@@ -178,6 +465,115 @@ func TestControllerStopPipelineError(t *testing.T) {
 	}
 }
 
+func TestControllerStopWithNoAudio(t *testing.T) {
+	for _, asError := range []bool{false, true} {
+		ind := &fakeIndicator{}
+		ctrl := NewController(nil, &fakeTranscriber{stopErr: ErrNoAudioCaptured}, nil, ind)
+		ctrl.SetNoAudioAsError(asError)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resultCh := make(chan Result, 1)
+		go func() {
+			resultCh <- ctrl.Run(ctx)
+		}()
+
+		waitForState(t, ctrl, fsm.StateRecording)
+		resp := ctrl.Handle(ctx, ipc.Request{Command: "toggle"})
+		if !resp.OK {
+			t.Fatalf("toggle response not OK: %+v", resp)
+		}
+
+		result := <-resultCh
+		cancel()
+		if state := ctrl.State(); state != fsm.StateIdle {
+			t.Fatalf("asError=%v: expected idle after stop, got %s", asError, state)
+		}
+		if asError {
+			if !errors.Is(result.Err, ErrNoAudioCaptured) {
+				t.Fatalf("unexpected result error: %v", result.Err)
+			}
+			if result.Cancelled {
+				t.Fatalf("did not expect cancelled result in error mode")
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Fatalf("expected silent cancel, got error: %v", result.Err)
+		}
+		if !result.Cancelled {
+			t.Fatalf("expected cancelled result when no audio was captured")
+		}
+		if result.BytesCaptured != 3200 {
+			t.Fatalf("expected bytes captured in cancelled result, got %d", result.BytesCaptured)
+		}
+		if ind.cancelCues.Load() != 1 || ind.stopCues.Load() != 0 {
+			t.Fatalf("expected cancel cue only, got cancel=%d stop=%d", ind.cancelCues.Load(), ind.stopCues.Load())
+		}
+	}
+}
+
+func TestControllerMinCommitChars(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		wantCommit bool
+	}{
+		{name: "below threshold", transcript: "  uh \n"},
+		{name: "at threshold", transcript: " ok. ", wantCommit: true},
+		{name: "counts characters not bytes", transcript: "café", wantCommit: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var committed atomic.Bool
+			ind := &fakeIndicator{}
+			ctrl := NewController(
+				nil,
+				&fakeTranscriber{transcript: tc.transcript},
+				CommitFunc(func(context.Context, string) error {
+					committed.Store(true)
+					return nil
+				}),
+				ind,
+			)
+			ctrl.SetMinCommitChars(3)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			resultCh := make(chan Result, 1)
+			go func() {
+				resultCh <- ctrl.Run(ctx)
+			}()
+
+			waitForState(t, ctrl, fsm.StateRecording)
+			if resp := ctrl.Handle(ctx, ipc.Request{Command: "stop"}); !resp.OK {
+				t.Fatalf("stop response not OK: %+v", resp)
+			}
+
+			result := <-resultCh
+			if committed.Load() != tc.wantCommit {
+				t.Fatalf("committed = %v, want %v", committed.Load(), tc.wantCommit)
+			}
+			if tc.wantCommit {
+				if result.Err != nil {
+					t.Fatalf("unexpected result error: %v", result.Err)
+				}
+				return
+			}
+			if !errors.Is(result.Err, ErrTranscriptTooShort) || !errors.Is(result.Err, ErrEmptyTranscript) {
+				t.Fatalf("expected short transcript error matching ErrEmptyTranscript, got %v", result.Err)
+			}
+			if state := ctrl.State(); state != fsm.StateIdle {
+				t.Fatalf("expected idle after short transcript reset, got %s", state)
+			}
+			if ind.completeCues.Load() != 0 {
+				t.Fatalf("did not expect complete cue on short transcript")
+			}
+		})
+	}
+}
+
 func TestControllerStopEmptyTranscriptReturnsError(t *testing.T) {
 	var committed atomic.Bool
 	ind := &fakeIndicator{}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/rbright/sotto/internal/ipc"
 )
 
 var (
@@ -11,14 +13,59 @@ var (
 	ErrPipelineUnavailable = errors.New("audio capture and ASR pipeline not implemented")
 	// ErrEmptyTranscript indicates stop completed but no usable speech was recognized.
 	ErrEmptyTranscript = errors.New("no speech recognized; check microphone input or mute state")
+	// ErrNoAudioCaptured indicates stop arrived before any audio was captured,
+	// e.g. an immediate toggle-toggle.
+	ErrNoAudioCaptured = errors.New("stopped before any audio was captured")
+	// ErrRecoverable marks a Start failure that happened before any audio was captured.
+	ErrRecoverable = errors.New("recoverable transcriber start failure")
+	// ErrTranscriptTooShort indicates the transcript was shorter than the
+	// minimum commit length. It matches ErrEmptyTranscript so callers treat
+	// both the same way.
+	ErrTranscriptTooShort error = shortTranscriptError{}
 )
 
+// shortTranscriptError is ErrTranscriptTooShort; Is ties it to ErrEmptyTranscript.
+type shortTranscriptError struct{}
+
+func (shortTranscriptError) Error() string {
+	return "transcript shorter than transcript.min_commit_chars; not committed"
+}
+func (shortTranscriptError) Is(target error) bool { return target == ErrEmptyTranscript }
+
+// recoverableError tags an error as ErrRecoverable without changing its message.
+type recoverableError struct {
+	err error
+}
+
+func (e recoverableError) Error() string        { return e.err.Error() }
+func (e recoverableError) Unwrap() error        { return e.err }
+func (e recoverableError) Is(target error) bool { return target == ErrRecoverable }
+
+// Recoverable marks err as safe for the controller to retry once via fsm.EventRetry.
+func Recoverable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return recoverableError{err: err}
+}
+
 // StopResult is the transcriber output consumed by the session controller.
 type StopResult struct {
 	Transcript    string
 	AudioDevice   string
 	BytesCaptured int64
 	GRPCLatency   time.Duration
+	// Model and LanguageCode are the ASR settings that produced the transcript.
+	Model        string
+	LanguageCode string
+	// CommittedSegments are finalized ASR segments before assembly.
+	CommittedSegments []string
+	// InterimTail is the trailing tentative hypothesis appended after CommittedSegments.
+	InterimTail string
+	// RequestID and ModelVersion are diagnostic ids from the ASR server's
+	// response trailers; empty when it sends none.
+	RequestID    string
+	ModelVersion string
 }
 
 // Transcriber abstracts capture/ASR operations needed by session orchestration.
@@ -28,6 +75,60 @@ type Transcriber interface {
 	Cancel(context.Context) error
 }
 
+// sessionConfigurer is implemented by transcribers that can apply per-session
+// overrides forwarded to a daemon owner; nil restores the configured values.
+type sessionConfigurer interface {
+	ConfigureSession(*ipc.SessionOverrides) error
+}
+
+// fallbackReporter is implemented by transcribers that can report whether
+// capture fell back to a non-primary input device.
+type fallbackReporter interface {
+	UsingFallbackDevice() bool
+}
+
+// flusher is implemented by transcribers that can hand over text finalized so
+// far while capture keeps running.
+type flusher interface {
+	Flush(context.Context) (string, error)
+}
+
+// warmReporter is implemented by transcribers that hold a pre-warmed ASR
+// connection between sessions.
+type warmReporter interface {
+	WarmStatus() string
+}
+
+// warmStatus reports the transcriber's warm state, or "cold" when it cannot
+// hold a connection.
+func warmStatus(t Transcriber) string {
+	if reporter, ok := t.(warmReporter); ok {
+		return reporter.WarmStatus()
+	}
+	return "cold"
+}
+
+// progressReporter is implemented by transcribers that can report the live
+// interim hypothesis and captured byte count while recording.
+type progressReporter interface {
+	Progress() (interim string, bytes int64)
+}
+
+// progress reports live session progress, or nothing when the transcriber
+// cannot.
+func progress(t Transcriber) (string, int64) {
+	if reporter, ok := t.(progressReporter); ok {
+		return reporter.Progress()
+	}
+	return "", 0
+}
+
+// usingFallbackDevice reports fallback capture when the transcriber supports it.
+func usingFallbackDevice(t Transcriber) bool {
+	reporter, ok := t.(fallbackReporter)
+	return ok && reporter.UsingFallbackDevice()
+}
+
 // PlaceholderTranscriber is a no-op placeholder used in tests/fallback wiring.
 type PlaceholderTranscriber struct{}
 
@@ -1,12 +1,50 @@
 // Package transcript assembles and normalizes recognized ASR segments.
 package transcript
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Trim policies select which edges of the recognized text lose their whitespace.
+const (
+	TrimBoth     = "both"
+	TrimLeading  = "leading"
+	TrimTrailing = "trailing"
+	TrimNone     = "none"
+)
+
+// Capitalization modes select which letters Assemble upper-cases.
+const (
+	CapitalizeSentences = "sentences"
+	CapitalizeFirst     = "first"
+	CapitalizeNone      = "none"
+)
 
 // Options controls transcript assembly formatting behavior.
 type Options struct {
-	TrailingSpace       bool
-	CapitalizeSentences bool
+	TrailingSpace bool
+	// TrailingNewline appends "\n" as the final step.
+	TrailingNewline bool
+	SingleLine      bool
+	SpokenDigits    bool
+	// CollapseInitialisms joins spelled-out letter runs ("A P I") that match
+	// Initialisms or reach InitialismMinLetters; see collapseInitialisms.
+	CollapseInitialisms  bool
+	Initialisms          []string
+	InitialismMinLetters int
+	// Capitalize is one of the Capitalize* modes; empty behaves like CapitalizeNone.
+	Capitalize string
+	// ContinuesSentence marks text that carries on a sentence from an earlier
+	// flush of the same session, so its first letter is not capitalized as a
+	// sentence start.
+	ContinuesSentence bool
+	// TrimPolicy is one of the Trim* constants; empty behaves like TrimBoth.
+	TrimPolicy string
+	// SymbolWords maps spoken words to literal symbols for code dictation;
+	// nil leaves words alone. See replaceSymbolWords.
+	SymbolWords map[string]string
 }
 
 // Assemble joins final ASR segments and applies configured normalization.
@@ -15,24 +53,145 @@ func Assemble(finalSegments []string, opts Options) string {
 		return ""
 	}
 
-	joined := strings.Join(finalSegments, " ")
-	normalized := strings.Join(strings.Fields(joined), " ")
+	// Truncated multibyte sequences would otherwise reach the clipboard as
+	// replacement characters.
+	joined := strings.ToValidUTF8(strings.Join(finalSegments, " "), "")
+	normalized := normalizeWhitespace(joined)
 	if normalized == "" {
 		return ""
 	}
 
-	if opts.CapitalizeSentences {
-		normalized = capitalizeSentences(normalized)
+	if opts.SpokenDigits {
+		normalized = perLine(normalized, joinSpokenDigits)
+	}
+	if opts.CollapseInitialisms {
+		known := initialismSet(opts.Initialisms)
+		normalized = perLine(normalized, func(line string) string {
+			return collapseInitialisms(line, known, opts.InitialismMinLetters)
+		})
+	}
+	if len(opts.SymbolWords) > 0 {
+		normalized = perLine(normalized, func(line string) string {
+			return replaceSymbolWords(line, opts.SymbolWords, opts.SpokenDigits)
+		})
+	}
+
+	switch opts.Capitalize {
+	case CapitalizeSentences:
+		normalized = capitalizePronounI(capitalizeSentenceStarts(normalized, !opts.ContinuesSentence))
+	case CapitalizeFirst:
+		if !opts.ContinuesSentence {
+			normalized = capitalizeFirstLetter(normalized)
+		}
+		normalized = capitalizePronounI(normalized)
+	}
+
+	if opts.SingleLine {
+		normalized = singleLine(normalized)
+	}
+
+	// An edge the policy keeps retains a single space when the recognized
+	// text had whitespace there.
+	leading, trailing := edgeWhitespace(finalSegments)
+	if trailing && (opts.TrimPolicy == TrimLeading || opts.TrimPolicy == TrimNone) {
+		normalized += " "
+	}
+	if leading && (opts.TrimPolicy == TrimTrailing || opts.TrimPolicy == TrimNone) {
+		normalized = " " + normalized
 	}
 
-	if opts.TrailingSpace {
-		return normalized + " "
+	if opts.TrailingSpace && !strings.HasSuffix(normalized, " ") {
+		normalized += " "
+	}
+	if opts.TrailingNewline && !strings.HasSuffix(normalized, "\n") {
+		normalized += "\n"
 	}
 	return normalized
 }
 
-func capitalizeSentences(text string) string {
-	text = capitalizeSentenceStarts(text)
+// edgeWhitespace reports whether the first and last non-empty segments begin
+// and end with whitespace. Empty segments are join artifacts, not spacing.
+func edgeWhitespace(segments []string) (bool, bool) {
+	var first, last string
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if first == "" {
+			first = segment
+		}
+		last = segment
+	}
+	if first == "" {
+		return false, false
+	}
+	r, _ := utf8.DecodeRuneInString(first)
+	l, _ := utf8.DecodeLastRuneInString(last)
+	return unicode.IsSpace(r), unicode.IsSpace(l)
+}
+
+// normalizeWhitespace collapses runs of spaces and tabs to one space while
+// keeping line breaks: "\r\n" and "\r" become "\n", blank lines collapse to
+// one paragraph break, whitespace around each break is dropped, and the edges
+// are trimmed.
+func normalizeWhitespace(text string) string {
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text)
+
+	var out strings.Builder
+	out.Grow(len(text))
+	blank := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank = true
+			continue
+		}
+		if out.Len() > 0 {
+			out.WriteByte('\n')
+			if blank {
+				out.WriteByte('\n')
+			}
+		}
+		blank = false
+		out.WriteString(line)
+	}
+	return out.String()
+}
+
+// perLine applies fn to each line of text so word-level passes keep line
+// breaks intact.
+func perLine(text string, fn func(string) string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = fn(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// singleLine replaces line breaks with spaces and collapses repeated whitespace
+// so submit-on-newline targets receive the transcript as one line.
+func singleLine(text string) string {
+	text = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// capitalizeFirstLetter upper-cases only the first letter of the transcript,
+// leaving later sentence starts as recognized.
+func capitalizeFirstLetter(text string) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if shouldCapitalizeWordAt(runes, i) {
+			runes[i] = unicode.ToUpper(r)
+		}
+		return string(runes)
+	}
+	return text
+}
+
+func capitalizePronounI(text string) string {
 	text = pronounIContractionPattern.ReplaceAllStringFunc(text, func(match string) string {
 		return "I" + match[1:]
 	})
@@ -2,6 +2,7 @@ package transcript
 
 import (
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/require"
 )
@@ -9,35 +10,180 @@ import (
 func TestAssembleNormalizesWhitespaceTrailingSpaceAndSentenceCase(t *testing.T) {
 	t.Parallel()
 
-	got := Assemble([]string{" hello", "world.", "\nfrom", "sotto"}, Options{
-		TrailingSpace:       true,
-		CapitalizeSentences: true,
+	got := Assemble([]string{" hello", "world.", "\tfrom", "sotto"}, Options{
+		TrailingSpace: true,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "Hello world. From sotto ", got)
 }
 
+func TestAssemblePreservesLineAndParagraphBreaks(t *testing.T) {
+	t.Parallel()
+
+	got := Assemble([]string{"first paragraph.  \n\n", " second   paragraph\n\n\n\nthird", "line\r\nnext line. done"}, Options{
+		Capitalize: CapitalizeSentences,
+	})
+	require.Equal(t, "First paragraph.\n\nSecond paragraph\n\nThird line\nnext line. Done", got)
+}
+
+func TestAssembleWordPassesKeepParagraphBreaks(t *testing.T) {
+	t.Parallel()
+
+	got := Assemble([]string{"code one two three\n\nthe A P I"}, Options{
+		SpokenDigits:         true,
+		CollapseInitialisms:  true,
+		InitialismMinLetters: 3,
+	})
+	require.Equal(t, "code 123\n\nthe API", got)
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "a b\nc\n\nd", normalizeWhitespace("\n  a \t b \n c\n \n\t\nd \n\n"))
+	require.Empty(t, normalizeWhitespace(" \r\n\t "))
+}
+
 func TestAssembleWithoutTrailingSpace(t *testing.T) {
 	t.Parallel()
 
 	got := Assemble([]string{"hello", "world"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: false,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeNone,
 	})
 	require.Equal(t, "hello world", got)
 }
 
+func TestAssembleAppendsTrailingNewline(t *testing.T) {
+	t.Parallel()
+
+	got := Assemble([]string{"hello", "world"}, Options{
+		TrailingNewline: true,
+		Capitalize:      CapitalizeSentences,
+	})
+	require.Equal(t, "Hello world\n", got)
+
+	got = Assemble([]string{"line one\n"}, Options{TrailingNewline: true, TrimPolicy: TrimNone})
+	require.Equal(t, "line one \n", got)
+	require.Empty(t, Assemble(nil, Options{TrailingNewline: true}))
+}
+
+func TestAssembleSingleLineFlattensSpokenNewlines(t *testing.T) {
+	t.Parallel()
+
+	got := Assemble([]string{"first line.\n", "\r\nsecond  line.", "new\nline third"}, Options{
+		TrailingSpace: true,
+		Capitalize:    CapitalizeSentences,
+		SingleLine:    true,
+	})
+	require.Equal(t, "First line. Second line. New line third ", got)
+	require.NotContains(t, got, "\n")
+}
+
+func TestSingleLineCollapsesWhitespace(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "a b c", singleLine("a\n\nb \r\n  c"))
+	require.Empty(t, singleLine("\n\r\n"))
+}
+
+func TestAssembleStripsInvalidUTF8(t *testing.T) {
+	t.Parallel()
+
+	got := Assemble([]string{"na\xefve", "r\xc3\xa9sum\xc3", "\xe2\x82", "done."}, Options{
+		TrailingSpace: true,
+		Capitalize:    CapitalizeSentences,
+	})
+	require.Equal(t, "Nave résum done. ", got)
+	require.True(t, utf8.ValidString(got))
+	require.NotContains(t, got, string(utf8.RuneError))
+}
+
 func TestAssembleEmptyInput(t *testing.T) {
 	t.Parallel()
 
-	require.Empty(t, Assemble(nil, Options{TrailingSpace: true, CapitalizeSentences: true}))
+	require.Empty(t, Assemble(nil, Options{TrailingSpace: true, Capitalize: CapitalizeSentences}))
+}
+
+func TestAssembleTrimPolicies(t *testing.T) {
+	t.Parallel()
+
+	segments := []string{" hello", "world\n"}
+	tests := []struct {
+		policy        string
+		trailingSpace bool
+		want          string
+	}{
+		{policy: "", want: "hello world"},
+		{policy: TrimBoth, want: "hello world"},
+		{policy: TrimLeading, want: "hello world "},
+		{policy: TrimTrailing, want: " hello world"},
+		{policy: TrimNone, want: " hello world "},
+		{policy: TrimBoth, trailingSpace: true, want: "hello world "},
+		{policy: TrimTrailing, trailingSpace: true, want: " hello world "},
+		{policy: TrimNone, trailingSpace: true, want: " hello world "},
+	}
+	for _, tc := range tests {
+		got := Assemble(segments, Options{TrimPolicy: tc.policy, TrailingSpace: tc.trailingSpace})
+		require.Equal(t, tc.want, got, "policy %q trailing_space=%v", tc.policy, tc.trailingSpace)
+	}
+
+	require.Equal(t, "hello", Assemble([]string{"", "hello", ""}, Options{TrimPolicy: TrimNone}))
+	require.Empty(t, Assemble([]string{" ", "\n"}, Options{TrimPolicy: TrimNone}))
+}
+
+func TestAssembleCapitalizeModes(t *testing.T) {
+	t.Parallel()
+
+	segments := []string{"so i think we ship it.", "then i'm off. bye"}
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: CapitalizeSentences, want: "So I think we ship it. Then I'm off. Bye"},
+		{mode: CapitalizeFirst, want: "So I think we ship it. then I'm off. bye"},
+		{mode: CapitalizeNone, want: "so i think we ship it. then i'm off. bye"},
+		{mode: "", want: "so i think we ship it. then i'm off. bye"},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.want, Assemble(segments, Options{Capitalize: tc.mode}), "mode %q", tc.mode)
+	}
+
+	require.Equal(t, `"Quoted" start`, Assemble([]string{`"quoted" start`}, Options{Capitalize: CapitalizeFirst}))
+	require.Equal(t, "i.e. a leading abbreviation", Assemble([]string{"i.e. a leading abbreviation"}, Options{Capitalize: CapitalizeFirst}))
+}
+
+func TestAssembleContinuesSentenceAcrossFlushes(t *testing.T) {
+	t.Parallel()
+
+	segments := []string{"and then i left. bye"}
+	require.Equal(t, "and then I left. Bye", Assemble(segments, Options{Capitalize: CapitalizeSentences, ContinuesSentence: true}))
+	require.Equal(t, "and then I left. bye", Assemble(segments, Options{Capitalize: CapitalizeFirst, ContinuesSentence: true}))
+}
+
+func TestEndsSentence(t *testing.T) {
+	t.Parallel()
+
+	for text, want := range map[string]bool{
+		"We shipped it. ":        true,
+		"Did it work?\n":         true,
+		`He said "stop!"`:        true,
+		"and then":               false,
+		"ask Dr.":                false,
+		"the total was 3.":       true,
+		"":                       false,
+		"see the appendix, e.g.": false,
+	} {
+		require.Equal(t, want, EndsSentence(text), "%q", text)
+	}
 }
 
 func TestAssembleSkipsWhitespaceOnlySegments(t *testing.T) {
 	t.Parallel()
 
 	got := Assemble([]string{"  ", "\n\t", "hello"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "Hello", got)
 }
@@ -46,8 +192,8 @@ func TestAssembleSentenceCaseCapitalizesPronounI(t *testing.T) {
 	t.Parallel()
 
 	got := Assemble([]string{"when i speak i'm clearer. i think i will keep using it."}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "When I speak I'm clearer. I think I will keep using it.", got)
 }
@@ -56,8 +202,8 @@ func TestAssembleSentenceCaseDoesNotCapitalizeDomainOrDecimalFragments(t *testin
 	t.Parallel()
 
 	got := Assemble([]string{"check example.com and v2.1 first. then reply"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "Check example.com and v2.1 first. Then reply", got)
 }
@@ -158,8 +304,8 @@ func TestAssembleSentenceCaseAbbreviationRegressionCorpus(t *testing.T) {
 			t.Parallel()
 
 			got := Assemble([]string{tc.in}, Options{
-				TrailingSpace:       false,
-				CapitalizeSentences: true,
+				TrailingSpace: false,
+				Capitalize:    CapitalizeSentences,
 			})
 			require.Equal(t, tc.want, got)
 		})
@@ -170,8 +316,8 @@ func TestAssembleSentenceCaseDoesNotCapitalizeAfterCommonAbbreviations(t *testin
 	t.Parallel()
 
 	got := Assemble([]string{"for i.e. this case and e.g. that case. then proceed"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "For i.e. this case and e.g. that case. Then proceed", got)
 }
@@ -180,8 +326,8 @@ func TestAssembleSentenceCaseKeepsPronounIDistinctFromIEAbbreviation(t *testing.
 	t.Parallel()
 
 	got := Assemble([]string{"i said i.e. this should stay lowercase"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "I said i.e. this should stay lowercase", got)
 }
@@ -190,8 +336,8 @@ func TestAssembleSentenceCaseKeepsLeadingIEAbbreviationLowercase(t *testing.T) {
 	t.Parallel()
 
 	got := Assemble([]string{"i.e. this should stay lowercase"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "i.e. this should stay lowercase", got)
 }
@@ -200,8 +346,8 @@ func TestAssembleSentenceCaseKeepsPostBoundaryIEAbbreviationLowercase(t *testing
 	t.Parallel()
 
 	got := Assemble([]string{"this is true. i.e. this should stay lowercase"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "This is true. i.e. this should stay lowercase", got)
 }
@@ -210,8 +356,8 @@ func TestAssembleSentenceCaseCapitalizesTitleAbbreviationAtSentenceStart(t *test
 	t.Parallel()
 
 	got := Assemble([]string{"dr. smith can help"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "Dr. smith can help", got)
 }
@@ -220,8 +366,8 @@ func TestAssembleSentenceCaseCapitalizesTitleAbbreviationAfterBoundary(t *testin
 	t.Parallel()
 
 	got := Assemble([]string{"this happened. dr. smith replied"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "This happened. Dr. smith replied", got)
 }
@@ -230,8 +376,8 @@ func TestAssembleSentenceCaseDoesNotCapitalizeAfterInitialismAbbreviation(t *tes
 	t.Parallel()
 
 	got := Assemble([]string{"in the u.s. government report. then we continue"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "In the u.s. government report. Then we continue", got)
 }
@@ -240,8 +386,8 @@ func TestAssembleSentenceCaseHandlesQuoteAfterBoundary(t *testing.T) {
 	t.Parallel()
 
 	got := Assemble([]string{"he said. \"hello there\" and left."}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "He said. \"Hello there\" and left.", got)
 }
@@ -250,8 +396,8 @@ func TestAssembleSentenceCaseLeadingBoundaryDoesNotDoubleCapitalize(t *testing.T
 	t.Parallel()
 
 	got := Assemble([]string{"2. hello there"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, "2. Hello there", got)
 }
@@ -260,12 +406,12 @@ func TestAssembleIdempotentForNormalizedOutput(t *testing.T) {
 	t.Parallel()
 
 	first := Assemble([]string{"hello world. this is sotto"}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	second := Assemble([]string{first}, Options{
-		TrailingSpace:       false,
-		CapitalizeSentences: true,
+		TrailingSpace: false,
+		Capitalize:    CapitalizeSentences,
 	})
 	require.Equal(t, first, second)
 }
@@ -377,8 +377,7 @@ func looksLikeInitialismToken(token string) bool {
 	}
 
 	for _, part := range parts {
-		runes := []rune(part)
-		if len(runes) != 1 || !unicode.IsLetter(runes[0]) {
+		if !isSingleLetterToken(part) {
 			return false
 		}
 	}
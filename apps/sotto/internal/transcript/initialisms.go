@@ -0,0 +1,68 @@
+package transcript
+
+import (
+	"strings"
+	"unicode"
+)
+
+// collapseInitialisms joins runs of two or more single-letter tokens ("A P I")
+// into one initialism ("API"). A run collapses when its letters, upper-cased,
+// match an entry in known, or when it is at least minLetters long and every
+// letter was recognized upper-case; minLetters <= 0 collapses known entries
+// only. Trailing punctuation on the last letter ends the run and is kept;
+// punctuation mid-run breaks it. Dotted initialisms ("U.S.") are one token and
+// left to the sentence boundary classifier.
+func collapseInitialisms(text string, known map[string]struct{}, minLetters int) string {
+	tokens := strings.Fields(text)
+	out := make([]string, 0, len(tokens))
+
+	for i := 0; i < len(tokens); {
+		var letters strings.Builder
+		allUpper := true
+		trailing := ""
+		j := i
+		for j < len(tokens) {
+			core, punct := splitTrailingPunctuation(tokens[j])
+			if !isSingleLetterToken(core) {
+				break
+			}
+			letters.WriteString(core)
+			allUpper = allUpper && strings.ToUpper(core) == core
+			j++
+			if punct != "" {
+				trailing = punct
+				break
+			}
+		}
+
+		run := j - i
+		initialism := strings.ToUpper(letters.String())
+		_, listed := known[initialism]
+		if run >= 2 && (listed || (minLetters > 0 && run >= minLetters && allUpper)) {
+			out = append(out, initialism+trailing)
+			i = j
+			continue
+		}
+		out = append(out, tokens[i])
+		i++
+	}
+
+	return strings.Join(out, " ")
+}
+
+// initialismSet upper-cases configured initialisms for run lookups.
+func initialismSet(entries []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if entry = strings.ToUpper(strings.TrimSpace(entry)); entry != "" {
+			set[entry] = struct{}{}
+		}
+	}
+	return set
+}
+
+// isSingleLetterToken reports whether token is exactly one letter.
+func isSingleLetterToken(token string) bool {
+	runes := []rune(token)
+	return len(runes) == 1 && unicode.IsLetter(runes[0])
+}
@@ -0,0 +1,50 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollapseInitialisms(t *testing.T) {
+	t.Parallel()
+
+	known := initialismSet([]string{"api", "CI", " "})
+	cases := []struct {
+		name       string
+		in         string
+		minLetters int
+		want       string
+	}{
+		{name: "listed_initialism", in: "call the A P I now", minLetters: 3, want: "call the API now"},
+		{name: "listed_lowercase_run", in: "the a p i works", minLetters: 0, want: "the API works"},
+		{name: "listed_two_letters", in: "the C I job failed", minLetters: 3, want: "the CI job failed"},
+		{name: "threshold_upper_run", in: "open the H T T P port", minLetters: 3, want: "open the HTTP port"},
+		{name: "trailing_punctuation_kept", in: "check the U R L.", minLetters: 3, want: "check the URL."},
+		{name: "unlisted_pair_left_alone", in: "plan B C next", minLetters: 3, want: "plan B C next"},
+		{name: "lowercase_unlisted_left_alone", in: "x y z coordinates", minLetters: 3, want: "x y z coordinates"},
+		{name: "threshold_disabled", in: "the H T T P port", minLetters: 0, want: "the H T T P port"},
+		{name: "comma_breaks_run", in: "A, P, I", minLetters: 3, want: "A, P, I"},
+		{name: "lone_letters_untouched", in: "I saw a cat and I left", minLetters: 2, want: "I saw a cat and I left"},
+		{name: "dotted_initialism_untouched", in: "the U.S. team", minLetters: 2, want: "the U.S. team"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, collapseInitialisms(tc.in, known, tc.minLetters))
+		})
+	}
+}
+
+func TestAssembleCollapseInitialismsIsOptIn(t *testing.T) {
+	t.Parallel()
+
+	segments := []string{"the A P I is up. a test I ran"}
+	require.Equal(t, "The A P I is up. A test I ran", Assemble(segments, Options{Capitalize: CapitalizeSentences}))
+	require.Equal(t, "The API is up. A test I ran", Assemble(segments, Options{
+		Capitalize:           CapitalizeSentences,
+		CollapseInitialisms:  true,
+		InitialismMinLetters: 3,
+	}))
+}
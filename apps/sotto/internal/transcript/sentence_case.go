@@ -5,13 +5,15 @@ import (
 	"unicode"
 )
 
-func capitalizeSentenceStarts(text string) string {
+// capitalizeSentenceStarts upper-cases the first letter of each sentence;
+// atStart reports whether text itself begins one.
+func capitalizeSentenceStarts(text string, atStart bool) string {
 	runes := []rune(text)
 
 	var out strings.Builder
 	out.Grow(len(text))
 
-	capitalizeStart := true
+	capitalizeStart := atStart
 	pendingBoundary := false
 	sawWhitespaceAfterBoundary := false
 
@@ -60,12 +62,39 @@ func capitalizeSentenceStarts(text string) string {
 		case '!', '?':
 			pendingBoundary = true
 			sawWhitespaceAfterBoundary = false
+		case '\n':
+			// A paragraph break starts a sentence even without punctuation.
+			if i > 0 && runes[i-1] == '\n' {
+				capitalizeStart = true
+			}
 		}
 	}
 
 	return out.String()
 }
 
+// EndsSentence reports whether text ends with sentence punctuation, ignoring
+// trailing whitespace and closing quotes or brackets, so a following flush
+// starts a new sentence.
+func EndsSentence(text string) bool {
+	runes := []rune(strings.TrimRightFunc(text, unicode.IsSpace))
+	end := len(runes) - 1
+	for end >= 0 && isSentencePrefixRune(runes[end]) {
+		end--
+	}
+	if end < 0 {
+		return false
+	}
+	switch runes[end] {
+	case '!', '?':
+		return true
+	case '.':
+		return isSentenceBoundaryPeriod(runes, end)
+	default:
+		return false
+	}
+}
+
 func shouldCapitalizeWordAt(runes []rune, idx int) bool {
 	token := strings.ToLower(strings.Trim(wordTokenFromIndex(runes, idx), "."))
 	if token == "" {
@@ -0,0 +1,72 @@
+package transcript
+
+import "strings"
+
+// minSpokenDigitRun is the shortest run of single digits joined into a number.
+// Shorter runs ("one or two") are too likely to be ordinary prose.
+const minSpokenDigitRun = 3
+
+var spokenDigitWords = map[string]string{
+	"zero":  "0",
+	"one":   "1",
+	"two":   "2",
+	"three": "3",
+	"four":  "4",
+	"five":  "5",
+	"six":   "6",
+	"seven": "7",
+	"eight": "8",
+	"nine":  "9",
+}
+
+// joinSpokenDigits collapses runs of single spoken or written digits
+// ("one two three", "4 5 6") into one digit string. Trailing punctuation on
+// the last digit ends the run and is preserved; punctuation mid-run breaks it.
+func joinSpokenDigits(text string) string {
+	tokens := strings.Fields(text)
+	out := make([]string, 0, len(tokens))
+
+	for i := 0; i < len(tokens); {
+		var digits strings.Builder
+		trailing := ""
+		j := i
+		for j < len(tokens) {
+			core, punct := splitTrailingPunctuation(tokens[j])
+			digit, ok := singleDigit(core)
+			if !ok {
+				break
+			}
+			digits.WriteString(digit)
+			j++
+			if punct != "" {
+				trailing = punct
+				break
+			}
+		}
+
+		if j-i >= minSpokenDigitRun {
+			out = append(out, digits.String()+trailing)
+			i = j
+			continue
+		}
+		out = append(out, tokens[i])
+		i++
+	}
+
+	return strings.Join(out, " ")
+}
+
+// singleDigit maps a spoken digit word or a one-character numeral to its digit.
+func singleDigit(token string) (string, bool) {
+	if len(token) == 1 && token[0] >= '0' && token[0] <= '9' {
+		return token, true
+	}
+	digit, ok := spokenDigitWords[strings.ToLower(token)]
+	return digit, ok
+}
+
+// splitTrailingPunctuation separates sentence punctuation from the end of token.
+func splitTrailingPunctuation(token string) (string, string) {
+	core := strings.TrimRight(token, ".,!?;:")
+	return core, token[len(core):]
+}
@@ -0,0 +1,46 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinSpokenDigits(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "pure_spoken_sequence", in: "one two three", want: "123"},
+		{name: "phone_number_in_sentence", in: "call me at five five five one two one two.", want: "call me at 5551212."},
+		{name: "written_single_digits", in: "code 4 0 9 now", want: "code 409 now"},
+		{name: "mixed_words_and_numerals", in: "pin One 2 three", want: "pin 123"},
+		{name: "short_run_left_alone", in: "pick one or two options", want: "pick one or two options"},
+		{name: "pair_left_alone", in: "one two buckle my shoe", want: "one two buckle my shoe"},
+		{name: "multi_digit_numbers_left_alone", in: "12 34 56", want: "12 34 56"},
+		{name: "comma_breaks_run", in: "one, two, three", want: "one, two, three"},
+		{name: "prose_without_digits", in: "someone won the race", want: "someone won the race"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, joinSpokenDigits(tc.in))
+		})
+	}
+}
+
+func TestAssembleSpokenDigitsRunsBeforeSentenceCase(t *testing.T) {
+	t.Parallel()
+
+	got := Assemble([]string{"the code is one two three four. two apples"}, Options{
+		Capitalize:   CapitalizeSentences,
+		SpokenDigits: true,
+	})
+	require.Equal(t, "The code is 1234. Two apples", got)
+
+	require.Equal(t, "one two three", Assemble([]string{"one two three"}, Options{}))
+}
@@ -0,0 +1,46 @@
+package transcript
+
+import "strings"
+
+// replaceSymbolWords swaps spoken symbol words ("dot") for their symbols and
+// joins them to the neighboring words, so "foo dot bar" becomes "foo.bar".
+// Punctuation the recognizer attached to a symbol word is dropped. With
+// spokenDigits, single digit words glued to a symbol become numerals, so
+// "one dot two" becomes "1.2" even though the run is too short for
+// joinSpokenDigits.
+func replaceSymbolWords(text string, symbols map[string]string, spokenDigits bool) string {
+	tokens := strings.Fields(text)
+	replaced := make([]string, len(tokens))
+	isSymbol := make([]bool, len(tokens))
+	for i, token := range tokens {
+		core, _ := splitTrailingPunctuation(token)
+		if symbol, ok := symbols[strings.ToLower(core)]; ok && core != "" {
+			replaced[i] = symbol
+			isSymbol[i] = true
+		}
+	}
+
+	var out strings.Builder
+	out.Grow(len(text))
+	glue := true
+	for i, token := range tokens {
+		if isSymbol[i] {
+			out.WriteString(replaced[i])
+			glue = true
+			continue
+		}
+		nextSymbol := i+1 < len(tokens) && isSymbol[i+1]
+		if spokenDigits && (glue && i > 0 || nextSymbol) {
+			core, punct := splitTrailingPunctuation(token)
+			if digit, ok := singleDigit(core); ok {
+				token = digit + punct
+			}
+		}
+		if !glue {
+			out.WriteByte(' ')
+		}
+		out.WriteString(token)
+		glue = false
+	}
+	return out.String()
+}
@@ -0,0 +1,47 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSymbolWords = map[string]string{"dot": ".", "dash": "-", "underscore": "_", "slash": "/"}
+
+func TestReplaceSymbolWords(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		in           string
+		spokenDigits bool
+		want         string
+	}{
+		{name: "joins_neighbors", in: "foo dot bar", want: "foo.bar"},
+		{name: "chained_symbols", in: "snake underscore case dash two", want: "snake_case-two"},
+		{name: "case_insensitive", in: "Foo Dot bar", want: "Foo.bar"},
+		{name: "leading_symbol", in: "dot env file", want: ".env file"},
+		{name: "drops_recognizer_punctuation", in: "src slash, main dot go.", want: "src/main.go."},
+		{name: "words_without_symbols_untouched", in: "a dotted line", want: "a dotted line"},
+		{name: "digits_stay_words_without_spoken_digits", in: "one dot two", want: "one.two"},
+		{name: "spoken_digits_glued_to_symbols", in: "version one dot two", spokenDigits: true, want: "version 1.2"},
+		{name: "spoken_digits_not_adjacent_left_alone", in: "one file dot go", spokenDigits: true, want: "one file.go"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, replaceSymbolWords(tc.in, testSymbolWords, tc.spokenDigits))
+		})
+	}
+}
+
+func TestAssembleSymbolWordsOnlyInCodeMode(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Capitalize: CapitalizeNone}
+	require.Equal(t, "foo dot bar", Assemble([]string{"foo dot bar"}, opts))
+
+	opts.SymbolWords = testSymbolWords
+	require.Equal(t, "foo.bar", Assemble([]string{"foo dot bar"}, opts))
+}
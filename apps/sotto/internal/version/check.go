@@ -0,0 +1,139 @@
+package version
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Latest fetches the newest release tag from a GitHub-style releases/latest
+// endpoint that answers with {"tag_name": "..."}.
+func Latest(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var payload struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&payload); err != nil {
+		return "", fmt.Errorf("decode release: %w", err)
+	}
+	tag := strings.TrimSpace(payload.TagName)
+	if tag == "" {
+		return "", fmt.Errorf("release has no tag_name")
+	}
+	return tag, nil
+}
+
+// Compare orders two dotted release versions by semver precedence, ignoring a
+// leading "v" and any "+build" suffix: a "-prerelease" sorts before its
+// release (1.3.0-rc1 < 1.3.0). It returns -1, 0, or 1, and ok=false when
+// either side is not numeric (e.g. a "dev" build).
+func Compare(a, b string) (result int, ok bool) {
+	left, leftPre, ok := parseRelease(a)
+	if !ok {
+		return 0, false
+	}
+	right, rightPre, ok := parseRelease(b)
+	if !ok {
+		return 0, false
+	}
+
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r int
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		switch {
+		case l < r:
+			return -1, true
+		case l > r:
+			return 1, true
+		}
+	}
+	return comparePrerelease(leftPre, rightPre), true
+}
+
+// parseRelease splits raw into its numeric release parts and its prerelease
+// identifiers, which are nil for a plain release.
+func parseRelease(raw string) ([]int, []string, bool) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	if idx := strings.IndexByte(raw, '+'); idx >= 0 {
+		raw = raw[:idx]
+	}
+	var prerelease []string
+	if idx := strings.IndexByte(raw, '-'); idx >= 0 {
+		prerelease = strings.Split(raw[idx+1:], ".")
+		raw = raw[:idx]
+	}
+	if raw == "" {
+		return nil, nil, false
+	}
+
+	parts := strings.Split(raw, ".")
+	out := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, nil, false
+		}
+		out = append(out, n)
+	}
+	return out, prerelease, true
+}
+
+// comparePrerelease orders prerelease identifiers per semver: a release (nil)
+// outranks any prerelease, numeric identifiers compare numerically and sort
+// before alphanumeric ones, and a longer list wins when the shared prefix is
+// equal.
+func comparePrerelease(left, right []string) int {
+	switch {
+	case left == nil && right == nil:
+		return 0
+	case left == nil:
+		return 1
+	case right == nil:
+		return -1
+	}
+
+	for i := 0; i < len(left) && i < len(right); i++ {
+		l, lErr := strconv.Atoi(left[i])
+		r, rErr := strconv.Atoi(right[i])
+		switch {
+		case lErr == nil && rErr == nil:
+			if c := cmp.Compare(l, r); c != 0 {
+				return c
+			}
+		case lErr == nil:
+			return -1
+		case rErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(left[i], right[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmp.Compare(len(left), len(right))
+}
@@ -1,6 +1,9 @@
 package version
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,3 +29,51 @@ func TestStringIncludesBuildMetadata(t *testing.T) {
 	require.Contains(t, got, "date=2026-02-18")
 	require.Contains(t, got, "go=")
 }
+
+func TestCompareOrdersReleaseVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.2.3", b: "v1.2.4", want: -1},
+		{a: "v1.10.0", b: "1.9.9", want: 1},
+		{a: "1.2", b: "v1.2.0", want: 0},
+		{a: "1.3.0-rc1", b: "1.3.0", want: -1},
+		{a: "v1.3.0", b: "1.3.0-rc.2", want: 1},
+		{a: "1.3.0-rc.2", b: "1.3.0-rc.10", want: -1},
+		{a: "1.3.0-alpha", b: "1.3.0-alpha.1", want: -1},
+		{a: "1.3.0-1", b: "1.3.0-alpha", want: -1},
+		{a: "1.3.0-beta", b: "1.3.0-alpha", want: 1},
+		{a: "1.3.0-rc1+build.5", b: "1.3.0-rc1", want: 0},
+		{a: "1.3.0-rc1", b: "1.2.9", want: 1},
+	}
+	for _, tc := range tests {
+		got, ok := Compare(tc.a, tc.b)
+		require.True(t, ok, "%s vs %s", tc.a, tc.b)
+		require.Equal(t, tc.want, got, "%s vs %s", tc.a, tc.b)
+	}
+
+	_, ok := Compare("dev", "1.0.0")
+	require.False(t, ok)
+}
+
+func TestLatestReadsTagName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name":"v1.4.0","name":"sotto 1.4.0"}`))
+	}))
+	defer server.Close()
+
+	tag, err := Latest(context.Background(), server.Client(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, "v1.4.0", tag)
+}
+
+func TestLatestRejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := Latest(context.Background(), server.Client(), server.URL)
+	require.ErrorContains(t, err, "unexpected status 403")
+}